@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen est renvoyée sans requête réseau tant que l'API est considérée en panne
+var ErrCircuitOpen = errors.New("circuit ouvert: API temporairement indisponible")
+
+type circuitState int
+
+const (
+	circuitClosed   circuitState = iota // fonctionnement normal
+	circuitOpen                         // échecs répétés → on échoue immédiatement
+	circuitHalfOpen                     // délai écoulé → une requête d'essai
+)
+
+// CircuitBreaker coupe les appels vers un hôte après trop d'échecs consécutifs
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	state     circuitState
+	failures  int
+	openedAt  time.Time
+	trial     bool // requête d'essai en cours (état half-open)
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+// NewCircuitBreaker crée un disjoncteur qui s'ouvre après threshold échecs
+// consécutifs et retente une requête après cooldown
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow indique si une requête peut partir
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cooldown {
+			return ErrCircuitOpen
+		}
+		cb.state = circuitHalfOpen
+		cb.trial = true
+		return nil
+	case circuitHalfOpen:
+		if cb.trial {
+			return ErrCircuitOpen // une seule requête d'essai à la fois
+		}
+		cb.trial = true
+		return nil
+	}
+
+	return nil
+}
+
+// Success referme le circuit
+func (cb *CircuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = circuitClosed
+	cb.failures = 0
+	cb.trial = false
+}
+
+// Release libère la requête d'essai sans changer l'état du circuit : une
+// requête annulée par l'appelant ne dit rien de la santé de l'API
+func (cb *CircuitBreaker) Release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.trial = false
+}
+
+// Failure comptabilise un échec et ouvre le circuit si besoin
+func (cb *CircuitBreaker) Failure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	cb.trial = false
+
+	if cb.state == circuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = circuitOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// IsOpen indique si le circuit bloque actuellement les requêtes
+func (cb *CircuitBreaker) IsOpen() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state == circuitOpen && cb.now().Sub(cb.openedAt) < cb.cooldown
+}
+
+// =====================
+// DISJONCTEURS PAR HÔTE
+// =====================
+
+var (
+	breakersMu       sync.Mutex
+	breakers         = make(map[string]*CircuitBreaker)
+	breakerThreshold = 5
+	breakerCooldown  = 30 * time.Second
+)
+
+// SetCircuitBreakerSettings change les réglages (et réinitialise les disjoncteurs existants)
+func SetCircuitBreakerSettings(threshold int, cooldown time.Duration) {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	breakerThreshold = threshold
+	breakerCooldown = cooldown
+	breakers = make(map[string]*CircuitBreaker)
+}
+
+// breakerFor retourne le disjoncteur associé à l'hôte d'une URL
+func breakerFor(rawURL string) *CircuitBreaker {
+	host := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	cb, ok := breakers[host]
+	if !ok {
+		cb = NewCircuitBreaker(breakerThreshold, breakerCooldown)
+		breakers[host] = cb
+	}
+	return cb
+}
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStats regroupe les compteurs du cache de réponses
+type CacheStats struct {
+	Hits        int // Réponse servie depuis le disque sans requête réseau
+	Revalidated int // Requête conditionnelle → 304 Not Modified
+	Misses      int // Réponse téléchargée en entier
+	StaleServed int // Réseau en échec → ancienne réponse servie
+}
+
+// cacheEntry représente une réponse stockée sur disque
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+	MaxAge       int       `json:"max_age"` // en secondes, 0 = toujours revalider
+	Body         []byte    `json:"body"`
+}
+
+// ResponseCache est un cache HTTP persistant (un fichier JSON par URL)
+type ResponseCache struct {
+	mu    sync.Mutex
+	dir   string
+	stats CacheStats
+	now   func() time.Time // remplaçable dans les tests
+}
+
+// NewResponseCache crée un cache stocké dans dir
+func NewResponseCache(dir string) *ResponseCache {
+	return &ResponseCache{
+		dir: dir,
+		now: time.Now,
+	}
+}
+
+// DefaultCacheDir retourne le dossier de cache de l'utilisateur pour l'application
+func DefaultCacheDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(base, "groupie-tracker", "http")
+}
+
+// Dir retourne le dossier du cache
+func (c *ResponseCache) Dir() string {
+	return c.dir
+}
+
+// Stats retourne une copie des compteurs
+func (c *ResponseCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Purge supprime toutes les réponses stockées et remet les compteurs à zéro
+func (c *ResponseCache) Purge() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats = CacheStats{}
+	if err := os.RemoveAll(c.dir); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path retourne le fichier associé à une URL
+func (c *ResponseCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get lit l'entrée associée à une URL (nil si absente ou illisible)
+func (c *ResponseCache) get(url string) *cacheEntry {
+	data, err := os.ReadFile(c.path(url))
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return nil
+	}
+	return &entry
+}
+
+// put écrit une entrée sur disque (écriture atomique via fichier temporaire)
+func (c *ResponseCache) put(entry *cacheEntry) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Un fichier temporaire unique par écriture : deux requêtes concurrentes
+	// sur la même URL ne s'écrasent pas avant le renommage
+	target := c.path(entry.URL)
+	tmp, err := os.CreateTemp(filepath.Dir(target), filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // sans effet une fois renommé
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// isFresh indique si l'entrée peut être servie sans revalidation
+func (c *ResponseCache) isFresh(entry *cacheEntry) bool {
+	if entry.MaxAge <= 0 {
+		return false
+	}
+	return c.now().Before(entry.StoredAt.Add(time.Duration(entry.MaxAge) * time.Second))
+}
+
+// record incrémente un compteur
+func (c *ResponseCache) record(update func(*CacheStats)) {
+	c.mu.Lock()
+	update(&c.stats)
+	c.mu.Unlock()
+}
+
+// conditionalHeaders ajoute If-None-Match / If-Modified-Since à la requête
+func (entry *cacheEntry) conditionalHeaders(req *http.Request) {
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// cacheControl extrait les directives utiles de Cache-Control
+// (maxAge en secondes, noStore si la réponse ne doit pas être stockée).
+// Toutes les directives sont lues : no-store l'emporte sur le reste et
+// no-cache impose une revalidation quel que soit max-age.
+func cacheControl(header http.Header) (maxAge int, noStore bool) {
+	noCache := false
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+
+		switch {
+		case directive == "no-store":
+			noStore = true
+		case directive == "no-cache":
+			noCache = true
+		case strings.HasPrefix(directive, "max-age="):
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil && seconds > 0 {
+				maxAge = seconds
+			}
+		}
+	}
+	if noStore || noCache {
+		maxAge = 0
+	}
+	return maxAge, noStore
+}
+
+// =====================
+// CACHE PAR DÉFAUT
+// =====================
+
+var (
+	responseCacheMu sync.RWMutex
+	responseCache   = newDefaultCache()
+)
+
+func newDefaultCache() *ResponseCache {
+	dir := DefaultCacheDir()
+	if dir == "" {
+		return nil // pas de dossier de cache → cache désactivé
+	}
+	return NewResponseCache(dir)
+}
+
+// SetResponseCache remplace le cache utilisé par FetchJSON (nil pour le désactiver)
+func SetResponseCache(cache *ResponseCache) {
+	responseCacheMu.Lock()
+	defer responseCacheMu.Unlock()
+	responseCache = cache
+}
+
+// currentCache retourne le cache actif (peut être nil)
+func currentCache() *ResponseCache {
+	responseCacheMu.RLock()
+	defer responseCacheMu.RUnlock()
+	return responseCache
+}
+
+// GetCacheStats retourne les compteurs du cache actif
+func GetCacheStats() CacheStats {
+	if cache := currentCache(); cache != nil {
+		return cache.Stats()
+	}
+	return CacheStats{}
+}
+
+// PurgeCache vide le cache actif
+func PurgeCache() error {
+	if cache := currentCache(); cache != nil {
+		return cache.Purge()
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type payload struct {
+	Name string `json:"name"`
+}
+
+// useTempCache installe un cache vide pour la durée du test
+func useTempCache(t *testing.T) *ResponseCache {
+	t.Helper()
+	cache := NewResponseCache(t.TempDir())
+	SetResponseCache(cache)
+	t.Cleanup(func() { SetResponseCache(nil) })
+	return cache
+}
+
+func TestFetchJSON_ConditionalRevalidation(t *testing.T) {
+	cache := useTempCache(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"name": "Queen"}`))
+	}))
+	defer server.Close()
+
+	var first, second payload
+	if err := FetchJSON(server.URL, &first); err != nil {
+		t.Fatalf("Premier appel: %v", err)
+	}
+	if err := FetchJSON(server.URL, &second); err != nil {
+		t.Fatalf("Second appel: %v", err)
+	}
+
+	if second.Name != "Queen" {
+		t.Errorf("Le 304 devrait servir le corps en cache, got %q", second.Name)
+	}
+
+	stats := cache.Stats()
+	if stats.Misses != 1 || stats.Revalidated != 1 {
+		t.Errorf("Stats inattendues: %+v", stats)
+	}
+	if requests != 2 {
+		t.Errorf("Attendu 2 requêtes, got %d", requests)
+	}
+}
+
+func TestFetchJSON_RevalidationUpdatesLastModified(t *testing.T) {
+	cache := useTempCache(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") != "" {
+			w.Header().Set("Last-Modified", "Tue, 02 Jan 2024 00:00:00 GMT")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		w.Write([]byte(`{"name": "Queen"}`))
+	}))
+	defer server.Close()
+
+	var p payload
+	FetchJSON(server.URL, &p)
+	if err := FetchJSON(server.URL, &p); err != nil {
+		t.Fatalf("Revalidation: %v", err)
+	}
+
+	entry := cache.get(server.URL)
+	if entry == nil || entry.LastModified != "Tue, 02 Jan 2024 00:00:00 GMT" {
+		t.Errorf("Last-Modified du 304 non enregistré: %+v", entry)
+	}
+}
+
+func TestFetchJSON_MaxAgeServesFromDisk(t *testing.T) {
+	cache := useTempCache(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.Write([]byte(`{"name": "Pink Floyd"}`))
+	}))
+	defer server.Close()
+
+	var p payload
+	FetchJSON(server.URL, &p)
+	FetchJSON(server.URL, &p)
+
+	if requests != 1 {
+		t.Errorf("La seconde lecture devrait venir du disque, got %d requêtes", requests)
+	}
+	if cache.Stats().Hits != 1 {
+		t.Errorf("Attendu 1 hit, got %+v", cache.Stats())
+	}
+
+	// Une fois max-age écoulé, on retourne au réseau
+	cache.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	FetchJSON(server.URL, &p)
+	if requests != 2 {
+		t.Errorf("L'entrée expirée devrait être revalidée, got %d requêtes", requests)
+	}
+}
+
+func TestFetchJSON_StaleOnNetworkFailure(t *testing.T) {
+	cache := useTempCache(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "Scorpions"}`))
+	}))
+	url := server.URL
+
+	var p payload
+	if err := FetchJSON(url, &p); err != nil {
+		t.Fatalf("Premier appel: %v", err)
+	}
+
+	// Serveur arrêté → plus de réseau
+	server.Close()
+	stubSleep(t)
+
+	var stale payload
+	if err := FetchJSON(url, &stale); err != nil {
+		t.Fatalf("Devrait servir les données en cache, got %v", err)
+	}
+	if stale.Name != "Scorpions" {
+		t.Errorf("Données en cache incorrectes: %q", stale.Name)
+	}
+	if cache.Stats().StaleServed != 1 {
+		t.Errorf("Attendu 1 réponse périmée servie, got %+v", cache.Stats())
+	}
+}
+
+func TestFetchJSONContext_ReportsStaleData(t *testing.T) {
+	useTempCache(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "Scorpions"}`))
+	}))
+	url := server.URL
+
+	// Réseau disponible : réponse fraîche
+	ctx, report := WithFetchReport(context.Background())
+	var p payload
+	if err := FetchJSONContext(ctx, url, &p); err != nil {
+		t.Fatalf("Premier appel: %v", err)
+	}
+	if report.Stale() {
+		t.Error("Une réponse du réseau ne doit pas être signalée périmée")
+	}
+
+	// Réseau coupé, cache chaud : les données arrivent mais sont signalées
+	server.Close()
+	stubSleep(t)
+
+	ctx, report = WithFetchReport(context.Background())
+	var stale payload
+	if err := FetchJSONContext(ctx, url, &stale); err != nil {
+		t.Fatalf("Devrait servir les données en cache, got %v", err)
+	}
+	if stale.Name != "Scorpions" || !report.Stale() {
+		t.Errorf("Données périmées non signalées: %+v, stale=%v", stale, report.Stale())
+	}
+	if report.StaleSince().IsZero() {
+		t.Error("La date des données en cache devrait être connue")
+	}
+}
+
+func TestFetchJSON_NoStore(t *testing.T) {
+	cache := useTempCache(t)
+
+	for _, header := range []string{"no-store", "no-cache, no-store", "max-age=60, no-store"} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", header)
+			w.Write([]byte(`{"name": "Muse"}`))
+		}))
+
+		var p payload
+		FetchJSON(server.URL, &p)
+		server.Close()
+
+		if cache.get(server.URL) != nil {
+			t.Errorf("Une réponse %q ne devrait pas être stockée", header)
+		}
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	tests := []struct {
+		header  string
+		maxAge  int
+		noStore bool
+	}{
+		{"", 0, false},
+		{"max-age=60", 60, false},
+		{"public, max-age=60", 60, false},
+		{"max-age=-5", 0, false},
+		{"no-cache", 0, false},
+		{"no-cache, max-age=60", 0, false},
+		{"no-store", 0, true},
+		{"no-cache, no-store", 0, true},
+		{"No-Cache,No-Store", 0, true},
+		{"max-age=60, no-store", 0, true},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		header.Set("Cache-Control", tt.header)
+		maxAge, noStore := cacheControl(header)
+		if maxAge != tt.maxAge || noStore != tt.noStore {
+			t.Errorf("cacheControl(%q) = %d, %v ; attendu %d, %v", tt.header, maxAge, noStore, tt.maxAge, tt.noStore)
+		}
+	}
+}
+
+func TestResponseCache_Purge(t *testing.T) {
+	cache := useTempCache(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "Coldplay"}`))
+	}))
+	defer server.Close()
+
+	var p payload
+	FetchJSON(server.URL, &p)
+
+	if err := PurgeCache(); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if cache.get(server.URL) != nil {
+		t.Error("Le cache devrait être vide après Purge")
+	}
+	if GetCacheStats() != (CacheStats{}) {
+		t.Errorf("Les compteurs devraient être remis à zéro, got %+v", GetCacheStats())
+	}
+}
+
+func TestResponseCache_ConcurrentPut(t *testing.T) {
+	cache := NewResponseCache(t.TempDir())
+	url := "http://example.test/artists"
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body := []byte(`{"name": "Queen ` + strings.Repeat("!", i) + `"}`)
+			if err := cache.put(&cacheEntry{URL: url, Body: body}); err != nil {
+				t.Errorf("put: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if cache.get(url) == nil {
+		t.Fatal("L'entrée devrait être lisible après des écritures concurrentes")
+	}
+	files, _ := os.ReadDir(cache.dir)
+	if len(files) != 1 {
+		t.Errorf("Aucun fichier temporaire ne devrait rester, got %d fichiers", len(files))
+	}
+}
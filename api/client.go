@@ -1,6 +1,7 @@
 package api
 
 import (
+    "context"
     "encoding/json"
     "fmt"
     "io"
@@ -13,28 +14,192 @@ var httpClient = &http.Client{
     Timeout: 10 * time.Second,
 }
 
-// FetchJSON récupère l'URL et la décode dans target
+// response est une réponse HTTP dont le corps a déjà été lu
+type response struct {
+    StatusCode int
+    Header     http.Header
+    Body       []byte
+}
+
+// FetchJSON récupère l'URL et la décode dans target.
+// Les réponses sont conservées dans le cache disque (voir cache.go) :
+// servies directement tant que max-age n'est pas écoulé, revalidées via
+// ETag / Last-Modified ensuite, et réutilisées si le réseau est en panne.
+// Les échecs temporaires sont retentés (voir retry.go) et un disjoncteur
+// par hôte évite d'insister quand l'API est hors service (voir breaker.go).
 func FetchJSON(url string, target interface{}) error {
-    resp, err := httpClient.Get(url)
+    return FetchJSONContext(context.Background(), url, target)
+}
+
+// FetchJSONContext est la variante de FetchJSON qui respecte l'annulation
+// et la deadline de ctx (requête HTTP et attentes entre tentatives).
+// Une réponse servie depuis le cache périmé est signalée dans le
+// FetchReport du contexte (voir WithFetchReport).
+func FetchJSONContext(ctx context.Context, url string, target interface{}) error {
+    cache := currentCache()
+
+    var entry *cacheEntry
+    if cache != nil {
+        entry = cache.get(url)
+        if entry != nil && cache.isFresh(entry) {
+            if err := json.Unmarshal(entry.Body, target); err == nil {
+                cache.record(func(s *CacheStats) { s.Hits++ })
+                return nil
+            }
+        }
+    }
+
+    resp, err := fetchWithRetry(ctx, url, entry)
     if err != nil {
-        return fmt.Errorf("erreur lors de la requête GET %s: %w", url, err)
+        // Annulation demandée par l'appelant : pas de repli sur le cache
+        if ctx.Err() != nil {
+            return err
+        }
+        if serveStale(ctx, cache, entry, target) {
+            return nil
+        }
+        return err
+    }
+
+    // Pas de changement côté serveur : on rafraîchit l'entrée existante
+    if resp.StatusCode == http.StatusNotModified && entry != nil {
+        entry.MaxAge, _ = cacheControl(resp.Header)
+        entry.StoredAt = cache.now()
+        if etag := resp.Header.Get("ETag"); etag != "" {
+            entry.ETag = etag
+        }
+        if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+            entry.LastModified = lastModified
+        }
+        cache.put(entry)
+        cache.record(func(s *CacheStats) { s.Revalidated++ })
+
+        if err := json.Unmarshal(entry.Body, target); err != nil {
+            return &FetchError{Kind: ErrDecode, URL: url, StatusCode: resp.StatusCode, Err: err}
+        }
+        return nil
     }
-    defer resp.Body.Close()
 
     // Vérifier le code de statut HTTP
     if resp.StatusCode != http.StatusOK {
-        return fmt.Errorf("statut HTTP %d pour %s", resp.StatusCode, url)
+        if resp.StatusCode >= 500 && serveStale(ctx, cache, entry, target) {
+            return nil
+        }
+        return statusError(url, resp.StatusCode)
     }
 
+    if err := json.Unmarshal(resp.Body, target); err != nil {
+        return &FetchError{Kind: ErrDecode, URL: url, StatusCode: resp.StatusCode, Err: err}
+    }
+
+    if cache != nil {
+        cache.record(func(s *CacheStats) { s.Misses++ })
+
+        maxAge, noStore := cacheControl(resp.Header)
+        if !noStore {
+            cache.put(&cacheEntry{
+                URL:          url,
+                ETag:         resp.Header.Get("ETag"),
+                LastModified: resp.Header.Get("Last-Modified"),
+                StoredAt:     cache.now(),
+                MaxAge:       maxAge,
+                Body:         resp.Body,
+            })
+        }
+    }
+
+    return nil
+}
+
+// fetchWithRetry exécute le GET en retentant les erreurs réseau, les 5xx et
+// les 429 avec un délai exponentiel (ou le Retry-After du serveur). Une
+// requête invalide échoue aussitôt en ErrBadRequest.
+// Les erreurs renvoyées sont classées (voir errors.go).
+func fetchWithRetry(ctx context.Context, url string, entry *cacheEntry) (*response, error) {
+    breaker := breakerFor(url)
+    if err := breaker.Allow(); err != nil {
+        return nil, transportError(url, err)
+    }
+
+    policy := currentRetryPolicy()
+
+    for attempt := 1; ; attempt++ {
+        resp, err := doGet(ctx, url, entry)
+        if ctx.Err() != nil {
+            breaker.Release()
+            return nil, transportError(url, ctx.Err())
+        }
+        if err != nil && !isTransient(err) {
+            // Requête invalide : rien à retenter, et rien à dire de la santé de l'API
+            breaker.Release()
+            return nil, &FetchError{Kind: ErrBadRequest, URL: url, Err: err}
+        }
+        if !isRetryable(resp, err) {
+            breaker.Success()
+            return resp, nil
+        }
+
+        if attempt >= policy.MaxAttempts {
+            breaker.Failure()
+            if err != nil {
+                return nil, transportError(url, err)
+            }
+            return resp, nil // 5xx / 429 définitif, géré par l'appelant
+        }
+
+        delay := policy.backoff(attempt)
+        if wait := retryAfter(resp); wait > delay {
+            delay = wait
+        }
+
+        fmt.Printf("🔁 Nouvelle tentative %d/%d pour %s dans %v\n", attempt+1, policy.MaxAttempts, url, delay.Round(time.Millisecond))
+        if err := sleep(ctx, delay); err != nil {
+            breaker.Release()
+            return nil, transportError(url, err)
+        }
+    }
+}
+
+// doGet effectue une seule requête GET et lit le corps de la réponse
+func doGet(ctx context.Context, url string, entry *cacheEntry) (*response, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+    if err != nil {
+        return nil, fmt.Errorf("erreur création requête %s: %w", url, err)
+    }
+    if entry != nil {
+        entry.conditionalHeaders(req)
+    }
+
+    resp, err := httpClient.Do(req)
+    if err != nil {
+        return nil, err // *url.Error, déjà préfixée par la méthode et l'URL
+    }
+    defer resp.Body.Close()
+
     // io.ReadAll au lieu de ioutil.ReadAll (deprecated)
     body, err := io.ReadAll(resp.Body)
     if err != nil {
-        return fmt.Errorf("erreur lecture body: %w", err)
+        return nil, fmt.Errorf("erreur lecture body: %w", err)
+    }
+
+    return &response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+// serveStale décode l'ancienne réponse en cache quand le réseau fait défaut
+// et la signale au FetchReport de ctx
+func serveStale(ctx context.Context, cache *ResponseCache, entry *cacheEntry, target interface{}) bool {
+    if cache == nil || entry == nil {
+        return false
     }
 
-    if err := json.Unmarshal(body, target); err != nil {
-        return fmt.Errorf("erreur décodage JSON: %w", err)
+    if err := json.Unmarshal(entry.Body, target); err != nil {
+        return false
     }
 
-    return nil
-}
\ No newline at end of file
+    cache.record(func(s *CacheStats) { s.StaleServed++ })
+    if report := reportFor(ctx); report != nil {
+        report.markStale(entry.StoredAt)
+    }
+    fmt.Printf("📦 Réseau indisponible, données en cache du %s pour %s\n", entry.StoredAt.Format("02/01/2006 15:04"), entry.URL)
+    return true
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Classes d'erreurs renvoyées par FetchJSON, à tester avec errors.Is
+var (
+	ErrNotFound    = errors.New("ressource introuvable")
+	ErrTimeout     = errors.New("délai d'attente dépassé")
+	ErrDecode      = errors.New("réponse illisible")
+	ErrRateLimited = errors.New("trop de requêtes")
+	ErrOffline     = errors.New("réseau indisponible")
+	ErrUnavailable = errors.New("service indisponible")
+	ErrBadStatus   = errors.New("statut HTTP inattendu")
+	ErrBadRequest  = errors.New("requête invalide")
+)
+
+// FetchError décrit l'échec d'un appel : la classe (Kind), l'URL, le statut
+// HTTP (0 si aucune réponse) et la cause d'origine
+type FetchError struct {
+	Kind       error
+	URL        string
+	StatusCode int
+	Err        error
+}
+
+func (e *FetchError) Error() string {
+	msg := fmt.Sprintf("GET %s: %v", e.URL, e.Kind)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (HTTP %d)", e.StatusCode)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Is rend la classe testable avec errors.Is(err, ErrNotFound)
+func (e *FetchError) Is(target error) bool {
+	return e.Kind == target
+}
+
+// Unwrap expose la cause (context.DeadlineExceeded, ErrCircuitOpen...)
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// statusError construit l'erreur correspondant à un code HTTP non géré
+func statusError(url string, status int) error {
+	kind := ErrBadStatus
+	switch {
+	case status == http.StatusNotFound:
+		kind = ErrNotFound
+	case status == http.StatusTooManyRequests:
+		kind = ErrRateLimited
+	case status >= 500:
+		kind = ErrUnavailable
+	}
+	return &FetchError{Kind: kind, URL: url, StatusCode: status}
+}
+
+// transportError classe une erreur survenue sans réponse exploitable
+func transportError(url string, err error) error {
+	// Déjà classée (disjoncteur, tentative précédente...)
+	var fetchErr *FetchError
+	if errors.As(err, &fetchErr) {
+		return err
+	}
+
+	// Annulation explicite : on la laisse remonter telle quelle
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("GET %s: %w", url, err)
+	}
+
+	kind := ErrOffline
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		kind = ErrUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		kind = ErrTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		kind = ErrTimeout
+	}
+	return &FetchError{Kind: kind, URL: url, Err: err}
+}
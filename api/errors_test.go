@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchJSON_ErrorClasses(t *testing.T) {
+	useTempCache(t)
+	stubSleep(t)
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		kind   error
+	}{
+		{"404", http.StatusNotFound, "", ErrNotFound},
+		{"429", http.StatusTooManyRequests, "", ErrRateLimited},
+		{"503", http.StatusServiceUnavailable, "", ErrUnavailable},
+		{"403", http.StatusForbidden, "", ErrBadStatus},
+		{"JSON invalide", http.StatusOK, `{"name": `, ErrDecode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			var p payload
+			err := FetchJSON(server.URL, &p)
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("Attendu %v, got %v", tt.kind, err)
+			}
+
+			var fetchErr *FetchError
+			if !errors.As(err, &fetchErr) {
+				t.Fatalf("Attendu un *FetchError, got %T", err)
+			}
+			if fetchErr.URL != server.URL || fetchErr.StatusCode != tt.status {
+				t.Errorf("URL/statut incorrects: %+v", fetchErr)
+			}
+		})
+	}
+}
+
+func TestFetchJSON_ErrOffline(t *testing.T) {
+	useTempCache(t)
+	stubSleep(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	var p payload
+	err := FetchJSON(url, &p)
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("Attendu ErrOffline, got %v", err)
+	}
+}
+
+func TestFetchJSONContext_ErrTimeout(t *testing.T) {
+	useTempCache(t)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var p payload
+	err := FetchJSONContext(ctx, server.URL, &p)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("Attendu ErrTimeout, got %v", err)
+	}
+}
+
+func TestFetchJSON_CircuitOpenIsUnavailable(t *testing.T) {
+	useTempCache(t)
+	SetCircuitBreakerSettings(1, time.Minute)
+	t.Cleanup(func() { SetCircuitBreakerSettings(5, 30*time.Second) })
+
+	url := "http://api.invalid/artists"
+	breakerFor(url).Failure()
+
+	var p payload
+	err := FetchJSON(url, &p)
+	if !errors.Is(err, ErrUnavailable) || !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Attendu ErrUnavailable et ErrCircuitOpen, got %v", err)
+	}
+}
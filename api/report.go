@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FetchReport indique si des appels FetchJSONContext ont été servis depuis le
+// cache périmé (réseau indisponible ou serveur en erreur) : ces appels
+// réussissent, mais les données ne sont pas à jour.
+type FetchReport struct {
+	mu     sync.Mutex
+	stale  int
+	oldest time.Time
+}
+
+type fetchReportKey struct{}
+
+// WithFetchReport retourne un contexte dont les appels FetchJSONContext
+// renseignent le rapport
+func WithFetchReport(ctx context.Context) (context.Context, *FetchReport) {
+	report := &FetchReport{}
+	return context.WithValue(ctx, fetchReportKey{}, report), report
+}
+
+// Stale indique si au moins une réponse venait du cache périmé
+func (r *FetchReport) Stale() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stale > 0
+}
+
+// StaleSince retourne la date de la plus ancienne réponse périmée servie
+// (zéro si aucune)
+func (r *FetchReport) StaleSince() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.oldest
+}
+
+// markStale enregistre une réponse périmée stockée à storedAt
+func (r *FetchReport) markStale(storedAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stale++
+	if r.oldest.IsZero() || storedAt.Before(r.oldest) {
+		r.oldest = storedAt
+	}
+}
+
+// reportFor retourne le rapport attaché à ctx, ou nil
+func reportFor(ctx context.Context) *FetchReport {
+	report, _ := ctx.Value(fetchReportKey{}).(*FetchReport)
+	return report
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy décrit la stratégie de nouvelle tentative pour les GET
+type RetryPolicy struct {
+	MaxAttempts int           // Nombre total de tentatives (1 = pas de retry)
+	BaseDelay   time.Duration // Délai avant la 2e tentative, doublé ensuite
+	MaxDelay    time.Duration // Plafond du délai exponentiel
+	Jitter      float64       // Part aléatoire du délai (0.2 = ±20%)
+}
+
+// DefaultRetryPolicy convient au dyno Heroku gratuit qui répond parfois 503
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    8 * time.Second,
+	Jitter:      0.2,
+}
+
+// maxRetryAfter borne le Retry-After annoncé par le serveur
+const maxRetryAfter = 60 * time.Second
+
+var (
+	retryMu     sync.RWMutex
+	retryPolicy = DefaultRetryPolicy
+
+	// sleep est remplaçable dans les tests
+	sleep = sleepContext
+)
+
+// SetRetryPolicy change la stratégie utilisée par FetchJSON
+func SetRetryPolicy(policy RetryPolicy) {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	retryPolicy = policy
+}
+
+// currentRetryPolicy retourne la stratégie active
+func currentRetryPolicy() RetryPolicy {
+	retryMu.RLock()
+	defer retryMu.RUnlock()
+	return retryPolicy
+}
+
+// backoff calcule le délai avant la tentative suivante (attempt commence à 1)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		// Facteur aléatoire dans [1-Jitter, 1+Jitter]
+		factor := 1 + p.Jitter*(2*rand.Float64()-1)
+		delay = time.Duration(float64(delay) * factor)
+	}
+
+	return delay
+}
+
+// sleepContext attend d ou s'interrompt dès que ctx est annulé
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isRetryable indique si une réponse (ou une erreur réseau) mérite un nouvel essai
+func isRetryable(resp *response, err error) bool {
+	if err != nil {
+		return isTransient(err)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// isTransient indique si une erreur sans réponse vient du réseau (timeout,
+// connexion refusée ou coupée, DNS) plutôt que de la requête elle-même
+// (URL mal formée, schéma non supporté...)
+func isTransient(err error) bool {
+	// *url.Error implémente net.Error : seule sa cause compte
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryAfter lit l'en-tête Retry-After (secondes ou date HTTP)
+func retryAfter(resp *response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = time.Until(date)
+	}
+
+	if wait < 0 {
+		return 0
+	}
+	if wait > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return wait
+}
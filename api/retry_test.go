@@ -0,0 +1,294 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubSleep enregistre les délais au lieu de dormir
+func stubSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	delays := []time.Duration{}
+	sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { sleep = sleepContext })
+	return &delays
+}
+
+func TestFetchJSON_RetriesOn503(t *testing.T) {
+	useTempCache(t)
+	delays := stubSleep(t)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"name": "Queen"}`))
+	}))
+	defer server.Close()
+
+	var p payload
+	if err := FetchJSON(server.URL, &p); err != nil {
+		t.Fatalf("Devrait réussir après retry, got %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("Attendu 3 tentatives, got %d", calls)
+	}
+	if len(*delays) != 2 {
+		t.Errorf("Attendu 2 attentes, got %v", *delays)
+	}
+}
+
+func TestFetchJSON_HonoursRetryAfter(t *testing.T) {
+	useTempCache(t)
+	delays := stubSleep(t)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"name": "Muse"}`))
+	}))
+	defer server.Close()
+
+	var p payload
+	if err := FetchJSON(server.URL, &p); err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+
+	if len(*delays) != 1 || (*delays)[0] < 3*time.Second {
+		t.Errorf("Le délai devrait respecter Retry-After (3s), got %v", *delays)
+	}
+}
+
+func TestFetchJSON_DoesNotRetry404(t *testing.T) {
+	useTempCache(t)
+	stubSleep(t)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var p payload
+	if err := FetchJSON(server.URL, &p); err == nil {
+		t.Error("Un 404 devrait retourner une erreur")
+	}
+	if calls != 1 {
+		t.Errorf("Un 404 ne doit pas être retenté, got %d appels", calls)
+	}
+}
+
+func TestFetchJSON_DoesNotRetryBadRequest(t *testing.T) {
+	useTempCache(t)
+	delays := stubSleep(t)
+	SetRetryPolicy(RetryPolicy{MaxAttempts: 3})
+	SetCircuitBreakerSettings(2, time.Minute)
+	t.Cleanup(func() {
+		SetRetryPolicy(DefaultRetryPolicy)
+		SetCircuitBreakerSettings(5, 30*time.Second)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	var p payload
+	FetchJSON(server.URL, &p) // premier échec compté par le disjoncteur
+	*delays = nil
+
+	for _, url := range []string{"http://" + host + "/%zz", "ftp://" + host + "/artists", "http://[" + host} {
+		err := FetchJSON(url, &p)
+		if !errors.Is(err, ErrBadRequest) {
+			t.Errorf("FetchJSON(%q): attendu ErrBadRequest, got %v", url, err)
+		}
+		if len(*delays) != 0 {
+			t.Errorf("FetchJSON(%q): une requête invalide ne doit pas être retentée, got %v", url, *delays)
+		}
+	}
+
+	// Les requêtes invalides n'ont ni remis à zéro ni incrémenté les échecs
+	FetchJSON(server.URL, &p)
+	if err := FetchJSON(server.URL, &p); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Le second échec réseau doit ouvrir le circuit, got %v", err)
+	}
+}
+
+func TestFetchJSON_CircuitBreakerFailsFast(t *testing.T) {
+	useTempCache(t)
+	stubSleep(t)
+	SetRetryPolicy(RetryPolicy{MaxAttempts: 2})
+	SetCircuitBreakerSettings(2, time.Minute)
+	t.Cleanup(func() {
+		SetRetryPolicy(DefaultRetryPolicy)
+		SetCircuitBreakerSettings(5, 30*time.Second)
+	})
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	var p payload
+	FetchJSON(server.URL, &p)
+	FetchJSON(server.URL, &p)
+	callsBeforeOpen := calls
+
+	err := FetchJSON(server.URL, &p)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Le circuit devrait être ouvert, got %v", err)
+	}
+	if calls != callsBeforeOpen {
+		t.Error("Aucune requête ne doit partir quand le circuit est ouvert")
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	now := time.Now()
+	cb := NewCircuitBreaker(1, 10*time.Second)
+	cb.now = func() time.Time { return now }
+
+	cb.Failure()
+	if cb.Allow() == nil {
+		t.Fatal("Le circuit devrait être ouvert après un échec")
+	}
+
+	// Après le délai : une seule requête d'essai
+	now = now.Add(11 * time.Second)
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("La requête d'essai devrait passer, got %v", err)
+	}
+	if cb.Allow() == nil {
+		t.Error("Une seule requête d'essai à la fois")
+	}
+
+	// Essai annulé : un autre essai peut partir, le circuit reste half-open
+	cb.Release()
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("Un essai annulé doit libérer la place, got %v", err)
+	}
+
+	cb.Success()
+	if err := cb.Allow(); err != nil {
+		t.Errorf("Le circuit devrait être refermé, got %v", err)
+	}
+}
+
+func TestFetchJSON_CancelledTrialReleasesBreaker(t *testing.T) {
+	useTempCache(t)
+	stubSleep(t)
+	SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	SetCircuitBreakerSettings(1, 10*time.Millisecond)
+	t.Cleanup(func() {
+		SetRetryPolicy(DefaultRetryPolicy)
+		SetCircuitBreakerSettings(5, 30*time.Second)
+	})
+
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"name":"ok"}`))
+	}))
+	defer server.Close()
+
+	var p payload
+	FetchJSON(server.URL, &p) // ouvre le circuit
+	time.Sleep(20 * time.Millisecond)
+
+	// La requête d'essai (half-open) est annulée par l'appelant
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := FetchJSONContext(ctx, server.URL, &p); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Attendu context.Canceled, got %v", err)
+	}
+
+	healthy.Store(true)
+	if err := FetchJSON(server.URL, &p); err != nil {
+		t.Fatalf("Le disjoncteur doit laisser partir un nouvel essai, got %v", err)
+	}
+	if p.Name != "ok" {
+		t.Errorf("Réponse inattendue: %+v", p)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := policy.backoff(i + 1); got != want {
+			t.Errorf("backoff(%d) = %v; want %v", i+1, got, want)
+		}
+	}
+
+	// Avec jitter, le délai reste dans ±20%
+	policy.Jitter = 0.2
+	for i := 0; i < 50; i++ {
+		d := policy.backoff(1)
+		if d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("Jitter hors bornes: %v", d)
+		}
+	}
+}
+
+func TestFetchJSONContext_Cancelled(t *testing.T) {
+	useTempCache(t)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var p payload
+	err := FetchJSONContext(ctx, server.URL, &p)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Attendu context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("La requête en cours aurait dû être interrompue")
+	}
+}
+
+func TestSleepContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := sleepContext(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Attendu context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Commande checkdata : recoupe Locations, Dates et Relation pour chaque
+// artiste et affiche les incohérences trouvées.
+//
+//	go run ./cmd/checkdata            # rapport lisible
+//	go run ./cmd/checkdata -json      # rapport JSON
+//	go run ./cmd/checkdata -artist 1  # un seul artiste
+//
+// La source est choisie comme pour l'application (GROUPIE_DATA_DIR,
+// GROUPIE_API_URL). Le code de sortie vaut 1 si une erreur est détectée.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"groupie-tracker/services"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "affiche le rapport au format JSON")
+	artistID := flag.Int("artist", 0, "ne vérifie que l'artiste avec cet ID")
+	timeout := flag.Duration("timeout", 2*time.Minute, "durée maximale du chargement")
+	flag.Parse()
+
+	// Les services journalisent sur la sortie standard : en mode JSON, on
+	// les redirige vers stderr pour que stdout reste exploitable
+	stdout := os.Stdout
+	if *asJSON {
+		os.Stdout = os.Stderr
+	}
+
+	source := services.NewDataSourceFromEnv()
+	services.SetDefaultDataSource(source)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	store := services.NewArtistStore(source)
+	if err := store.LoadAll(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Chargement des données impossible: %v\n", err)
+		os.Exit(2)
+	}
+
+	reports := services.NewConsistencyChecker().CheckAll(store.Aggregates())
+	if *artistID != 0 {
+		filtered := []services.ConsistencyReport{}
+		for _, report := range reports {
+			if report.ArtistID == *artistID {
+				filtered = append(filtered, report)
+			}
+		}
+		if len(filtered) == 0 {
+			fmt.Fprintf(os.Stderr, "❌ Artiste #%d introuvable\n", *artistID)
+			os.Exit(2)
+		}
+		reports = filtered
+	}
+
+	if *asJSON {
+		os.Stdout = stdout
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Encodage JSON: %v\n", err)
+			os.Exit(2)
+		}
+	} else {
+		printReports(reports)
+	}
+
+	for _, report := range reports {
+		if report.Worst() == services.SeverityError {
+			os.Exit(1)
+		}
+	}
+}
+
+// printReports affiche les artistes concernés puis un résumé
+func printReports(reports []services.ConsistencyReport) {
+	counts := map[services.Severity]int{}
+	clean := 0
+
+	for _, report := range reports {
+		if report.OK() {
+			clean++
+			continue
+		}
+
+		fmt.Printf("%s #%d %s — %d anomalie(s)\n", severityIcon(report.Worst()), report.ArtistID, report.ArtistName, len(report.Issues))
+		for _, issue := range report.Issues {
+			counts[issue.Severity]++
+			fmt.Printf("   [%s] %s\n", issue.Severity, issue.Message)
+		}
+	}
+
+	fmt.Printf("\n📊 %d artistes vérifiés : %d cohérents, %d erreur(s), %d avertissement(s), %d info(s)\n",
+		len(reports), clean, counts[services.SeverityError], counts[services.SeverityWarning], counts[services.SeverityInfo])
+}
+
+func severityIcon(severity services.Severity) string {
+	switch severity {
+	case services.SeverityError:
+		return "❌"
+	case services.SeverityWarning:
+		return "⚠️"
+	}
+	return "ℹ️"
+}
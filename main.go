@@ -1,8 +1,14 @@
 package main
 
-import "groupie-tracker/ui"
+import (
+	"groupie-tracker/services"
+	"groupie-tracker/ui"
+)
 
 func main() {
-	app := ui.NewApp()
+	source := services.NewDataSourceFromEnv()
+	services.SetDefaultDataSource(source)
+
+	app := ui.NewApp(source)
 	app.Run()
 }
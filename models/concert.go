@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Concert est une représentation d'un artiste dans un lieu à une date,
+// issue d'une entrée de Relation.DatesLocations
+type Concert struct {
+	ArtistID int
+	Place    Place     // lieu canonique (ville, région, pays ISO)
+	Location string    // clé brute de l'API, ex. "los_angeles-usa"
+	Date     time.Time // date du concert (UTC, sans heure)
+}
@@ -1,6 +1,140 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
 type ConcertDate struct {
-    ID    int      `json:"id"`
-    Dates []string `json:"dates"`
+	ID    int    `json:"id"`
+	Dates []Date `json:"dates"`
+}
+
+// DateLayout est le format des dates de l'API ("14-12-1973")
+const DateLayout = "02-01-2006"
+
+// Date est une date de l'API, parsée une seule fois au décodage JSON.
+// Le "*" qui précède certaines dates est conservé dans Starred. Une valeur
+// illisible ne fait pas échouer le décodage : la date est nulle, la valeur
+// d'origine reste disponible via Raw et l'erreur via Err.
+type Date struct {
+	Time    time.Time // minuit UTC (zéro si la date est absente ou illisible)
+	Starred bool      // date marquée d'un "*" par l'API
+
+	raw string // valeur d'origine d'une date illisible
+	err error  // *DateError d'une date illisible
+}
+
+// DateError est retournée pour une date qui ne respecte pas DateLayout
+type DateError struct {
+	Value string
+	Err   error
+}
+
+func (e *DateError) Error() string {
+	return fmt.Sprintf("date invalide %q (attendu jj-mm-aaaa): %v", e.Value, e.Err)
+}
+
+func (e *DateError) Unwrap() error {
+	return e.Err
+}
+
+// ParseDate parse une date "dd-mm-yyyy" ou "*dd-mm-yyyy".
+// Une chaîne vide donne une date nulle, sans erreur.
+func ParseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Date{}, nil
+	}
+
+	starred := strings.HasPrefix(s, "*")
+	t, err := time.Parse(DateLayout, strings.TrimPrefix(s, "*"))
+	if err != nil {
+		return Date{}, &DateError{Value: s, Err: err}
+	}
+	return Date{Time: t, Starred: starred}, nil
+}
+
+// MustParseDate est comme ParseDate mais panique si la date est invalide
+// (pour les valeurs littérales connues à l'avance)
+func MustParseDate(s string) Date {
+	d, err := ParseDate(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// IsZero indique si la date est absente (ou illisible)
+func (d Date) IsZero() bool {
+	return d.Time.IsZero()
+}
+
+// Valid indique si la date a pu être lue (une date absente est valide)
+func (d Date) Valid() bool {
+	return d.err == nil
+}
+
+// Raw retourne la valeur telle que reçue de l'API
+func (d Date) Raw() string {
+	if d.err != nil {
+		return d.raw
+	}
+	return d.String()
+}
+
+// Err retourne l'erreur de lecture d'une date illisible (*DateError), ou nil
+func (d Date) Err() error {
+	return d.err
+}
+
+// Year retourne l'année de la date (0 si absente)
+func (d Date) Year() int {
+	if d.IsZero() {
+		return 0
+	}
+	return d.Time.Year()
+}
+
+// String retourne la date au format de l'API, "*" compris (la valeur
+// d'origine pour une date illisible)
+func (d Date) String() string {
+	if d.err != nil {
+		return d.raw
+	}
+	if d.IsZero() {
+		return ""
+	}
+	if d.Starred {
+		return "*" + d.Time.Format(DateLayout)
+	}
+	return d.Time.Format(DateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+
+	// Une date illisible est conservée telle quelle plutôt que de faire
+	// échouer tout le document (liste d'artistes, index des relations)
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		*d = Date{raw: s, err: err}
+		return nil
+	}
+	*d = parsed
+	return nil
 }
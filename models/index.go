@@ -0,0 +1,16 @@
+package models
+
+// Réponses des endpoints d'index (/locations, /dates, /relation)
+// qui renvoient les données de tous les artistes d'un coup
+
+type LocationIndex struct {
+	Index []Location `json:"index"`
+}
+
+type DateIndex struct {
+	Index []ConcertDate `json:"index"`
+}
+
+type RelationIndex struct {
+	Index []Relation `json:"index"`
+}
@@ -1,6 +1,11 @@
 package models
 
-import "testing"
+import (
+    "encoding/json"
+    "errors"
+    "testing"
+    "time"
+)
 
 func TestArtistStruct(t *testing.T) {
     a := Artist{
@@ -8,7 +13,7 @@ func TestArtistStruct(t *testing.T) {
         Name:         "Queen",
         Members:      []string{"Freddie Mercury"},
         CreationDate: 1970,
-        FirstAlbum:   "14-12-1973",
+        FirstAlbum:   MustParseDate("14-12-1973"),
         Image:        "queen.jpg",
     }
 
@@ -16,3 +21,99 @@ func TestArtistStruct(t *testing.T) {
         t.Errorf("Artist struct fields incorrect")
     }
 }
+
+func TestPlaceDisplay(t *testing.T) {
+    p := Place{
+        Key:     "sydney-new_south_wales-australia",
+        City:    "Sydney",
+        Region:  "New South Wales",
+        Country: Country{Code: "AU", Name: "Australie", Continent: "Océanie"},
+    }
+
+    if p.String() != "Sydney, Australie" {
+        t.Errorf("String() = %q", p.String())
+    }
+    if p.Query() != "Sydney, New South Wales, Australie" {
+        t.Errorf("Query() = %q", p.Query())
+    }
+    if p.Country.Flag() != "🇦🇺" {
+        t.Errorf("Flag() = %q", p.Country.Flag())
+    }
+    if (Country{Name: "Atlantis"}).Flag() != "" {
+        t.Errorf("Un pays inconnu ne devrait pas avoir de drapeau")
+    }
+}
+
+func TestDateJSON(t *testing.T) {
+    var data ConcertDate
+    if err := json.Unmarshal([]byte(`{"id": 1, "dates": ["*23-08-2019", "22-08-2019"]}`), &data); err != nil {
+        t.Fatalf("Unmarshal: %v", err)
+    }
+
+    first := data.Dates[0]
+    if !first.Starred || !first.Time.Equal(time.Date(2019, 8, 23, 0, 0, 0, 0, time.UTC)) {
+        t.Errorf("Première date incorrecte: %+v", first)
+    }
+    if data.Dates[1].Starred {
+        t.Errorf("La seconde date ne devrait pas être marquée")
+    }
+
+    // Aller-retour : le "*" est conservé
+    encoded, err := json.Marshal(data)
+    if err != nil {
+        t.Fatalf("Marshal: %v", err)
+    }
+    if string(encoded) != `{"id":1,"dates":["*23-08-2019","22-08-2019"]}` {
+        t.Errorf("Marshal = %s", encoded)
+    }
+}
+
+func TestDateJSON_Invalid(t *testing.T) {
+    var artist Artist
+    if err := json.Unmarshal([]byte(`{"id": 1, "firstAlbum": "1973-12-14"}`), &artist); err != nil {
+        t.Fatalf("Une date illisible ne doit pas faire échouer le décodage: %v", err)
+    }
+
+    date := artist.FirstAlbum
+    var dateErr *DateError
+    if date.Valid() || !date.IsZero() || !errors.As(date.Err(), &dateErr) || dateErr.Value != "1973-12-14" {
+        t.Errorf("Attendu une date illisible avec sa DateError, got %+v", date)
+    }
+    if date.Raw() != "1973-12-14" {
+        t.Errorf("Raw() = %q", date.Raw())
+    }
+
+    // Aller-retour : la valeur d'origine est conservée
+    encoded, _ := json.Marshal(artist.FirstAlbum)
+    var decoded Date
+    if err := json.Unmarshal(encoded, &decoded); err != nil || decoded.Valid() || decoded.Raw() != "1973-12-14" {
+        t.Errorf("Aller-retour incorrect: %s -> %+v (%v)", encoded, decoded, err)
+    }
+
+    // Date absente : pas d'erreur, date nulle
+    if err := json.Unmarshal([]byte(`{"id": 1, "firstAlbum": ""}`), &artist); err != nil || !artist.FirstAlbum.IsZero() || !artist.FirstAlbum.Valid() {
+        t.Errorf("Une date vide devrait donner une date nulle, got %+v (%v)", artist.FirstAlbum, err)
+    }
+}
+
+func TestDateJSON_InvalidDateKeepsOtherArtists(t *testing.T) {
+    data := `[
+        {"id": 1, "name": "Queen", "firstAlbum": "14-12-1973"},
+        {"id": 2, "name": "Pink Floyd", "firstAlbum": "05/08/1967"},
+        {"id": 3, "name": "The Beatles", "firstAlbum": "22-03-1963"}
+    ]`
+
+    var artists []Artist
+    if err := json.Unmarshal([]byte(data), &artists); err != nil {
+        t.Fatalf("Une date illisible ne doit pas faire échouer la liste: %v", err)
+    }
+    if len(artists) != 3 {
+        t.Fatalf("Attendu 3 artistes, got %d", len(artists))
+    }
+    if !artists[0].FirstAlbum.Valid() || artists[0].FirstAlbum.Year() != 1973 || artists[2].FirstAlbum.Year() != 1963 {
+        t.Errorf("Les dates valides doivent être lues: %+v", artists)
+    }
+    if artists[1].Name != "Pink Floyd" || artists[1].FirstAlbum.Valid() || artists[1].FirstAlbum.Raw() != "05/08/1967" {
+        t.Errorf("L'artiste à la date illisible doit être conservé: %+v", artists[1])
+    }
+}
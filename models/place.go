@@ -0,0 +1,78 @@
+package models
+
+import "strings"
+
+// Country est un pays normalisé selon la norme ISO 3166-1
+type Country struct {
+	Code      string // code alpha-2, ex. "US" (vide si le pays est inconnu)
+	Name      string // nom affiché, ex. "États-Unis"
+	Continent string // ex. "Amérique du Nord"
+}
+
+// Known indique si le pays a été reconnu dans la table de référence
+func (c Country) Known() bool {
+	return c.Code != ""
+}
+
+// Flag retourne l'emoji drapeau dérivé du code ISO (vide si inconnu)
+func (c Country) Flag() string {
+	if len(c.Code) != 2 {
+		return ""
+	}
+
+	var flag strings.Builder
+	for _, letter := range strings.ToUpper(c.Code) {
+		if letter < 'A' || letter > 'Z' {
+			return ""
+		}
+		// Symboles indicateurs régionaux : 🇦 = U+1F1E6
+		flag.WriteRune(0x1F1E6 + letter - 'A')
+	}
+	return flag.String()
+}
+
+// Label retourne le nom du pays précédé de son drapeau, ex. "🇫🇷 France"
+func (c Country) Label() string {
+	if flag := c.Flag(); flag != "" {
+		return flag + " " + c.Name
+	}
+	return c.Name
+}
+
+// Place est un lieu de concert canonique, construit à partir d'une clé de
+// l'API comme "los_angeles-usa" ou "sydney-new_south_wales-australia"
+type Place struct {
+	Key     string // clé brute de l'API
+	City    string // "Los Angeles" (vide si la clé ne désigne qu'une région)
+	Region  string // état ou province, ex. "New South Wales"
+	Country Country
+}
+
+// Locality retourne la ville, ou la région à défaut
+func (p Place) Locality() string {
+	if p.City != "" {
+		return p.City
+	}
+	return p.Region
+}
+
+// String retourne le nom affiché du lieu, ex. "Los Angeles, États-Unis"
+func (p Place) String() string {
+	return joinNonEmpty(", ", p.Locality(), p.Country.Name)
+}
+
+// Query retourne la forme complète utilisée pour le géocodage,
+// ex. "Sydney, New South Wales, Australie"
+func (p Place) Query() string {
+	return joinNonEmpty(", ", p.City, p.Region, p.Country.Name)
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
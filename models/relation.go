@@ -1,6 +1,6 @@
 package models
 
 type Relation struct {
-    ID             int                 `json:"id"`
-    DatesLocations map[string][]string `json:"datesLocations"` // location -> liste de dates
+    ID             int               `json:"id"`
+    DatesLocations map[string][]Date `json:"datesLocations"` // location -> liste de dates
 }
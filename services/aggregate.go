@@ -1,20 +1,35 @@
 package services
 
-import "groupie-tracker/models"
+import (
+	"context"
 
+	"groupie-tracker/models"
+)
+
+// AggregateArtist agrège les données d'un artiste depuis la source par défaut
 func AggregateArtist(artist models.Artist) (models.ArtistAggregate, error) {
+	return AggregateArtistContext(context.Background(), DefaultDataSource(), artist)
+}
+
+// AggregateArtistFrom agrège les données d'un artiste depuis une source donnée
+func AggregateArtistFrom(source DataSource, artist models.Artist) (models.ArtistAggregate, error) {
+	return AggregateArtistContext(context.Background(), source, artist)
+}
+
+// AggregateArtistContext agrège les données d'un artiste en respectant ctx
+func AggregateArtistContext(ctx context.Context, source DataSource, artist models.Artist) (models.ArtistAggregate, error) {
 
-	locations, err := GetLocation(artist.ID)
+	locations, err := source.GetLocation(ctx, artist.ID)
 	if err != nil {
 		return models.ArtistAggregate{}, err
 	}
 
-	dates, err := GetDate(artist.ID)
+	dates, err := source.GetDate(ctx, artist.ID)
 	if err != nil {
 		return models.ArtistAggregate{}, err
 	}
 
-	relation, err := GetRelation(artist.ID)
+	relation, err := source.GetRelation(ctx, artist.ID)
 	if err != nil {
 		return models.ArtistAggregate{}, err
 	}
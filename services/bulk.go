@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"groupie-tracker/models"
+)
+
+// LoadAllAggregates construit les données agrégées de tous les artistes.
+// Si la source expose les endpoints d'index (BulkDataSource), trois requêtes
+// suffisent ; sinon, ou pour les artistes absents des index, on retombe sur
+// le chargement artiste par artiste.
+func LoadAllAggregates(source DataSource, artists []models.Artist) (map[int]models.ArtistAggregate, error) {
+	return LoadAllAggregatesContext(context.Background(), source, artists)
+}
+
+// LoadAllAggregatesContext est la variante annulable de LoadAllAggregates
+func LoadAllAggregatesContext(ctx context.Context, source DataSource, artists []models.Artist) (map[int]models.ArtistAggregate, error) {
+	aggregates := make(map[int]models.ArtistAggregate, len(artists))
+	if ctx.Err() != nil {
+		return aggregates, ctx.Err()
+	}
+
+	if bulk, ok := source.(BulkDataSource); ok {
+		if err := loadFromIndexes(ctx, bulk, artists, aggregates); err != nil {
+			if ctx.Err() != nil {
+				return aggregates, ctx.Err()
+			}
+			fmt.Printf("⚠️ Chargement groupé impossible, repli artiste par artiste: %v\n", err)
+		}
+	}
+
+	// Repli pour tout ce que les index n'ont pas fourni
+	failed := 0
+	var firstErr error
+	for _, artist := range artists {
+		if _, exists := aggregates[artist.ID]; exists {
+			continue
+		}
+		if ctx.Err() != nil {
+			return aggregates, ctx.Err()
+		}
+
+		aggregate, err := AggregateArtistContext(ctx, source, artist)
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		aggregates[artist.ID] = aggregate
+	}
+
+	if failed > 0 {
+		return aggregates, fmt.Errorf("%d artiste(s) non chargé(s): %w", failed, firstErr)
+	}
+
+	return aggregates, nil
+}
+
+// loadFromIndexes remplit aggregates à partir des trois endpoints d'index
+func loadFromIndexes(ctx context.Context, bulk BulkDataSource, artists []models.Artist, aggregates map[int]models.ArtistAggregate) error {
+	locations, err := bulk.GetAllLocations(ctx)
+	if err != nil {
+		return err
+	}
+
+	dates, err := bulk.GetAllDates(ctx)
+	if err != nil {
+		return err
+	}
+
+	relations, err := bulk.GetAllRelations(ctx)
+	if err != nil {
+		return err
+	}
+
+	locationsByID := make(map[int]models.Location, len(locations))
+	for _, loc := range locations {
+		locationsByID[loc.ID] = loc
+	}
+
+	datesByID := make(map[int]models.ConcertDate, len(dates))
+	for _, date := range dates {
+		datesByID[date.ID] = date
+	}
+
+	relationsByID := make(map[int]models.Relation, len(relations))
+	for _, relation := range relations {
+		relationsByID[relation.ID] = relation
+	}
+
+	for _, artist := range artists {
+		loc, okLoc := locationsByID[artist.ID]
+		date, okDate := datesByID[artist.ID]
+		relation, okRel := relationsByID[artist.ID]
+
+		// Artiste incomplet dans les index → sera chargé individuellement
+		if !okLoc || !okDate || !okRel {
+			continue
+		}
+
+		aggregates[artist.ID] = models.ArtistAggregate{
+			Artist:    artist,
+			Locations: loc,
+			Dates:     date,
+			Relation:  relation,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"groupie-tracker/models"
+)
+
+// countingSource compte les appels par artiste et peut simuler des index incomplets
+type countingSource struct {
+	*MemoryDataSource
+	perArtistCalls int
+	bulkErr        error
+	hiddenFromBulk int // ID absent des index
+}
+
+func (c *countingSource) GetLocation(ctx context.Context, id int) (models.Location, error) {
+	c.perArtistCalls++
+	return c.MemoryDataSource.GetLocation(ctx, id)
+}
+
+func (c *countingSource) GetAllLocations(ctx context.Context) ([]models.Location, error) {
+	if c.bulkErr != nil {
+		return nil, c.bulkErr
+	}
+	all, _ := c.MemoryDataSource.GetAllLocations(ctx)
+	filtered := []models.Location{}
+	for _, loc := range all {
+		if loc.ID != c.hiddenFromBulk {
+			filtered = append(filtered, loc)
+		}
+	}
+	return filtered, nil
+}
+
+func newCountingSource() *countingSource {
+	artists := createTestArtists()
+	memory := NewMemoryDataSource(artists)
+	for _, artist := range artists {
+		memory.AddAggregate(models.ArtistAggregate{
+			Artist:    artist,
+			Locations: models.Location{ID: artist.ID, Locations: []string{"paris-france"}},
+			Dates:     models.ConcertDate{ID: artist.ID, Dates: testDates("*01-01-2020")},
+			Relation:  models.Relation{ID: artist.ID, DatesLocations: map[string][]models.Date{"paris-france": testDates("01-01-2020")}},
+		})
+	}
+	return &countingSource{MemoryDataSource: memory}
+}
+
+func TestLoadAllAggregates_UsesIndexes(t *testing.T) {
+	source := newCountingSource()
+
+	aggregates, err := LoadAllAggregates(source, source.Artists)
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+
+	if len(aggregates) != len(source.Artists) {
+		t.Errorf("Devrait charger %d artistes, got %d", len(source.Artists), len(aggregates))
+	}
+
+	if source.perArtistCalls != 0 {
+		t.Errorf("Aucun appel par artiste attendu, got %d", source.perArtistCalls)
+	}
+
+	if aggregates[1].Artist.Name != "Queen" {
+		t.Errorf("L'agrégat devrait contenir l'artiste, got %+v", aggregates[1].Artist)
+	}
+}
+
+func TestLoadAllAggregates_FallbackForMissingArtist(t *testing.T) {
+	source := newCountingSource()
+	source.hiddenFromBulk = 2
+
+	aggregates, err := LoadAllAggregates(source, source.Artists)
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+
+	if _, ok := aggregates[2]; !ok {
+		t.Error("L'artiste absent des index devrait être chargé individuellement")
+	}
+
+	if source.perArtistCalls != 1 {
+		t.Errorf("Un seul appel par artiste attendu, got %d", source.perArtistCalls)
+	}
+}
+
+func TestLoadAllAggregates_FallbackWhenBulkFails(t *testing.T) {
+	source := newCountingSource()
+	source.bulkErr = errors.New("index indisponible")
+
+	aggregates, err := LoadAllAggregates(source, source.Artists)
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+
+	if len(aggregates) != len(source.Artists) {
+		t.Errorf("Le repli devrait charger tous les artistes, got %d", len(aggregates))
+	}
+
+	if source.perArtistCalls != len(source.Artists) {
+		t.Errorf("Attendu %d appels par artiste, got %d", len(source.Artists), source.perArtistCalls)
+	}
+}
+
+func TestLoadAllAggregates_ReportsFailures(t *testing.T) {
+	source := newCountingSource()
+	source.bulkErr = errors.New("index indisponible")
+	delete(source.Locations, 3)
+
+	aggregates, err := LoadAllAggregates(source, source.Artists)
+	if err == nil {
+		t.Error("Devrait signaler l'artiste non chargé")
+	}
+
+	if len(aggregates) != 2 {
+		t.Errorf("Les autres artistes devraient être chargés, got %d", len(aggregates))
+	}
+}
+
+func TestLoadAllAggregatesContext_Cancelled(t *testing.T) {
+	source := newCountingSource()
+	source.bulkErr = errors.New("index indisponible")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	aggregates, err := LoadAllAggregatesContext(ctx, source, source.Artists)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Attendu context.Canceled, got %v", err)
+	}
+
+	if len(aggregates) != 0 || source.perArtistCalls != 0 {
+		t.Errorf("Aucun chargement attendu après annulation, got %d agrégats / %d appels", len(aggregates), source.perArtistCalls)
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"groupie-tracker/models"
+)
+
+// ConcertsFromAggregate aplatit Relation.DatesLocations d'un artiste en
+// concerts triés par date. Les dates illisibles sont ignorées.
+func ConcertsFromAggregate(aggregate models.ArtistAggregate) []models.Concert {
+	concerts := []models.Concert{}
+
+	for location, dates := range aggregate.Relation.DatesLocations {
+		place := ParsePlace(location)
+		for _, date := range dates {
+			if date.IsZero() {
+				continue
+			}
+			concerts = append(concerts, models.Concert{
+				ArtistID: aggregate.Artist.ID,
+				Place:    place,
+				Location: location,
+				Date:     date.Time,
+			})
+		}
+	}
+
+	SortConcerts(concerts)
+	return concerts
+}
+
+// FlattenConcerts construit la liste triée des concerts de tous les artistes
+func FlattenConcerts(aggregates map[int]models.ArtistAggregate) []models.Concert {
+	concerts := []models.Concert{}
+	for id, aggregate := range aggregates {
+		aggregate.Artist.ID = id // la clé fait foi (agrégats partiels)
+		concerts = append(concerts, ConcertsFromAggregate(aggregate)...)
+	}
+
+	SortConcerts(concerts)
+	return concerts
+}
+
+// SortConcerts trie par date, puis par artiste, puis par lieu (ordre stable
+// d'un lancement à l'autre malgré l'ordre aléatoire des maps)
+func SortConcerts(concerts []models.Concert) {
+	sort.Slice(concerts, func(i, j int) bool {
+		a, b := concerts[i], concerts[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if a.ArtistID != b.ArtistID {
+			return a.ArtistID < b.ArtistID
+		}
+		return a.Location < b.Location
+	})
+}
+
+// ConcertService interroge la liste de tous les concerts du store
+type ConcertService struct {
+	store *ArtistStore
+}
+
+// NewConcertService crée un service de concerts sur un store
+func NewConcertService(store *ArtistStore) *ConcertService {
+	return &ConcertService{store: store}
+}
+
+// All retourne tous les concerts connus, triés par date (ne pas modifier)
+func (cs *ConcertService) All() []models.Concert {
+	return cs.store.State().Concerts()
+}
+
+// ForArtist retourne les concerts d'un artiste, triés par date
+func (cs *ConcertService) ForArtist(artistID int) []models.Concert {
+	return cs.filter(func(c models.Concert) bool { return c.ArtistID == artistID })
+}
+
+// InLocation retourne les concerts dont le lieu correspond à location :
+// ville, région, pays (jeton de l'API, code ISO ou nom) ou clé brute
+func (cs *ConcertService) InLocation(location string) []models.Concert {
+	return cs.filter(func(c models.Concert) bool {
+		return MatchesPlace(c.Place, location)
+	})
+}
+
+// Between retourne les concerts entre from et to inclus
+func (cs *ConcertService) Between(from, to time.Time) []models.Concert {
+	concerts := cs.All()
+
+	// Liste triée : recherche dichotomique des bornes
+	start := sort.Search(len(concerts), func(i int) bool { return !concerts[i].Date.Before(from) })
+	end := sort.Search(len(concerts), func(i int) bool { return concerts[i].Date.After(to) })
+	if start >= end {
+		return []models.Concert{}
+	}
+
+	result := make([]models.Concert, end-start)
+	copy(result, concerts[start:end])
+	return result
+}
+
+func (cs *ConcertService) filter(keep func(models.Concert) bool) []models.Concert {
+	result := []models.Concert{}
+	for _, concert := range cs.All() {
+		if keep(concert) {
+			result = append(result, concert)
+		}
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"groupie-tracker/models"
+)
+
+func concertStore() *ArtistStore {
+	artists := createTestArtists()
+	store := NewArtistStore(NewMemoryDataSource(artists))
+	store.Replace(artists, map[int]models.ArtistAggregate{
+		1: {
+			Artist: artists[0],
+			Relation: models.Relation{ID: 1, DatesLocations: map[string][]models.Date{
+				"london-uk":       testDates("12-07-1986", "11-07-1986"),
+				"paris-france":    testDates("14-06-1986"),
+				"los_angeles-usa": testDates(""),
+			}},
+		},
+		2: {
+			Artist: artists[1],
+			Relation: models.Relation{ID: 2, DatesLocations: map[string][]models.Date{
+				"paris-france": testDates("*20-06-1965"),
+			}},
+		},
+	})
+	return store
+}
+
+func TestConcertsFromAggregate(t *testing.T) {
+	aggregate, _ := concertStore().CachedAggregate(1)
+	concerts := ConcertsFromAggregate(aggregate)
+
+	// La date absente est ignorée
+	if len(concerts) != 3 {
+		t.Fatalf("Attendu 3 concerts, got %d", len(concerts))
+	}
+
+	first := concerts[0]
+	if first.Place.City != "Paris" || first.Place.Country.Code != "FR" || first.Location != "paris-france" || first.ArtistID != 1 {
+		t.Errorf("Premier concert incorrect: %+v", first)
+	}
+	if !first.Date.Equal(time.Date(1986, 6, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Date incorrecte: %v", first.Date)
+	}
+
+	for i := 1; i < len(concerts); i++ {
+		if concerts[i].Date.Before(concerts[i-1].Date) {
+			t.Fatalf("Concerts non triés: %+v", concerts)
+		}
+	}
+}
+
+func TestConcertService_Queries(t *testing.T) {
+	service := NewConcertService(concertStore())
+
+	if all := service.All(); len(all) != 4 || all[0].ArtistID != 2 {
+		t.Errorf("All devrait trier tous les concerts par date, got %+v", all)
+	}
+
+	if got := service.ForArtist(1); len(got) != 3 {
+		t.Errorf("ForArtist(1): attendu 3 concerts, got %d", len(got))
+	}
+
+	if got := service.InLocation("PARIS"); len(got) != 2 {
+		t.Errorf("InLocation(PARIS): attendu 2 concerts, got %d", len(got))
+	}
+	if got := service.InLocation("uk"); len(got) != 2 {
+		t.Errorf("InLocation(uk): attendu 2 concerts, got %d", len(got))
+	}
+
+	from := time.Date(1986, 7, 11, 0, 0, 0, 0, time.UTC)
+	to := time.Date(1986, 7, 12, 0, 0, 0, 0, time.UTC)
+	if got := service.Between(from, to); len(got) != 2 {
+		t.Errorf("Between doit inclure les bornes, got %+v", got)
+	}
+}
+
+func TestFilterEngine_FilterByConcertYear(t *testing.T) {
+	engine := NewFilterEngineWithStore(concertStore())
+
+	criteria := NewFilterCriteria()
+	criteria.EnableConcertYearFilter = true
+	criteria.ConcertYearMin = 1980
+	criteria.ConcertYearMax = 1990
+
+	filtered := engine.ApplyFilters(criteria)
+	if len(filtered) != 1 || filtered[0].ID != 1 {
+		t.Errorf("Seul Queen a joué entre 1980 et 1990, got %+v", filtered)
+	}
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"groupie-tracker/models"
+)
+
+// IssueKind identifie un type d'incohérence entre Locations, Dates et Relation
+type IssueKind string
+
+const (
+	IssueIDMismatch        IssueKind = "id_mismatch"        // ID d'un endpoint différent de celui de l'artiste
+	IssueOrphanLocation    IssueKind = "orphan_location"    // lieu de Locations absent de Relation
+	IssueUnknownLocation   IssueKind = "unknown_location"   // lieu de Relation absent de Locations
+	IssueMissingDate       IssueKind = "missing_date"       // date de Dates absente de Relation
+	IssueExtraDate         IssueKind = "extra_date"         // date de Relation absente de Dates
+	IssueInvalidDate       IssueKind = "invalid_date"       // date vide ou illisible
+	IssueDuplicateLocation IssueKind = "duplicate_location" // lieu listé plusieurs fois
+	IssueDuplicateDate     IssueKind = "duplicate_date"     // même date deux fois pour un lieu
+	IssueFutureDate        IssueKind = "future_date"        // concert après la date de contrôle
+	IssuePastDate          IssueKind = "past_date"          // concert avant la création du groupe
+)
+
+// Severity indique la gravité d'une incohérence
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "erreur"
+	case SeverityWarning:
+		return "avertissement"
+	}
+	return "info"
+}
+
+// MarshalText permet d'exporter la gravité en clair (JSON)
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Issue est une incohérence détectée pour un artiste
+type Issue struct {
+	Kind     IssueKind   `json:"kind"`
+	Severity Severity    `json:"severity"`
+	Location string      `json:"location,omitempty"` // clé brute concernée (vide si sans objet)
+	Date     models.Date `json:"date"`               // date concernée (nulle si sans objet)
+	Message  string      `json:"message"`
+}
+
+// ConsistencyReport regroupe les incohérences d'un artiste
+type ConsistencyReport struct {
+	ArtistID   int     `json:"artistId"`
+	ArtistName string  `json:"artistName"`
+	Issues     []Issue `json:"issues"`
+}
+
+// OK indique qu'aucune incohérence n'a été trouvée
+func (r ConsistencyReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Count retourne le nombre d'incohérences d'une gravité donnée
+func (r ConsistencyReport) Count(severity Severity) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Worst retourne la gravité maximale du rapport (SeverityInfo si vide)
+func (r ConsistencyReport) Worst() Severity {
+	worst := SeverityInfo
+	for _, issue := range r.Issues {
+		if issue.Severity > worst {
+			worst = issue.Severity
+		}
+	}
+	return worst
+}
+
+// ConsistencyChecker recoupe les trois endpoints par artiste
+type ConsistencyChecker struct {
+	// Now donne la date de contrôle des concerts futurs (time.Now par défaut)
+	Now func() time.Time
+}
+
+// NewConsistencyChecker crée un vérificateur basé sur l'heure courante
+func NewConsistencyChecker() *ConsistencyChecker {
+	return &ConsistencyChecker{Now: time.Now}
+}
+
+// CheckAll vérifie tous les artistes et retourne les rapports triés par ID
+func (c *ConsistencyChecker) CheckAll(aggregates map[int]models.ArtistAggregate) []ConsistencyReport {
+	reports := make([]ConsistencyReport, 0, len(aggregates))
+	for _, id := range sortedIDs(aggregates) {
+		reports = append(reports, c.Check(aggregates[id]))
+	}
+	return reports
+}
+
+// Check recoupe Locations, Dates et Relation d'un artiste
+func (c *ConsistencyChecker) Check(aggregate models.ArtistAggregate) ConsistencyReport {
+	artist := aggregate.Artist
+	report := ConsistencyReport{ArtistID: artist.ID, ArtistName: artist.Name}
+	add := func(issue Issue) {
+		report.Issues = append(report.Issues, issue)
+	}
+
+	// IDs des endpoints (0 = endpoint non renseigné, ignoré)
+	for _, endpoint := range []struct {
+		name string
+		id   int
+	}{
+		{"locations", aggregate.Locations.ID},
+		{"dates", aggregate.Dates.ID},
+		{"relation", aggregate.Relation.ID},
+	} {
+		if endpoint.id != 0 && endpoint.id != artist.ID {
+			add(Issue{Kind: IssueIDMismatch, Severity: SeverityError,
+				Message: fmt.Sprintf("%s porte l'ID %d au lieu de %d", endpoint.name, endpoint.id, artist.ID)})
+		}
+	}
+
+	// Premier album (une date absente n'est pas signalée)
+	if !artist.FirstAlbum.Valid() {
+		add(Issue{Kind: IssueInvalidDate, Severity: SeverityError, Date: artist.FirstAlbum,
+			Message: fmt.Sprintf("%s pour le premier album", describeInvalidDate(artist.FirstAlbum))})
+	}
+
+	// Lieux : doublons, puis recoupement avec les clés de Relation
+	locations := make(map[string]bool)
+	for _, location := range aggregate.Locations.Locations {
+		if locations[location] {
+			add(Issue{Kind: IssueDuplicateLocation, Severity: SeverityWarning, Location: location,
+				Message: fmt.Sprintf("lieu %s listé plusieurs fois", location)})
+		}
+		locations[location] = true
+	}
+
+	relationKeys := make([]string, 0, len(aggregate.Relation.DatesLocations))
+	for location := range aggregate.Relation.DatesLocations {
+		relationKeys = append(relationKeys, location)
+	}
+	sort.Strings(relationKeys)
+
+	for _, location := range aggregate.Locations.Locations {
+		if _, ok := aggregate.Relation.DatesLocations[location]; !ok && locations[location] {
+			locations[location] = false // une seule alerte par lieu, même en double
+			add(Issue{Kind: IssueOrphanLocation, Severity: SeverityWarning, Location: location,
+				Message: fmt.Sprintf("lieu %s sans date dans la relation", location)})
+		}
+	}
+	for _, location := range relationKeys {
+		if _, ok := locations[location]; !ok {
+			add(Issue{Kind: IssueUnknownLocation, Severity: SeverityWarning, Location: location,
+				Message: fmt.Sprintf("lieu %s présent dans la relation mais pas dans les locations", location)})
+		}
+	}
+
+	// Dates de la relation : validité, doublons, anomalies temporelles
+	relationDates := make(map[time.Time]int)
+	now := c.now()
+	for _, location := range relationKeys {
+		seen := make(map[time.Time]bool)
+		for _, date := range aggregate.Relation.DatesLocations[location] {
+			if date.IsZero() {
+				add(Issue{Kind: IssueInvalidDate, Severity: SeverityError, Location: location, Date: date,
+					Message: fmt.Sprintf("%s pour %s", describeInvalidDate(date), location)})
+				continue
+			}
+			if seen[date.Time] {
+				add(Issue{Kind: IssueDuplicateDate, Severity: SeverityWarning, Location: location, Date: date,
+					Message: fmt.Sprintf("concert du %s en double à %s", FormatDate(date), location)})
+				continue
+			}
+			seen[date.Time] = true
+			relationDates[date.Time]++
+
+			if date.Time.After(now) {
+				add(Issue{Kind: IssueFutureDate, Severity: SeverityInfo, Location: location, Date: date,
+					Message: fmt.Sprintf("concert futur le %s à %s", FormatDate(date), location)})
+			}
+			if artist.CreationDate > 0 && date.Year() < artist.CreationDate {
+				add(Issue{Kind: IssuePastDate, Severity: SeverityWarning, Location: location, Date: date,
+					Message: fmt.Sprintf("concert le %s, avant la création du groupe (%d)", FormatDate(date), artist.CreationDate)})
+			}
+		}
+	}
+
+	// Dates de l'endpoint dates, comparées (comme multi-ensembles) à la relation
+	dates := make(map[time.Time]int)
+	for _, date := range aggregate.Dates.Dates {
+		if date.IsZero() {
+			add(Issue{Kind: IssueInvalidDate, Severity: SeverityError, Date: date,
+				Message: fmt.Sprintf("%s dans la liste des dates", describeInvalidDate(date))})
+			continue
+		}
+		dates[date.Time]++
+		if dates[date.Time] > relationDates[date.Time] {
+			add(Issue{Kind: IssueMissingDate, Severity: SeverityWarning, Date: date,
+				Message: fmt.Sprintf("date %s absente de la relation", FormatDate(date))})
+		}
+	}
+	for _, location := range relationKeys {
+		for _, date := range aggregate.Relation.DatesLocations[location] {
+			if date.IsZero() || relationDates[date.Time] <= dates[date.Time] {
+				continue
+			}
+			relationDates[date.Time]-- // une seule alerte par occurrence manquante
+			add(Issue{Kind: IssueExtraDate, Severity: SeverityWarning, Location: location, Date: date,
+				Message: fmt.Sprintf("concert du %s à %s absent de la liste des dates", FormatDate(date), location)})
+		}
+	}
+
+	return report
+}
+
+// describeInvalidDate décrit une date vide ou illisible, valeur d'origine comprise
+func describeInvalidDate(date models.Date) string {
+	if !date.Valid() {
+		return fmt.Sprintf("date illisible %q", date.Raw())
+	}
+	return "date vide"
+}
+
+func (c *ConsistencyChecker) now() time.Time {
+	if c.Now == nil {
+		return time.Now()
+	}
+	return c.Now()
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"groupie-tracker/models"
+)
+
+func fixedChecker() *ConsistencyChecker {
+	return &ConsistencyChecker{Now: func() time.Time { return time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC) }}
+}
+
+func consistentAggregate() models.ArtistAggregate {
+	return models.ArtistAggregate{
+		Artist:    models.Artist{ID: 1, Name: "Queen", CreationDate: 1970},
+		Locations: models.Location{ID: 1, Locations: []string{"london-uk", "paris-france"}},
+		Dates:     models.ConcertDate{ID: 1, Dates: testDates("*12-07-1986", "11-07-1986", "*14-06-1986")},
+		Relation: models.Relation{ID: 1, DatesLocations: map[string][]models.Date{
+			"london-uk":    testDates("12-07-1986", "11-07-1986"),
+			"paris-france": testDates("14-06-1986"),
+		}},
+	}
+}
+
+func issueKinds(report ConsistencyReport) map[IssueKind]int {
+	kinds := make(map[IssueKind]int)
+	for _, issue := range report.Issues {
+		kinds[issue.Kind]++
+	}
+	return kinds
+}
+
+func TestConsistencyChecker_Consistent(t *testing.T) {
+	report := fixedChecker().Check(consistentAggregate())
+
+	if !report.OK() {
+		t.Errorf("Aucune incohérence attendue, got %+v", report.Issues)
+	}
+	if report.ArtistID != 1 || report.ArtistName != "Queen" {
+		t.Errorf("Rapport mal identifié: %+v", report)
+	}
+}
+
+func TestConsistencyChecker_Issues(t *testing.T) {
+	aggregate := consistentAggregate()
+	aggregate.Dates.ID = 2
+	aggregate.Locations.Locations = []string{"london-uk", "paris-france", "paris-france", "tokyo-japan"}
+	aggregate.Dates.Dates = testDates("12-07-1986", "11-07-1986", "14-06-1986", "01-01-1999", "")
+	aggregate.Relation.DatesLocations = map[string][]models.Date{
+		"london-uk":      testDates("12-07-1986", "11-07-1986", "11-07-1986"),
+		"paris-france":   testDates("14-06-1986", "05-05-1965"),
+		"berlin-germany": testDates("01-06-2022"),
+	}
+
+	report := fixedChecker().Check(aggregate)
+	kinds := issueKinds(report)
+
+	expected := map[IssueKind]int{
+		IssueIDMismatch:        1,
+		IssueDuplicateLocation: 1,
+		IssueOrphanLocation:    1, // tokyo
+		IssueUnknownLocation:   1, // berlin
+		IssueDuplicateDate:     1, // 11-07-1986 à Londres
+		IssueInvalidDate:       1,
+		IssueMissingDate:       1, // 01-01-1999
+		IssueExtraDate:         2, // 05-05-1965 et 01-06-2022
+		IssueFutureDate:        1, // 2022
+		IssuePastDate:          1, // 1965 < 1970
+	}
+	for kind, count := range expected {
+		if kinds[kind] != count {
+			t.Errorf("%s: attendu %d, got %d", kind, count, kinds[kind])
+		}
+	}
+	if len(report.Issues) != 11 {
+		t.Errorf("Attendu 11 incohérences, got %d: %+v", len(report.Issues), report.Issues)
+	}
+
+	if report.Worst() != SeverityError || report.Count(SeverityInfo) != 1 {
+		t.Errorf("Gravités inattendues: pire=%v, infos=%d", report.Worst(), report.Count(SeverityInfo))
+	}
+}
+
+func TestConsistencyChecker_UnparseableDates(t *testing.T) {
+	aggregate := consistentAggregate()
+	for _, part := range []struct {
+		json   string
+		target any
+	}{
+		{`{"id": 1, "firstAlbum": "1973/12/14", "creationDate": 1970}`, &aggregate.Artist},
+		{`{"id": 1, "dates": ["*12-07-1986", "11-07-1986", "*14-06-1986", "31-02-1986"]}`, &aggregate.Dates},
+		{`{"id": 1, "datesLocations": {"london-uk": ["12-07-1986", "11-07-1986"], "paris-france": ["14-06-1986", "juin 1986"]}}`, &aggregate.Relation},
+	} {
+		if err := json.Unmarshal([]byte(part.json), part.target); err != nil {
+			t.Fatalf("Une date illisible ne doit pas faire échouer le décodage: %v", err)
+		}
+	}
+
+	report := fixedChecker().Check(aggregate)
+
+	messages := []string{}
+	for _, issue := range report.Issues {
+		if issue.Kind == IssueInvalidDate {
+			messages = append(messages, issue.Message)
+		}
+	}
+	expected := []string{
+		`date illisible "1973/12/14" pour le premier album`,
+		`date illisible "juin 1986" pour paris-france`,
+		`date illisible "31-02-1986" dans la liste des dates`,
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Dates illisibles signalées:\n got %q\nwant %q", messages, expected)
+	}
+}
+
+func TestConsistencyChecker_CheckAllSorted(t *testing.T) {
+	first := consistentAggregate()
+	second := consistentAggregate()
+	second.Artist = models.Artist{ID: 7, Name: "Pink Floyd"}
+	second.Locations.ID, second.Dates.ID, second.Relation.ID = 7, 7, 7
+
+	reports := fixedChecker().CheckAll(map[int]models.ArtistAggregate{7: second, 1: first})
+
+	if len(reports) != 2 || reports[0].ArtistID != 1 || reports[1].ArtistID != 7 {
+		t.Errorf("Rapports non triés par ID: %+v", reports)
+	}
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"groupie-tracker/models"
+)
+
+// ChangeKind indique la nature d'un changement entre deux versions
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// ArtistChange décrit un artiste ajouté, retiré ou modifié
+type ArtistChange struct {
+	Kind       ChangeKind `json:"kind"`
+	ArtistID   int        `json:"artist_id"`
+	ArtistName string     `json:"artist_name"`
+	Fields     []string   `json:"fields,omitempty"` // champs modifiés ("name", "members"...)
+}
+
+// LocationChange décrit un lieu ajouté ou retiré pour un artiste
+type LocationChange struct {
+	Kind       ChangeKind `json:"kind"`
+	ArtistID   int        `json:"artist_id"`
+	ArtistName string     `json:"artist_name"`
+	Location   string     `json:"location"` // clé brute de l'API
+}
+
+// DateChange décrit un concert (lieu + date) ajouté ou retiré
+type DateChange struct {
+	Kind       ChangeKind  `json:"kind"`
+	ArtistID   int         `json:"artist_id"`
+	ArtistName string      `json:"artist_name"`
+	Location   string      `json:"location"`
+	Date       models.Date `json:"date"`
+}
+
+// DatasetDiff liste les différences entre deux versions du jeu de données
+type DatasetDiff struct {
+	From      time.Time        `json:"from"` // date de la version précédente
+	To        time.Time        `json:"to"`   // date de la nouvelle version
+	Artists   []ArtistChange   `json:"artists,omitempty"`
+	Locations []LocationChange `json:"locations,omitempty"`
+	Dates     []DateChange     `json:"dates,omitempty"`
+}
+
+// Empty indique qu'aucune différence n'a été trouvée
+func (d DatasetDiff) Empty() bool {
+	return len(d.Artists) == 0 && len(d.Locations) == 0 && len(d.Dates) == 0
+}
+
+// Summary résume le diff, ex. "1 artiste, 2 lieux, 5 dates"
+func (d DatasetDiff) Summary() string {
+	if d.Empty() {
+		return "aucun changement"
+	}
+
+	parts := []string{}
+	for _, count := range []struct {
+		n        int
+		singular string
+		plural   string
+	}{
+		{len(d.Artists), "artiste", "artistes"},
+		{len(d.Locations), "lieu", "lieux"},
+		{len(d.Dates), "date", "dates"},
+	} {
+		switch {
+		case count.n == 1:
+			parts = append(parts, "1 "+count.singular)
+		case count.n > 1:
+			parts = append(parts, fmt.Sprintf("%d %s", count.n, count.plural))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DiffSnapshots compare deux versions du jeu de données (previous peut être nil)
+func DiffSnapshots(previous, current *Snapshot) DatasetDiff {
+	diff := DatasetDiff{To: current.SavedAt}
+
+	oldArtists := map[int]models.Artist{}
+	oldAggregates := map[int]models.ArtistAggregate{}
+	if previous != nil {
+		diff.From = previous.SavedAt
+		for _, artist := range previous.Artists {
+			oldArtists[artist.ID] = artist
+		}
+		oldAggregates = previous.AggregateMap()
+	}
+
+	newArtists := map[int]models.Artist{}
+	for _, artist := range current.Artists {
+		newArtists[artist.ID] = artist
+	}
+	newAggregates := current.AggregateMap()
+
+	ids := map[int]bool{}
+	for id := range oldArtists {
+		ids[id] = true
+	}
+	for id := range newArtists {
+		ids[id] = true
+	}
+	sorted := make([]int, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Ints(sorted)
+
+	for _, id := range sorted {
+		before, existed := oldArtists[id]
+		after, exists := newArtists[id]
+
+		name := after.Name
+		switch {
+		case !exists:
+			name = before.Name
+			diff.Artists = append(diff.Artists, ArtistChange{Kind: ChangeRemoved, ArtistID: id, ArtistName: name})
+		case !existed:
+			diff.Artists = append(diff.Artists, ArtistChange{Kind: ChangeAdded, ArtistID: id, ArtistName: name})
+		default:
+			if fields := changedArtistFields(before, after); len(fields) > 0 {
+				diff.Artists = append(diff.Artists, ArtistChange{Kind: ChangeChanged, ArtistID: id, ArtistName: name, Fields: fields})
+			}
+		}
+
+		oldAggregate := oldAggregates[id]
+		newAggregate := newAggregates[id]
+		if existed && exists {
+			// Agrégat absent d'un côté (chargement incomplet) : rien à comparer
+			if _, ok := oldAggregates[id]; !ok {
+				continue
+			}
+			if _, ok := newAggregates[id]; !ok {
+				continue
+			}
+		}
+
+		for _, location := range diffStrings(oldAggregate.Locations.Locations, newAggregate.Locations.Locations) {
+			diff.Locations = append(diff.Locations, LocationChange{Kind: location.kind, ArtistID: id, ArtistName: name, Location: location.value})
+		}
+		diff.Dates = append(diff.Dates, diffConcerts(id, name, oldAggregate.Relation, newAggregate.Relation)...)
+	}
+
+	return diff
+}
+
+// changedArtistFields liste les champs qui diffèrent entre deux versions d'un artiste
+func changedArtistFields(before, after models.Artist) []string {
+	fields := []string{}
+	if before.Name != after.Name {
+		fields = append(fields, "name")
+	}
+	if before.Image != after.Image {
+		fields = append(fields, "image")
+	}
+	if strings.Join(before.Members, "\x00") != strings.Join(after.Members, "\x00") {
+		fields = append(fields, "members")
+	}
+	if before.CreationDate != after.CreationDate {
+		fields = append(fields, "creationDate")
+	}
+	// Date, "*" et valeur d'origine d'une date illisible
+	if !before.FirstAlbum.Time.Equal(after.FirstAlbum.Time) || before.FirstAlbum.Starred != after.FirstAlbum.Starred ||
+		before.FirstAlbum.Raw() != after.FirstAlbum.Raw() {
+		fields = append(fields, "firstAlbum")
+	}
+	return fields
+}
+
+type stringChange struct {
+	kind  ChangeKind
+	value string
+}
+
+// diffStrings retourne les valeurs ajoutées puis retirées (ordre trié)
+func diffStrings(before, after []string) []stringChange {
+	oldSet := make(map[string]bool, len(before))
+	for _, value := range before {
+		oldSet[value] = true
+	}
+	newSet := make(map[string]bool, len(after))
+	for _, value := range after {
+		newSet[value] = true
+	}
+
+	changes := []stringChange{}
+	for _, set := range []struct {
+		kind ChangeKind
+		from map[string]bool
+		to   map[string]bool
+	}{
+		{ChangeAdded, newSet, oldSet},
+		{ChangeRemoved, oldSet, newSet},
+	} {
+		values := []string{}
+		for value := range set.from {
+			if !set.to[value] {
+				values = append(values, value)
+			}
+		}
+		sort.Strings(values)
+		for _, value := range values {
+			changes = append(changes, stringChange{kind: set.kind, value: value})
+		}
+	}
+	return changes
+}
+
+// diffConcerts compare les couples (lieu, date) de deux relations
+func diffConcerts(artistID int, artistName string, before, after models.Relation) []DateChange {
+	concertKeys := func(relation models.Relation) ([]string, map[string]DateChange) {
+		keys := []string{}
+		concerts := map[string]DateChange{}
+		for location, dates := range relation.DatesLocations {
+			for _, date := range dates {
+				key := location + "|" + date.Time.Format("2006-01-02")
+				keys = append(keys, key)
+				concerts[key] = DateChange{ArtistID: artistID, ArtistName: artistName, Location: location, Date: date}
+			}
+		}
+		return keys, concerts
+	}
+
+	oldKeys, oldConcerts := concertKeys(before)
+	newKeys, newConcerts := concertKeys(after)
+
+	changes := []DateChange{}
+	for _, change := range diffStrings(oldKeys, newKeys) {
+		concert := newConcerts[change.value]
+		if change.kind == ChangeRemoved {
+			concert = oldConcerts[change.value]
+		}
+		concert.Kind = change.kind
+		changes = append(changes, concert)
+	}
+	return changes
+}
+
+// =====================
+// HISTORIQUE DES VERSIONS
+// =====================
+
+// DatasetHistory conserve les dernières versions du jeu de données et
+// l'historique des différences entre versions successives
+type DatasetHistory struct {
+	mu          sync.Mutex
+	dir         string
+	maxVersions int // versions complètes conservées sur disque
+	maxDiffs    int // entrées conservées dans l'historique des changements
+}
+
+// DefaultDatasetHistoryDir retourne le dossier des versions (à côté du snapshot)
+func DefaultDatasetHistoryDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".groupie-tracker", "versions")
+}
+
+// NewDatasetHistory crée un historique stocké dans dir
+func NewDatasetHistory(dir string, maxVersions int) *DatasetHistory {
+	if maxVersions < 1 {
+		maxVersions = 1
+	}
+	return &DatasetHistory{dir: dir, maxVersions: maxVersions, maxDiffs: 100}
+}
+
+// Record enregistre une nouvelle version et retourne sa différence avec la
+// précédente. Retourne nil si c'est la première version ou si rien n'a changé
+// (la version n'est alors pas stockée).
+func (h *DatasetHistory) Record(snapshot *Snapshot) (*DatasetDiff, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	previous, err := h.latest()
+	if err != nil {
+		fmt.Printf("⚠️ Version précédente illisible, ignorée: %v\n", err)
+		previous = nil
+	}
+
+	var diff *DatasetDiff
+	if previous != nil {
+		d := DiffSnapshots(previous, snapshot)
+		if d.Empty() {
+			return nil, nil
+		}
+		diff = &d
+	}
+
+	name := "dataset-" + snapshot.SavedAt.UTC().Format("20060102T150405.000") + ".json"
+	if err := SaveSnapshot(filepath.Join(h.dir, name), snapshot); err != nil {
+		return nil, err
+	}
+	h.prune()
+
+	if diff != nil {
+		diffs, err := h.readDiffs()
+		if err != nil {
+			return diff, err
+		}
+		diffs = append([]DatasetDiff{*diff}, diffs...)
+		if len(diffs) > h.maxDiffs {
+			diffs = diffs[:h.maxDiffs]
+		}
+		if err := h.writeDiffs(diffs); err != nil {
+			return diff, err
+		}
+	}
+
+	return diff, nil
+}
+
+// Diffs retourne l'historique des changements, le plus récent en premier
+func (h *DatasetHistory) Diffs() ([]DatasetDiff, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.readDiffs()
+}
+
+// versions retourne les fichiers de version, du plus ancien au plus récent
+func (h *DatasetHistory) versions() []string {
+	files, _ := filepath.Glob(filepath.Join(h.dir, "dataset-*.json"))
+	sort.Strings(files) // horodatage dans le nom : ordre chronologique
+	return files
+}
+
+func (h *DatasetHistory) latest() (*Snapshot, error) {
+	files := h.versions()
+	if len(files) == 0 {
+		return nil, nil
+	}
+	return LoadSnapshot(files[len(files)-1])
+}
+
+// prune supprime les versions les plus anciennes au-delà de maxVersions
+func (h *DatasetHistory) prune() {
+	files := h.versions()
+	for len(files) > h.maxVersions {
+		os.Remove(files[0])
+		files = files[1:]
+	}
+}
+
+func (h *DatasetHistory) diffsPath() string {
+	return filepath.Join(h.dir, "changes.json")
+}
+
+func (h *DatasetHistory) readDiffs() ([]DatasetDiff, error) {
+	data, err := os.ReadFile(h.diffsPath())
+	if os.IsNotExist(err) {
+		return []DatasetDiff{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []DatasetDiff
+	if err := json.Unmarshal(data, &diffs); err != nil {
+		return nil, fmt.Errorf("historique des changements illisible: %w", err)
+	}
+	return diffs, nil
+}
+
+func (h *DatasetHistory) writeDiffs(diffs []DatasetDiff) error {
+	data, err := json.MarshalIndent(diffs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(h.diffsPath(), data)
+}
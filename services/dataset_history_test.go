@@ -0,0 +1,123 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"groupie-tracker/models"
+)
+
+func datasetVersion(savedAt time.Time, artists []models.Artist, aggregates map[int]models.ArtistAggregate) *Snapshot {
+	snapshot := NewSnapshot(artists, aggregates)
+	snapshot.SavedAt = savedAt
+	return snapshot
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	artists := createTestArtists()
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	previous := datasetVersion(day, artists[:2], map[int]models.ArtistAggregate{
+		1: {Artist: artists[0],
+			Locations: models.Location{Locations: []string{"london-uk", "paris-france"}},
+			Relation: models.Relation{DatesLocations: map[string][]models.Date{
+				"london-uk":    testDates("12-07-1986"),
+				"paris-france": testDates("14-06-1986"),
+			}}},
+		2: {Artist: artists[1]},
+	})
+
+	renamed := artists[1]
+	renamed.Members = append([]string{"Pete Best"}, renamed.Members...)
+	current := datasetVersion(day.Add(24*time.Hour), []models.Artist{artists[0], renamed, artists[2]}, map[int]models.ArtistAggregate{
+		1: {Artist: artists[0],
+			Locations: models.Location{Locations: []string{"london-uk", "tokyo-japan"}},
+			Relation: models.Relation{DatesLocations: map[string][]models.Date{
+				"london-uk":   testDates("12-07-1986", "*13-07-1986"),
+				"tokyo-japan": testDates("01-05-1987"),
+			}}},
+		2: {Artist: renamed},
+		3: {Artist: artists[2], Locations: models.Location{Locations: []string{"berlin-germany"}}},
+	})
+
+	diff := DiffSnapshots(previous, current)
+
+	if !diff.From.Equal(day) || !diff.To.Equal(current.SavedAt) {
+		t.Errorf("Bornes du diff incorrectes: %v -> %v", diff.From, diff.To)
+	}
+
+	if len(diff.Artists) != 2 ||
+		diff.Artists[0].Kind != ChangeChanged || diff.Artists[0].ArtistID != 2 || diff.Artists[0].Fields[0] != "members" ||
+		diff.Artists[1].Kind != ChangeAdded || diff.Artists[1].ArtistID != 3 {
+		t.Errorf("Artistes inattendus: %+v", diff.Artists)
+	}
+
+	// Artiste 1 : tokyo ajouté, paris retiré ; artiste 3 : berlin ajouté
+	if len(diff.Locations) != 3 ||
+		diff.Locations[0] != (LocationChange{Kind: ChangeAdded, ArtistID: 1, ArtistName: "Queen", Location: "tokyo-japan"}) ||
+		diff.Locations[1].Kind != ChangeRemoved || diff.Locations[1].Location != "paris-france" ||
+		diff.Locations[2].ArtistID != 3 {
+		t.Errorf("Lieux inattendus: %+v", diff.Locations)
+	}
+
+	// 13/07 à Londres et 01/05 à Tokyo ajoutés, 14/06 à Paris retiré
+	if len(diff.Dates) != 3 || diff.Dates[0].Date.String() != "*13-07-1986" || diff.Dates[2].Kind != ChangeRemoved {
+		t.Errorf("Dates inattendues: %+v", diff.Dates)
+	}
+
+	if diff.Summary() != "2 artistes, 3 lieux, 3 dates" {
+		t.Errorf("Summary = %q", diff.Summary())
+	}
+}
+
+func TestChangedArtistFields_FirstAlbum(t *testing.T) {
+	queen := createTestArtists()[0]
+
+	// Même instant dans un autre fuseau : pas de changement
+	moved := queen
+	moved.FirstAlbum.Time = queen.FirstAlbum.Time.In(time.FixedZone("CET", 3600))
+	if fields := changedArtistFields(queen, moved); len(fields) != 0 {
+		t.Errorf("Aucun changement attendu, got %v", fields)
+	}
+
+	starred := queen
+	starred.FirstAlbum.Starred = true
+	if fields := changedArtistFields(queen, starred); len(fields) != 1 || fields[0] != "firstAlbum" {
+		t.Errorf("Le \"*\" ajouté devrait être signalé, got %v", fields)
+	}
+}
+
+func TestDatasetHistory_Record(t *testing.T) {
+	history := NewDatasetHistory(t.TempDir(), 2)
+	artists := createTestArtists()
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Première version : rien à comparer
+	if diff, err := history.Record(datasetVersion(day, artists[:1], nil)); err != nil || diff != nil {
+		t.Fatalf("Première version: diff=%v, err=%v", diff, err)
+	}
+
+	// Version identique : ignorée
+	if diff, err := history.Record(datasetVersion(day.Add(time.Hour), artists[:1], nil)); err != nil || diff != nil {
+		t.Fatalf("Version identique: diff=%v, err=%v", diff, err)
+	}
+
+	for i := 2; i <= 3; i++ {
+		diff, err := history.Record(datasetVersion(day.Add(time.Duration(i)*24*time.Hour), artists[:i], nil))
+		if err != nil || diff == nil || len(diff.Artists) != 1 {
+			t.Fatalf("Version %d: diff=%+v, err=%v", i, diff, err)
+		}
+	}
+
+	diffs, err := history.Diffs()
+	if err != nil {
+		t.Fatalf("Diffs: %v", err)
+	}
+	if len(diffs) != 2 || diffs[0].Artists[0].ArtistID != 3 || diffs[1].Artists[0].ArtistID != 2 {
+		t.Errorf("Historique inattendu (plus récent en premier): %+v", diffs)
+	}
+
+	if versions := history.versions(); len(versions) != 2 {
+		t.Errorf("Seules les 2 dernières versions devraient être conservées, got %d", len(versions))
+	}
+}
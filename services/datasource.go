@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"groupie-tracker/api"
+	"groupie-tracker/models"
+)
+
+// DataSource abstrait l'origine des données (API HTTP, dump local, fixtures...).
+// Le contexte permet d'annuler les requêtes en cours.
+type DataSource interface {
+	GetArtists(ctx context.Context) ([]models.Artist, error)
+	GetLocation(ctx context.Context, id int) (models.Location, error)
+	GetDate(ctx context.Context, id int) (models.ConcertDate, error)
+	GetRelation(ctx context.Context, id int) (models.Relation, error)
+}
+
+// BulkDataSource est implémentée par les sources capables de renvoyer
+// les données de tous les artistes en une seule fois (endpoints d'index)
+type BulkDataSource interface {
+	GetAllLocations(ctx context.Context) ([]models.Location, error)
+	GetAllDates(ctx context.Context) ([]models.ConcertDate, error)
+	GetAllRelations(ctx context.Context) ([]models.Relation, error)
+}
+
+// Variables d'environnement lues au démarrage pour choisir la source
+const (
+	EnvAPIURL  = "GROUPIE_API_URL"  // ex: URL d'un miroir de staging
+	EnvDataDir = "GROUPIE_DATA_DIR" // ex: dossier contenant un dump JSON
+)
+
+// =====================
+// HTTP
+// =====================
+
+// HTTPDataSource interroge une API compatible Groupie Trackers
+type HTTPDataSource struct {
+	BaseURL string
+}
+
+// NewHTTPDataSource crée une source HTTP pointant vers baseURL
+func NewHTTPDataSource(baseURL string) *HTTPDataSource {
+	return &HTTPDataSource{BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+func (h *HTTPDataSource) GetArtists(ctx context.Context) ([]models.Artist, error) {
+	var artists []models.Artist
+	err := api.FetchJSONContext(ctx, h.BaseURL+"/artists", &artists)
+	return artists, err
+}
+
+func (h *HTTPDataSource) GetLocation(ctx context.Context, id int) (models.Location, error) {
+	var loc models.Location
+	err := api.FetchJSONContext(ctx, fmt.Sprintf("%s/locations/%d", h.BaseURL, id), &loc)
+	return loc, err
+}
+
+func (h *HTTPDataSource) GetDate(ctx context.Context, id int) (models.ConcertDate, error) {
+	var date models.ConcertDate
+	err := api.FetchJSONContext(ctx, fmt.Sprintf("%s/dates/%d", h.BaseURL, id), &date)
+	return date, err
+}
+
+func (h *HTTPDataSource) GetRelation(ctx context.Context, id int) (models.Relation, error) {
+	var relation models.Relation
+	err := api.FetchJSONContext(ctx, fmt.Sprintf("%s/relation/%d", h.BaseURL, id), &relation)
+	return relation, err
+}
+
+func (h *HTTPDataSource) GetAllLocations(ctx context.Context) ([]models.Location, error) {
+	var index models.LocationIndex
+	err := api.FetchJSONContext(ctx, h.BaseURL+"/locations", &index)
+	return index.Index, err
+}
+
+func (h *HTTPDataSource) GetAllDates(ctx context.Context) ([]models.ConcertDate, error) {
+	var index models.DateIndex
+	err := api.FetchJSONContext(ctx, h.BaseURL+"/dates", &index)
+	return index.Index, err
+}
+
+func (h *HTTPDataSource) GetAllRelations(ctx context.Context) ([]models.Relation, error) {
+	var index models.RelationIndex
+	err := api.FetchJSONContext(ctx, h.BaseURL+"/relation", &index)
+	return index.Index, err
+}
+
+// =====================
+// DOSSIER LOCAL
+// =====================
+
+// LocalDataSource lit un dump JSON qui reprend l'arborescence de l'API :
+//
+//	<dir>/artists.json
+//	<dir>/locations/<id>.json
+//	<dir>/dates/<id>.json
+//	<dir>/relation/<id>.json
+//
+// Les index optionnels locations.json, dates.json et relation.json
+// ({"index": [...]}) permettent le chargement groupé.
+type LocalDataSource struct {
+	Dir string
+}
+
+// NewLocalDataSource crée une source lisant les fichiers de dir
+func NewLocalDataSource(dir string) *LocalDataSource {
+	return &LocalDataSource{Dir: dir}
+}
+
+func (l *LocalDataSource) GetArtists(ctx context.Context) ([]models.Artist, error) {
+	var artists []models.Artist
+	err := l.readJSON(ctx, &artists, "artists.json")
+	return artists, err
+}
+
+func (l *LocalDataSource) GetLocation(ctx context.Context, id int) (models.Location, error) {
+	var loc models.Location
+	err := l.readJSON(ctx, &loc, "locations", fmt.Sprintf("%d.json", id))
+	return loc, err
+}
+
+func (l *LocalDataSource) GetDate(ctx context.Context, id int) (models.ConcertDate, error) {
+	var date models.ConcertDate
+	err := l.readJSON(ctx, &date, "dates", fmt.Sprintf("%d.json", id))
+	return date, err
+}
+
+func (l *LocalDataSource) GetRelation(ctx context.Context, id int) (models.Relation, error) {
+	var relation models.Relation
+	err := l.readJSON(ctx, &relation, "relation", fmt.Sprintf("%d.json", id))
+	return relation, err
+}
+
+func (l *LocalDataSource) GetAllLocations(ctx context.Context) ([]models.Location, error) {
+	var index models.LocationIndex
+	err := l.readJSON(ctx, &index, "locations.json")
+	return index.Index, err
+}
+
+func (l *LocalDataSource) GetAllDates(ctx context.Context) ([]models.ConcertDate, error) {
+	var index models.DateIndex
+	err := l.readJSON(ctx, &index, "dates.json")
+	return index.Index, err
+}
+
+func (l *LocalDataSource) GetAllRelations(ctx context.Context) ([]models.Relation, error) {
+	var index models.RelationIndex
+	err := l.readJSON(ctx, &index, "relation.json")
+	return index.Index, err
+}
+
+// readJSON lit et décode un fichier du dump
+func (l *LocalDataSource) readJSON(ctx context.Context, target interface{}, parts ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	path := filepath.Join(append([]string{l.Dir}, parts...)...)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &api.FetchError{Kind: api.ErrNotFound, URL: path, Err: err}
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lecture %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return &api.FetchError{Kind: api.ErrDecode, URL: path, Err: err}
+	}
+
+	return nil
+}
+
+// =====================
+// MÉMOIRE
+// =====================
+
+// MemoryDataSource sert des données déjà en mémoire (tests, fixtures)
+type MemoryDataSource struct {
+	Artists   []models.Artist
+	Locations map[int]models.Location
+	Dates     map[int]models.ConcertDate
+	Relations map[int]models.Relation
+}
+
+// NewMemoryDataSource crée une source en mémoire vide pour les artistes donnés
+func NewMemoryDataSource(artists []models.Artist) *MemoryDataSource {
+	return &MemoryDataSource{
+		Artists:   artists,
+		Locations: make(map[int]models.Location),
+		Dates:     make(map[int]models.ConcertDate),
+		Relations: make(map[int]models.Relation),
+	}
+}
+
+// AddAggregate enregistre toutes les données d'un artiste d'un coup
+func (m *MemoryDataSource) AddAggregate(aggregate models.ArtistAggregate) {
+	id := aggregate.Artist.ID
+	m.Locations[id] = aggregate.Locations
+	m.Dates[id] = aggregate.Dates
+	m.Relations[id] = aggregate.Relation
+}
+
+func (m *MemoryDataSource) GetArtists(ctx context.Context) ([]models.Artist, error) {
+	return m.Artists, nil
+}
+
+func (m *MemoryDataSource) GetLocation(ctx context.Context, id int) (models.Location, error) {
+	loc, ok := m.Locations[id]
+	if !ok {
+		return models.Location{}, fmt.Errorf("locations de l'artiste %d: %w", id, api.ErrNotFound)
+	}
+	return loc, nil
+}
+
+func (m *MemoryDataSource) GetDate(ctx context.Context, id int) (models.ConcertDate, error) {
+	date, ok := m.Dates[id]
+	if !ok {
+		return models.ConcertDate{}, fmt.Errorf("dates de l'artiste %d: %w", id, api.ErrNotFound)
+	}
+	return date, nil
+}
+
+func (m *MemoryDataSource) GetRelation(ctx context.Context, id int) (models.Relation, error) {
+	relation, ok := m.Relations[id]
+	if !ok {
+		return models.Relation{}, fmt.Errorf("relation de l'artiste %d: %w", id, api.ErrNotFound)
+	}
+	return relation, nil
+}
+
+func (m *MemoryDataSource) GetAllLocations(ctx context.Context) ([]models.Location, error) {
+	locations := make([]models.Location, 0, len(m.Locations))
+	for id, loc := range m.Locations {
+		loc.ID = id // la clé fait foi
+		locations = append(locations, loc)
+	}
+	return locations, nil
+}
+
+func (m *MemoryDataSource) GetAllDates(ctx context.Context) ([]models.ConcertDate, error) {
+	dates := make([]models.ConcertDate, 0, len(m.Dates))
+	for id, date := range m.Dates {
+		date.ID = id // la clé fait foi
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
+func (m *MemoryDataSource) GetAllRelations(ctx context.Context) ([]models.Relation, error) {
+	relations := make([]models.Relation, 0, len(m.Relations))
+	for id, relation := range m.Relations {
+		relation.ID = id // la clé fait foi
+		relations = append(relations, relation)
+	}
+	return relations, nil
+}
+
+// =====================
+// SOURCE PAR DÉFAUT
+// =====================
+
+var (
+	defaultSourceMu sync.RWMutex
+	defaultSource   DataSource = NewHTTPDataSource(API_BASE)
+)
+
+// DefaultDataSource retourne la source utilisée par les fonctions du package
+func DefaultDataSource() DataSource {
+	defaultSourceMu.RLock()
+	defer defaultSourceMu.RUnlock()
+	return defaultSource
+}
+
+// SetDefaultDataSource remplace la source utilisée par les fonctions du package
+func SetDefaultDataSource(source DataSource) {
+	defaultSourceMu.Lock()
+	defer defaultSourceMu.Unlock()
+	defaultSource = source
+}
+
+// NewDataSourceFromEnv choisit la source au démarrage :
+// GROUPIE_DATA_DIR (dump local) puis GROUPIE_API_URL (miroir), sinon l'API publique
+func NewDataSourceFromEnv() DataSource {
+	if dir := os.Getenv(EnvDataDir); dir != "" {
+		fmt.Printf("📂 Source de données: dossier local %s\n", dir)
+		return NewLocalDataSource(dir)
+	}
+
+	if baseURL := os.Getenv(EnvAPIURL); baseURL != "" {
+		fmt.Printf("🌐 Source de données: %s\n", baseURL)
+		return NewHTTPDataSource(baseURL)
+	}
+
+	return NewHTTPDataSource(API_BASE)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"groupie-tracker/api"
+	"groupie-tracker/models"
+)
+
+func TestMemoryDataSource_Aggregate(t *testing.T) {
+	artists := createTestArtists()
+	source := NewMemoryDataSource(artists)
+	source.AddAggregate(models.ArtistAggregate{
+		Artist:    artists[0],
+		Locations: models.Location{ID: 1, Locations: []string{"london-uk"}},
+		Dates:     models.ConcertDate{ID: 1, Dates: testDates("*12-07-1986")},
+		Relation:  models.Relation{ID: 1, DatesLocations: map[string][]models.Date{"london-uk": testDates("12-07-1986")}},
+	})
+
+	aggregate, err := AggregateArtistFrom(source, artists[0])
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+
+	if len(aggregate.Locations.Locations) != 1 || aggregate.Locations.Locations[0] != "london-uk" {
+		t.Errorf("Locations incorrectes: %v", aggregate.Locations.Locations)
+	}
+
+	// Artiste sans données → erreur
+	if _, err := AggregateArtistFrom(source, artists[1]); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("Un artiste sans données devrait donner api.ErrNotFound, got %v", err)
+	}
+}
+
+func TestLocalDataSource(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"artists.json":     `[{"id": 1, "name": "Queen", "members": ["Freddie Mercury"], "creationDate": 1970, "firstAlbum": "14-12-1973"}]`,
+		"locations/1.json": `{"id": 1, "locations": ["london-uk", "paris-france"]}`,
+		"dates/1.json":     `{"id": 1, "dates": ["*12-07-1986", "*14-06-1986"]}`,
+		"relation/1.json":  `{"id": 1, "datesLocations": {"london-uk": ["12-07-1986"], "paris-france": ["14-06-1986"]}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source := NewLocalDataSource(dir)
+
+	artists, err := source.GetArtists(context.Background())
+	if err != nil {
+		t.Fatalf("GetArtists erreur: %v", err)
+	}
+	if len(artists) != 1 || artists[0].Name != "Queen" {
+		t.Fatalf("Artistes incorrects: %+v", artists)
+	}
+
+	aggregate, err := AggregateArtistFrom(source, artists[0])
+	if err != nil {
+		t.Fatalf("AggregateArtistFrom erreur: %v", err)
+	}
+	if len(aggregate.Relation.DatesLocations) != 2 {
+		t.Errorf("Devrait avoir 2 lieux dans la relation, got %d", len(aggregate.Relation.DatesLocations))
+	}
+
+	if _, err := source.GetLocation(context.Background(), 42); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("Un fichier absent devrait donner api.ErrNotFound, got %v", err)
+	}
+}
+
+func TestSearchEngine_UsesDataSource(t *testing.T) {
+	artists := createTestArtists()
+	source := NewMemoryDataSource(artists)
+	source.AddAggregate(models.ArtistAggregate{
+		Artist:    artists[0],
+		Locations: models.Location{ID: 1, Locations: []string{"berlin-germany"}},
+		Dates:     models.ConcertDate{ID: 1},
+		Relation:  models.Relation{ID: 1},
+	})
+
+	engine := NewSearchEngine(artists)
+	engine.SetDataSource(source)
+
+	if err := engine.LoadAggregateData(1); err != nil {
+		t.Fatalf("LoadAggregateData erreur: %v", err)
+	}
+
+	results := engine.SearchByType("berlin", SearchTypeLocation)
+	if len(results) != 1 || results[0].ArtistID != 1 {
+		t.Errorf("Devrait trouver Berlin pour Queen, got %+v", results)
+	}
+}
+
+func TestHTTPDataSource_ReportsStaleCache(t *testing.T) {
+	api.SetResponseCache(api.NewResponseCache(t.TempDir()))
+	api.SetRetryPolicy(api.RetryPolicy{MaxAttempts: 1})
+	t.Cleanup(func() {
+		api.SetResponseCache(nil)
+		api.SetRetryPolicy(api.DefaultRetryPolicy)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id": 1, "name": "Queen", "firstAlbum": "14-12-1973"}]`))
+	}))
+	source := NewHTTPDataSource(server.URL)
+
+	// Cache chaud
+	ctx, report := api.WithFetchReport(context.Background())
+	if _, err := source.GetArtists(ctx); err != nil || report.Stale() {
+		t.Fatalf("Premier appel: err=%v, stale=%v", err, report.Stale())
+	}
+
+	// Réseau coupé : les artistes viennent du cache, signalés périmés
+	server.Close()
+	ctx, report = api.WithFetchReport(context.Background())
+	artists, err := source.GetArtists(ctx)
+	if err != nil || len(artists) != 1 {
+		t.Fatalf("Le cache devrait servir les artistes, got %v, %v", artists, err)
+	}
+	if !report.Stale() {
+		t.Error("Des artistes servis par le cache périmé doivent être signalés")
+	}
+}
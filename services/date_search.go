@@ -0,0 +1,226 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"groupie-tracker/models"
+)
+
+// =====================
+// RECHERCHE PAR DATE DE CONCERT
+// =====================
+
+// DatePrecision indique la granularité d'une date saisie
+type DatePrecision int
+
+const (
+	PrecisionDay   DatePrecision = iota // 12-07-2019, 12 juillet 2019
+	PrecisionMonth                      // 07/2019, juillet 2019
+	PrecisionYear                       // 2019
+	PrecisionRange                      // 2018..2020
+)
+
+// DateQuery est une période reconnue dans une saisie de recherche
+type DateQuery struct {
+	From      time.Time // premier jour inclus (UTC)
+	To        time.Time // dernier jour inclus (UTC)
+	Precision DatePrecision
+}
+
+// Contains indique si t tombe dans la période
+func (q DateQuery) Contains(t time.Time) bool {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return !day.Before(q.From) && !day.After(q.To)
+}
+
+var (
+	dayPattern      = regexp.MustCompile(`^(\d{1,2})[-/.](\d{1,2})[-/.](\d{4})$`) // 12-07-2019, 12/07/2019
+	isoDayPattern   = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)             // 2019-07-12
+	monthPattern    = regexp.MustCompile(`^(\d{1,2})[-/.](\d{4})$`)               // 07/2019
+	isoMonthPattern = regexp.MustCompile(`^(\d{4})-(\d{2})$`)                     // 2019-07
+	yearPattern     = regexp.MustCompile(`^\d{4}$`)
+)
+
+// monthNames associe les noms de mois (français et anglais, complets ou
+// abrégés, sans accents) à leur numéro
+var monthNames = func() map[string]time.Month {
+	names := make(map[string]time.Month)
+	for month, aliases := range map[time.Month]string{
+		time.January:   "janvier janv jan january",
+		time.February:  "fevrier fevr fev february feb",
+		time.March:     "mars mar march",
+		time.April:     "avril avr april apr",
+		time.May:       "mai may",
+		time.June:      "juin june jun",
+		time.July:      "juillet juil july jul",
+		time.August:    "aout august aug",
+		time.September: "septembre sept sep september",
+		time.October:   "octobre oct october",
+		time.November:  "novembre nov november",
+		time.December:  "decembre dec december",
+	} {
+		for _, alias := range strings.Fields(aliases) {
+			names[alias] = month
+		}
+	}
+	return names
+}()
+
+// ParseDateQuery reconnaît une date ou une période dans une saisie :
+// "12-07-2019", "12/07/2019", "2019-07-12", "07/2019", "juillet 2019",
+// "12 July 2019", "July 12, 2019", "2019" ou un intervalle "2018..2020".
+// Un nom de mois seul n'est pas une date ("may" reste un texte).
+func ParseDateQuery(input string) (DateQuery, bool) {
+	input = foldText(strings.TrimSpace(input))
+
+	if from, to, isRange := strings.Cut(input, ".."); isRange {
+		start, ok := parseSingleDate(strings.TrimSpace(from))
+		if !ok {
+			return DateQuery{}, false
+		}
+		end, ok := parseSingleDate(strings.TrimSpace(to))
+		if !ok || end.To.Before(start.From) {
+			return DateQuery{}, false
+		}
+		return DateQuery{From: start.From, To: end.To, Precision: PrecisionRange}, true
+	}
+
+	return parseSingleDate(input)
+}
+
+func parseSingleDate(s string) (DateQuery, bool) {
+	if m := dayPattern.FindStringSubmatch(s); m != nil {
+		return dayQuery(atoi(m[3]), atoi(m[2]), atoi(m[1]))
+	}
+	if m := isoDayPattern.FindStringSubmatch(s); m != nil {
+		return dayQuery(atoi(m[1]), atoi(m[2]), atoi(m[3]))
+	}
+	if m := monthPattern.FindStringSubmatch(s); m != nil {
+		return monthQuery(atoi(m[2]), atoi(m[1]))
+	}
+	if m := isoMonthPattern.FindStringSubmatch(s); m != nil {
+		return monthQuery(atoi(m[1]), atoi(m[2]))
+	}
+	if yearPattern.MatchString(s) {
+		year := atoi(s)
+		return DateQuery{From: dayTime(year, 1, 1), To: dayTime(year, 12, 31), Precision: PrecisionYear}, true
+	}
+	return parseWrittenDate(s)
+}
+
+// parseWrittenDate lit une date avec un mois en toutes lettres :
+// "juillet 2019", "12 juillet 2019", "1er juillet 2019", "july 12, 2019"
+func parseWrittenDate(s string) (DateQuery, bool) {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '.'
+	})
+
+	var month time.Month
+	day, year := 0, 0
+	for _, word := range words {
+		if m, ok := monthNames[word]; ok && month == 0 {
+			month = m
+			continue
+		}
+		word = strings.TrimSuffix(strings.TrimSuffix(word, "er"), "th")
+		n, err := strconv.Atoi(word)
+		switch {
+		case err != nil:
+			return DateQuery{}, false
+		case len(word) == 4 && year == 0:
+			year = n
+		case len(word) <= 2 && day == 0:
+			day = n
+		default:
+			return DateQuery{}, false
+		}
+	}
+
+	if month == 0 || year == 0 {
+		return DateQuery{}, false
+	}
+	if day == 0 {
+		return monthQuery(year, int(month))
+	}
+	return dayQuery(year, int(month), day)
+}
+
+func dayQuery(year, month, day int) (DateQuery, bool) {
+	date := dayTime(year, month, day)
+	// time.Date normalise les dates impossibles (31/02) : on les refuse
+	if date.Year() != year || int(date.Month()) != month || date.Day() != day {
+		return DateQuery{}, false
+	}
+	return DateQuery{From: date, To: date, Precision: PrecisionDay}, true
+}
+
+func monthQuery(year, month int) (DateQuery, bool) {
+	if month < 1 || month > 12 {
+		return DateQuery{}, false
+	}
+	from := dayTime(year, month, 1)
+	return DateQuery{From: from, To: from.AddDate(0, 1, -1), Precision: PrecisionMonth}, true
+}
+
+func dayTime(year, month, day int) time.Time {
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// searchConcerts retourne un résultat SearchTypeConcert par concert de la
+// période, dans l'ordre chronologique
+func (se *SearchEngine) searchConcerts(query DateQuery) []SearchResult {
+	state := se.store.State()
+	names := make(map[int]string, len(state.Artists))
+	for _, artist := range state.Artists {
+		names[artist.ID] = artist.Name
+	}
+
+	results := []SearchResult{}
+	seen := make(map[string]bool)
+	for _, concert := range NewConcertService(se.store).Between(query.From, query.To) {
+		name, known := names[concert.ArtistID]
+		key := fmt.Sprintf("%d-%s-%s", concert.ArtistID, concert.Location, concert.Date.Format(models.DateLayout))
+		if !known || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		matched := FormatDate(models.Date{Time: concert.Date})
+		results = append(results, SearchResult{
+			ArtistID:    concert.ArtistID,
+			ArtistName:  name,
+			MatchedText: matched,
+			Type:        SearchTypeConcert,
+			Score:       concertScore(query.Precision),
+			MatchStart:  0,
+			MatchEnd:    len(matched), // JJ/MM/AAAA : ASCII
+			Location:    concert.Place.String(),
+		})
+	}
+	return results
+}
+
+// concertScore : plus la date saisie est précise, plus le concert est pertinent
+func concertScore(precision DatePrecision) int {
+	score := 100 + 150 // score de base + bonus du type concert
+	switch precision {
+	case PrecisionDay:
+		score += 1000
+	case PrecisionMonth:
+		score += 500
+	case PrecisionYear:
+		score += 300
+	default:
+		score += 200
+	}
+	return score
+}
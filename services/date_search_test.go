@@ -0,0 +1,127 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateQuery(t *testing.T) {
+	tests := []struct {
+		input     string
+		from, to  string // JJ-MM-AAAA
+		precision DatePrecision
+	}{
+		{"12-07-2019", "12-07-2019", "12-07-2019", PrecisionDay},
+		{"12/07/2019", "12-07-2019", "12-07-2019", PrecisionDay},
+		{"1.7.2019", "01-07-2019", "01-07-2019", PrecisionDay},
+		{"2019-07-12", "12-07-2019", "12-07-2019", PrecisionDay},
+		{"12/2019", "01-12-2019", "31-12-2019", PrecisionMonth},
+		{"02-2020", "01-02-2020", "29-02-2020", PrecisionMonth},
+		{"2019-07", "01-07-2019", "31-07-2019", PrecisionMonth},
+		{"2019", "01-01-2019", "31-12-2019", PrecisionYear},
+		{"juillet 2019", "01-07-2019", "31-07-2019", PrecisionMonth},
+		{"Février 2020", "01-02-2020", "29-02-2020", PrecisionMonth},
+		{"1er août 2019", "01-08-2019", "01-08-2019", PrecisionDay},
+		{"July 12, 2019", "12-07-2019", "12-07-2019", PrecisionDay},
+		{"12th dec. 2019", "12-12-2019", "12-12-2019", PrecisionDay},
+		{"2018..2020", "01-01-2018", "31-12-2020", PrecisionRange},
+		{"03/2019 .. 12/07/2019", "01-03-2019", "12-07-2019", PrecisionRange},
+	}
+
+	for _, tt := range tests {
+		query, ok := ParseDateQuery(tt.input)
+		if !ok {
+			t.Errorf("ParseDateQuery(%q) non reconnue", tt.input)
+			continue
+		}
+		if got := query.From.Format("02-01-2006"); got != tt.from {
+			t.Errorf("ParseDateQuery(%q).From = %s, attendu %s", tt.input, got, tt.from)
+		}
+		if got := query.To.Format("02-01-2006"); got != tt.to {
+			t.Errorf("ParseDateQuery(%q).To = %s, attendu %s", tt.input, got, tt.to)
+		}
+		if query.Precision != tt.precision {
+			t.Errorf("ParseDateQuery(%q).Precision = %d, attendu %d", tt.input, query.Precision, tt.precision)
+		}
+	}
+
+	for _, input := range []string{"", "queen", "may", "brian may", "31-02-2019", "13/2019", "12", "2020..2018", "19", "2019 2020"} {
+		if _, ok := ParseDateQuery(input); ok {
+			t.Errorf("ParseDateQuery(%q) ne devrait pas être une date", input)
+		}
+	}
+}
+
+func TestDateQuery_Contains(t *testing.T) {
+	query, _ := ParseDateQuery("12/2019")
+	for date, expected := range map[time.Time]bool{
+		time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC):   true,
+		time.Date(2019, 12, 31, 23, 0, 0, 0, time.UTC): true,
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC):    false,
+	} {
+		if query.Contains(date) != expected {
+			t.Errorf("Contains(%v) = %v", date, !expected)
+		}
+	}
+}
+
+func TestSearchEngine_ConcertDates(t *testing.T) {
+	engine := queryTestEngine()
+	defer engine.Close()
+
+	results := engine.SearchByType("2019", SearchTypeConcert)
+	if len(results) != 1 {
+		t.Fatalf("Attendu 1 concert en 2019, got %+v", results)
+	}
+	concert := results[0]
+	if concert.ArtistID != 1 || concert.MatchedText != "20/04/2019" || concert.Location != "Los Angeles, États-Unis" {
+		t.Errorf("Concert inattendu: %+v", concert)
+	}
+	if _, match, _ := engine.HighlightMatch(concert); match != "20/04/2019" {
+		t.Errorf("La date entière devrait être surlignée, got %q", match)
+	}
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"20/04/2019", 1},
+		{"04/2019", 1},
+		{"avril 2019", 1},
+		{"April 20, 2019", 1},
+		{"2019..2020", 3},
+		{"1960", 1},
+		{"2021", 0},
+	}
+	for _, tt := range tests {
+		if got := engine.SearchByType(tt.query, SearchTypeConcert); len(got) != tt.expected {
+			t.Errorf("%q: attendu %d concerts, got %+v", tt.query, tt.expected, got)
+		}
+	}
+
+	// Les résultats textuels restent présents : "1973" trouve le premier album de Queen
+	if got := engine.SearchByType("1973", SearchTypeDate); len(got) != 1 {
+		t.Errorf("Premier album attendu pour 1973, got %+v", got)
+	}
+}
+
+func TestSearchQuery_ConcertFormats(t *testing.T) {
+	engine := queryTestEngine()
+	defer engine.Close()
+
+	for query, expected := range map[string]int{
+		"concert:04/2019":    1,
+		"concert:2019-04":    1,
+		"concert:20/04/2019": 1,
+		"concert:21/04/2019": 0,
+	} {
+		parsed, err := ParseQuery(query)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", query, err)
+			continue
+		}
+		if got := engine.SearchQuery(parsed); len(got) != expected {
+			t.Errorf("%q: attendu %d, got %+v", query, expected, got)
+		}
+	}
+}
@@ -1,22 +1,27 @@
 package services
 
 import (
-	"fmt"
+	"context"
 
-	"groupie-tracker/api"
 	"groupie-tracker/models"
 )
 
 const API_BASE = "https://groupietrackers.herokuapp.com/api"
 
+// Les fonctions ci-dessous passent par la source par défaut
+// (voir DefaultDataSource / SetDefaultDataSource dans datasource.go).
+// Les variantes *Context permettent d'annuler la requête.
+
 // =====================
 // ARTISTS
 // =====================
 
 func GetArtists() ([]models.Artist, error) {
-	var artists []models.Artist
-	err := api.FetchJSON(API_BASE+"/artists", &artists)
-	return artists, err
+	return GetArtistsContext(context.Background())
+}
+
+func GetArtistsContext(ctx context.Context) ([]models.Artist, error) {
+	return DefaultDataSource().GetArtists(ctx)
 }
 
 // =====================
@@ -24,10 +29,11 @@ func GetArtists() ([]models.Artist, error) {
 // =====================
 
 func GetLocation(id int) (models.Location, error) {
-	var loc models.Location
-	url := fmt.Sprintf("%s/locations/%d", API_BASE, id)
-	err := api.FetchJSON(url, &loc)
-	return loc, err
+	return GetLocationContext(context.Background(), id)
+}
+
+func GetLocationContext(ctx context.Context, id int) (models.Location, error) {
+	return DefaultDataSource().GetLocation(ctx, id)
 }
 
 // =====================
@@ -35,10 +41,11 @@ func GetLocation(id int) (models.Location, error) {
 // =====================
 
 func GetDate(id int) (models.ConcertDate, error) {
-	var date models.ConcertDate
-	url := fmt.Sprintf("%s/dates/%d", API_BASE, id)
-	err := api.FetchJSON(url, &date)
-	return date, err
+	return GetDateContext(context.Background(), id)
+}
+
+func GetDateContext(ctx context.Context, id int) (models.ConcertDate, error) {
+	return DefaultDataSource().GetDate(ctx, id)
 }
 
 // =====================
@@ -46,8 +53,9 @@ func GetDate(id int) (models.ConcertDate, error) {
 // =====================
 
 func GetRelation(id int) (models.Relation, error) {
-	var relation models.Relation
-	url := fmt.Sprintf("%s/relation/%d", API_BASE, id)
-	err := api.FetchJSON(url, &relation)
-	return relation, err
+	return GetRelationContext(context.Background(), id)
+}
+
+func GetRelationContext(ctx context.Context, id int) (models.Relation, error) {
+	return DefaultDataSource().GetRelation(ctx, id)
 }
@@ -1,8 +1,9 @@
 package services
 
 import (
+	"context"
 	"groupie-tracker/models"
-	"strings"
+	"sort"
 )
 
 // FilterCriteria contient tous les critères de filtrage possibles
@@ -17,6 +18,9 @@ type FilterCriteria struct {
 	MembersMin int // Nombre minimum de membres
 	MembersMax int // Nombre maximum de membres
 	
+	ConcertYearMin int // Année minimum d'au moins un concert
+	ConcertYearMax int // Année maximum d'au moins un concert
+	
 	// Filtres par location (liste de pays/villes sélectionnés)
 	Locations []string // Si vide, tous les lieux sont acceptés
 	
@@ -25,6 +29,7 @@ type FilterCriteria struct {
 	EnableFirstAlbumFilter    bool
 	EnableMembersFilter       bool
 	EnableLocationsFilter     bool
+	EnableConcertYearFilter   bool
 }
 
 // NewFilterCriteria crée des critères de filtrage par défaut (tous désactivés)
@@ -36,58 +41,69 @@ func NewFilterCriteria() *FilterCriteria {
 		FirstAlbumYearMax: 2025,
 		MembersMin:        1,
 		MembersMax:        10,
+		ConcertYearMin:    1900,
+		ConcertYearMax:    2025,
 		Locations:         []string{},
 		
 		EnableCreationDateFilter:  false,
 		EnableFirstAlbumFilter:    false,
 		EnableMembersFilter:       false,
 		EnableLocationsFilter:     false,
+		EnableConcertYearFilter:   false,
 	}
 }
 
 // FilterEngine gère le filtrage des artistes
 type FilterEngine struct {
-	artists    []models.Artist
-	aggregates map[int]models.ArtistAggregate
+	store *ArtistStore // Artistes et données agrégées partagés
 }
 
 // NewFilterEngine crée une nouvelle instance du moteur de filtrage
+// avec son propre store (source par défaut)
 func NewFilterEngine(artists []models.Artist) *FilterEngine {
-	return &FilterEngine{
-		artists:    artists,
-		aggregates: make(map[int]models.ArtistAggregate),
-	}
+	store := NewArtistStore(DefaultDataSource())
+	store.SetArtists(artists)
+	return NewFilterEngineWithStore(store)
+}
+
+// NewFilterEngineWithStore crée un moteur qui lit ses données dans un store partagé
+func NewFilterEngineWithStore(store *ArtistStore) *FilterEngine {
+	return &FilterEngine{store: store}
+}
+
+// SetDataSource change la source utilisée pour charger les données agrégées
+func (fe *FilterEngine) SetDataSource(source DataSource) {
+	fe.store.SetSource(source)
 }
 
 // LoadAggregateData charge les données agrégées pour un artiste
 func (fe *FilterEngine) LoadAggregateData(artistID int) error {
-	if _, exists := fe.aggregates[artistID]; exists {
-		return nil
-	}
+	return fe.LoadAggregateDataContext(context.Background(), artistID)
+}
 
-	var artist models.Artist
-	for _, a := range fe.artists {
-		if a.ID == artistID {
-			artist = a
-			break
-		}
-	}
+// LoadAggregateDataContext est la variante annulable de LoadAggregateData
+func (fe *FilterEngine) LoadAggregateDataContext(ctx context.Context, artistID int) error {
+	_, err := fe.store.Aggregate(ctx, artistID)
+	return err
+}
 
-	aggregate, err := AggregateArtist(artist)
-	if err != nil {
-		return err
-	}
+// SetArtists remplace la liste d'artistes (actualisation des données)
+func (fe *FilterEngine) SetArtists(artists []models.Artist) {
+	fe.store.SetArtists(artists)
+}
 
-	fe.aggregates[artistID] = aggregate
-	return nil
+// AddAggregates enregistre des données agrégées déjà chargées (chargement groupé)
+func (fe *FilterEngine) AddAggregates(aggregates map[int]models.ArtistAggregate) {
+	fe.store.AddAggregates(aggregates)
 }
 
 // ApplyFilters applique les critères de filtrage aux artistes
 func (fe *FilterEngine) ApplyFilters(criteria *FilterCriteria) []models.Artist {
 	filtered := []models.Artist{}
+	state := fe.store.State() // état cohérent pendant tout le filtrage
 
-	for _, artist := range fe.artists {
-		if fe.matchesCriteria(artist, criteria) {
+	for _, artist := range state.Artists {
+		if fe.matchesCriteria(state, artist, criteria) {
 			filtered = append(filtered, artist)
 		}
 	}
@@ -96,7 +112,7 @@ func (fe *FilterEngine) ApplyFilters(criteria *FilterCriteria) []models.Artist {
 }
 
 // matchesCriteria vérifie si un artiste correspond aux critères
-func (fe *FilterEngine) matchesCriteria(artist models.Artist, criteria *FilterCriteria) bool {
+func (fe *FilterEngine) matchesCriteria(state *StoreState, artist models.Artist, criteria *FilterCriteria) bool {
 	// Filtre par date de création
 	if criteria.EnableCreationDateFilter {
 		if artist.CreationDate < criteria.CreationDateMin || artist.CreationDate > criteria.CreationDateMax {
@@ -122,7 +138,14 @@ func (fe *FilterEngine) matchesCriteria(artist models.Artist, criteria *FilterCr
 
 	// Filtre par location (nécessite les données agrégées)
 	if criteria.EnableLocationsFilter && len(criteria.Locations) > 0 {
-		if !fe.matchesLocations(artist.ID, criteria.Locations) {
+		if !fe.matchesLocations(state, artist.ID, criteria.Locations) {
+			return false
+		}
+	}
+
+	// Filtre par année de concert (nécessite les données agrégées)
+	if criteria.EnableConcertYearFilter {
+		if !fe.matchesConcertYears(state, artist.ID, criteria.ConcertYearMin, criteria.ConcertYearMax) {
 			return false
 		}
 	}
@@ -130,45 +153,42 @@ func (fe *FilterEngine) matchesCriteria(artist models.Artist, criteria *FilterCr
 	return true
 }
 
-// extractYearFromFirstAlbum extrait l'année d'une date au format "dd-mm-yyyy"
-func (fe *FilterEngine) extractYearFromFirstAlbum(dateStr string) int {
-	date, err := ParseDate(dateStr)
-	if err != nil {
-		return 0
-	}
+// extractYearFromFirstAlbum retourne l'année du premier album (0 si inconnue)
+func (fe *FilterEngine) extractYearFromFirstAlbum(date models.Date) int {
 	return date.Year()
 }
 
 // matchesLocations vérifie si un artiste a des concerts dans les locations spécifiées
-func (fe *FilterEngine) matchesLocations(artistID int, wantedLocations []string) bool {
-	aggregate, exists := fe.aggregates[artistID]
+func (fe *FilterEngine) matchesLocations(state *StoreState, artistID int, wantedLocations []string) bool {
+	aggregate, exists := state.Aggregate(artistID)
 	if !exists {
 		return false // Si pas de données, on considère que ça ne match pas
 	}
 
-	// Normaliser les locations recherchées
-	normalizedWanted := make(map[string]bool)
-	for _, loc := range wantedLocations {
-		normalizedWanted[strings.ToLower(strings.TrimSpace(loc))] = true
-	}
-
-	// Vérifier si au moins une location de l'artiste match
+	// Vérifier si au moins une location de l'artiste match (pays par code
+	// ISO, quel que soit le jeton utilisé : "usa", "US", "États-Unis"...)
 	for _, location := range aggregate.Locations.Locations {
-		city, country := ParseLocation(location)
-		
-		// Chercher match par ville
-		if normalizedWanted[strings.ToLower(city)] {
-			return true
-		}
-		
-		// Chercher match par pays
-		if normalizedWanted[strings.ToLower(country)] {
-			return true
+		place := ParsePlace(location)
+		for _, wanted := range wantedLocations {
+			if MatchesPlace(place, wanted) {
+				return true
+			}
 		}
-		
-		// Chercher match par location complète
-		fullLoc := city + ", " + country
-		if normalizedWanted[strings.ToLower(fullLoc)] {
+	}
+
+	return false
+}
+
+// matchesConcertYears vérifie si un artiste a au moins un concert dans l'intervalle d'années
+func (fe *FilterEngine) matchesConcertYears(state *StoreState, artistID int, minYear, maxYear int) bool {
+	aggregate, exists := state.Aggregate(artistID)
+	if !exists {
+		return false
+	}
+
+	for _, concert := range ConcertsFromAggregate(aggregate) {
+		year := concert.Date.Year()
+		if year >= minYear && year <= maxYear {
 			return true
 		}
 	}
@@ -176,37 +196,49 @@ func (fe *FilterEngine) matchesLocations(artistID int, wantedLocations []string)
 	return false
 }
 
-// GetAvailableLocations retourne toutes les locations uniques disponibles
+// Concerts retourne le service de concerts partageant le store du moteur
+func (fe *FilterEngine) Concerts() *ConcertService {
+	return NewConcertService(fe.store)
+}
+
+// GetAvailableLocations retourne les pays uniques disponibles, sous leur
+// forme affichée ("🇺🇸 États-Unis"), triés par nom
 func (fe *FilterEngine) GetAvailableLocations() []string {
-	locationSet := make(map[string]bool)
-	locations := []string{}
+	countrySet := make(map[string]models.Country)
 
-	for artistID := range fe.aggregates {
-		aggregate := fe.aggregates[artistID]
+	for _, aggregate := range fe.store.State().Aggregates {
 		for _, location := range aggregate.Locations.Locations {
-			_, country := ParseLocation(location)
-			
-			// Ajouter le pays
-			if country != "" && !locationSet[country] {
-				locationSet[country] = true
-				locations = append(locations, strings.ToUpper(country))
+			country := ParsePlace(location).Country
+			if country.Name != "" {
+				countrySet[country.Name] = country
 			}
 		}
 	}
 
+	countries := make([]models.Country, 0, len(countrySet))
+	for _, country := range countrySet {
+		countries = append(countries, country)
+	}
+	sort.Slice(countries, func(i, j int) bool { return countries[i].Name < countries[j].Name })
+
+	locations := make([]string, len(countries))
+	for i, country := range countries {
+		locations[i] = country.Label()
+	}
 	return locations
 }
 
 // GetDateRange retourne le range de dates de création disponible
 func (fe *FilterEngine) GetDateRange() (min, max int) {
-	if len(fe.artists) == 0 {
+	artists := fe.store.State().Artists
+	if len(artists) == 0 {
 		return 1900, 2025
 	}
 
-	min = fe.artists[0].CreationDate
-	max = fe.artists[0].CreationDate
+	min = artists[0].CreationDate
+	max = artists[0].CreationDate
 
-	for _, artist := range fe.artists {
+	for _, artist := range artists {
 		if artist.CreationDate < min {
 			min = artist.CreationDate
 		}
@@ -220,14 +252,15 @@ func (fe *FilterEngine) GetDateRange() (min, max int) {
 
 // GetMembersRange retourne le range de nombre de membres disponible
 func (fe *FilterEngine) GetMembersRange() (min, max int) {
-	if len(fe.artists) == 0 {
+	artists := fe.store.State().Artists
+	if len(artists) == 0 {
 		return 1, 10
 	}
 
-	min = len(fe.artists[0].Members)
-	max = len(fe.artists[0].Members)
+	min = len(artists[0].Members)
+	max = len(artists[0].Members)
 
-	for _, artist := range fe.artists {
+	for _, artist := range artists {
 		memberCount := len(artist.Members)
 		if memberCount < min {
 			min = memberCount
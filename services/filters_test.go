@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"groupie-tracker/models"
 	"testing"
 )
@@ -156,10 +157,11 @@ func TestFilterEngine_ExtractYearFromFirstAlbum(t *testing.T) {
 		{"14-12-1973", 1973},
 		{"*23-08-2019", 2019},
 		{"01-01-2000", 2000},
+		{"", 0},
 	}
 
 	for _, tc := range testCases {
-		year := engine.extractYearFromFirstAlbum(tc.input)
+		year := engine.extractYearFromFirstAlbum(models.MustParseDate(tc.input))
 		if year != tc.expected {
 			t.Errorf("extractYearFromFirstAlbum(%s) = %d, want %d", tc.input, year, tc.expected)
 		}
@@ -174,13 +176,13 @@ func TestFilterEngine_FilterLocations(t *testing.T) {
 	engine := NewFilterEngine(artists)
 
 	// Simuler des données agrégées
-	engine.aggregates[1] = models.ArtistAggregate{
+	engine.AddAggregates(map[int]models.ArtistAggregate{1: {
 		Artist: artists[0],
 		Locations: models.Location{
 			ID:        1,
 			Locations: []string{"los_angeles-usa", "paris-france", "tokyo-japan"},
 		},
-	}
+	}})
 
 	criteria := NewFilterCriteria()
 	criteria.EnableLocationsFilter = true
@@ -200,13 +202,13 @@ func TestFilterEngine_FilterLocationsNoMatch(t *testing.T) {
 	}
 	engine := NewFilterEngine(artists)
 
-	engine.aggregates[1] = models.ArtistAggregate{
+	engine.AddAggregates(map[int]models.ArtistAggregate{1: {
 		Artist: artists[0],
 		Locations: models.Location{
 			ID:        1,
 			Locations: []string{"los_angeles-usa"},
 		},
-	}
+	}})
 
 	criteria := NewFilterCriteria()
 	criteria.EnableLocationsFilter = true
@@ -227,16 +229,16 @@ func TestFilterEngine_GetAvailableLocations(t *testing.T) {
 	}
 	engine := NewFilterEngine(artists)
 
-	engine.aggregates[1] = models.ArtistAggregate{
+	engine.AddAggregates(map[int]models.ArtistAggregate{1: {
 		Locations: models.Location{
 			Locations: []string{"los_angeles-usa", "paris-france"},
 		},
-	}
-	engine.aggregates[2] = models.ArtistAggregate{
+	}})
+	engine.AddAggregates(map[int]models.ArtistAggregate{2: {
 		Locations: models.Location{
 			Locations: []string{"tokyo-japan", "paris-france"},
 		},
-	}
+	}})
 
 	locations := engine.GetAvailableLocations()
 
@@ -246,6 +248,47 @@ func TestFilterEngine_GetAvailableLocations(t *testing.T) {
 	}
 }
 
+// TestFilterEngine_ConcurrentLoadAndFilter filtre pendant que les agrégats
+// sont ajoutés par lots depuis une autre goroutine. À lancer avec -race.
+func TestFilterEngine_ConcurrentLoadAndFilter(t *testing.T) {
+	source := newStoreSource()
+	store := NewArtistStore(source)
+	store.SetArtists(source.Artists)
+	engine := NewFilterEngineWithStore(store)
+
+	criteria := NewFilterCriteria()
+	criteria.EnableLocationsFilter = true
+	criteria.Locations = []string{"france"}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, artist := range source.Artists {
+			aggregate, _ := source.MemoryDataSource.GetLocation(context.Background(), artist.ID)
+			store.AddAggregates(map[int]models.ArtistAggregate{
+				artist.ID: {Artist: artist, Locations: aggregate},
+			})
+		}
+	}()
+
+	for {
+		filtered := engine.ApplyFilters(criteria)
+		locations := engine.GetAvailableLocations()
+		if len(filtered) > len(source.Artists) || len(locations) > 1 {
+			t.Fatalf("État incohérent: %d artistes, %v", len(filtered), locations)
+		}
+
+		select {
+		case <-done:
+			if filtered := engine.ApplyFilters(criteria); len(filtered) != len(source.Artists) {
+				t.Errorf("Après chargement, attendu %d artistes, got %d", len(source.Artists), len(filtered))
+			}
+			return
+		default:
+		}
+	}
+}
+
 // Benchmark
 func BenchmarkFilterEngine_ApplyFilters(b *testing.B) {
 	artists := createTestArtists()
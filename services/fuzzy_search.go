@@ -1,7 +1,9 @@
 package services
 
 import (
+	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // FuzzySearchEngine gère la recherche floue (tolérante aux fautes)
@@ -18,7 +20,7 @@ func NewFuzzySearchEngine(baseEngine *SearchEngine) *FuzzySearchEngine {
 
 // FuzzySearch effectue une recherche tolérante aux fautes de frappe
 func (fse *FuzzySearchEngine) FuzzySearch(query string, maxDistance int) []SearchResult {
-	query = strings.ToLower(strings.TrimSpace(query))
+	query = foldText(strings.TrimSpace(query))
 
 	if query == "" {
 		return []SearchResult{}
@@ -32,52 +34,100 @@ func (fse *FuzzySearchEngine) FuzzySearch(query string, maxDistance int) []Searc
 		return exactResults
 	}
 
-	// Sinon, chercher avec distance de Levenshtein
-	allResults := []SearchResult{}
+	matches := fse.fuzzyMatches(query, maxDistance)
+	results := make([]SearchResult, len(matches))
+	for i, match := range matches {
+		results[i] = match.SearchResult
+	}
+	return results
+}
+
+// fuzzyMatch est un résultat de la recherche floue et son nombre de fautes
+type fuzzyMatch struct {
+	SearchResult
+	distance int
+}
+
+// fuzzyMatches cherche, sans passer par la recherche exacte, les noms et
+// membres entiers à distance de Levenshtein au plus maxDistance de query
+// (déjà repliée), puis les noms, membres et lieux dont chaque mot de la
+// requête approche un mot ("fredie mercuri", "mercury", "los angelos"), avec
+// tokenEditBudget fautes par mot. Le meilleur score vient en premier.
+func (fse *FuzzySearchEngine) fuzzyMatches(query string, maxDistance int) []fuzzyMatch {
+	matches := []fuzzyMatch{}
 	seen := make(map[string]bool)
 
-	for _, artist := range fse.baseEngine.artists {
-		// Recherche floue sur le nom de l'artiste
-		if distance := levenshteinDistance(query, strings.ToLower(artist.Name)); distance <= maxDistance {
-			key := artist.Name + "-artist"
-			if !seen[key] {
-				score := fse.calculateFuzzyScore(artist.Name, query, distance, SearchTypeArtist)
-				allResults = append(allResults, SearchResult{
-					ArtistID:    artist.ID,
-					ArtistName:  artist.Name,
-					MatchedText: artist.Name,
-					Type:        SearchTypeArtist,
-					Score:       score,
-					MatchStart:  0,
-					MatchEnd:    len(artist.Name),
-				})
-				seen[key] = true
-			}
+	fse.baseEngine.mu.RLock()
+	idx := fse.baseEngine.index
+
+	// Texte entier : la distance est au moins égale à l'écart de longueur,
+	// seuls les noms de longueur proche sont comparés
+	queryRunes := []rune(query)
+	for _, doc := range idx.fuzzyCandidates(query, maxDistance) {
+		distance := boundedLevenshtein(queryRunes, []rune(doc.folded.text), maxDistance)
+		if distance > maxDistance || seen[doc.key] {
+			continue
 		}
+		matches = append(matches, fse.newFuzzyMatch(doc, query, distance, 0, utf8.RuneCountInString(doc.text)))
+		seen[doc.key] = true
+	}
 
-		// Recherche floue sur les membres
-		for _, member := range artist.Members {
-			if distance := levenshteinDistance(query, strings.ToLower(member)); distance <= maxDistance {
-				key := artist.Name + "-" + member
-				if !seen[key] {
-					score := fse.calculateFuzzyScore(member, query, distance, SearchTypeMember)
-					allResults = append(allResults, SearchResult{
-						ArtistID:    artist.ID,
-						ArtistName:  artist.Name,
-						MatchedText: member,
-						Type:        SearchTypeMember,
-						Score:       score,
-						MatchStart:  0,
-						MatchEnd:    len(member),
-					})
-					seen[key] = true
-				}
-			}
+	// Mot à mot
+	tokens := idx.fuzzyTokenCandidates(looseText(query), maxDistance)
+	ids := make(map[int]bool, len(tokens))
+	for docID := range tokens {
+		ids[docID] = true
+	}
+	for _, docID := range idx.orderedIDs(ids) {
+		doc := idx.docs[docID]
+		if seen[doc.key] {
+			continue
+		}
+		start, end := doc.loose.wordsSpan(tokens[docID].words)
+		if start == -1 {
+			start, end = 0, utf8.RuneCountInString(doc.text)
 		}
+		matches = append(matches, fse.newFuzzyMatch(doc, query, tokens[docID].distance, start, end))
+		seen[doc.key] = true
 	}
+	fse.baseEngine.mu.RUnlock()
 
 	// Trier par score
-	return fse.baseEngine.sortByScore(allResults)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// newFuzzyMatch construit le résultat d'un document dont les runes
+// [start, end) correspondent à la requête
+func (fse *FuzzySearchEngine) newFuzzyMatch(doc *indexedDoc, query string, distance, start, end int) fuzzyMatch {
+	return fuzzyMatch{
+		SearchResult: SearchResult{
+			ArtistID:    doc.artistID,
+			ArtistName:  doc.artistName,
+			MatchedText: doc.text,
+			Type:        doc.typ,
+			Score:       fse.calculateFuzzyScore(runeSlice(doc.text, start, end), query, distance, doc.typ),
+			MatchStart:  start,
+			MatchEnd:    end,
+		},
+		distance: distance,
+	}
+}
+
+// tokenEditBudget retourne le nombre de fautes tolérées dans un mot de la
+// requête selon sa longueur : aucune jusqu'à 3 lettres, une jusqu'à 5, deux
+// au-delà (et jamais plus de maxDistance)
+func tokenEditBudget(word string, maxDistance int) int {
+	budget := 2
+	switch length := utf8.RuneCountInString(word); {
+	case length <= 3:
+		budget = 0
+	case length <= 5:
+		budget = 1
+	}
+	return min(budget, maxDistance)
 }
 
 // calculateFuzzyScore calcule le score pour une recherche floue
@@ -101,7 +151,7 @@ func (fse *FuzzySearchEngine) calculateFuzzyScore(text, query string, distance i
 	}
 
 	// Bonus si les mots ont la même longueur
-	if len(text) == len(query) {
+	if utf8.RuneCountInString(text) == utf8.RuneCountInString(query) {
 		score += 100
 	}
 
@@ -109,10 +159,10 @@ func (fse *FuzzySearchEngine) calculateFuzzyScore(text, query string, distance i
 }
 
 // levenshteinDistance calcule la distance de Levenshtein entre deux chaînes
-// (nombre minimum d'opérations pour transformer s1 en s2)
-func levenshteinDistance(s1, s2 string) int {
-	s1 = strings.ToLower(s1)
-	s2 = strings.ToLower(s2)
+// repliées (nombre minimum d'opérations sur les runes pour transformer s1 en s2)
+func levenshteinDistance(a, b string) int {
+	s1 := []rune(foldText(a))
+	s2 := []rune(foldText(b))
 
 	len1 := len(s1)
 	len2 := len(s2)
@@ -150,6 +200,46 @@ func levenshteinDistance(s1, s2 string) int {
 	return matrix[len1][len2]
 }
 
+// boundedLevenshtein calcule la distance de Levenshtein entre deux textes
+// déjà repliés si elle ne dépasse pas limit, et retourne limit+1 sinon. Le
+// calcul s'arrête dès qu'une ligne entière de la matrice dépasse limit.
+func boundedLevenshtein(s1, s2 []rune, limit int) int {
+	if len(s1)-len(s2) > limit || len(s2)-len(s1) > limit {
+		return limit + 1
+	}
+
+	// Deux lignes de la matrice suffisent
+	previous := make([]int, len(s2)+1)
+	current := make([]int, len(s2)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s1); i++ {
+		current[0] = i
+		rowMin := i
+		for j := 1; j <= len(s2); j++ {
+			cost := 0
+			if s1[i-1] != s2[j-1] {
+				cost = 1
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if current[j] < rowMin {
+				rowMin = current[j]
+			}
+		}
+		if rowMin > limit {
+			return limit + 1 // la distance ne peut plus redescendre
+		}
+		previous, current = current, previous
+	}
+
+	if previous[len(s2)] > limit {
+		return limit + 1
+	}
+	return previous[len(s2)]
+}
+
 // min3 retourne le minimum de trois entiers
 func min3(a, b, c int) int {
 	if a < b {
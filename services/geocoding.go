@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -8,6 +9,8 @@ import (
 	"strings"
 	"time"
 	"os"
+
+	"groupie-tracker/models"
 )
 
 // Coordinates représente une position géographique
@@ -55,6 +58,26 @@ func NewGeocodingService() *GeocodingService {
 
 // Geocode convertit une adresse en coordonnées géographiques
 func (gs *GeocodingService) Geocode(location string) (*Coordinates, error) {
+	return gs.GeocodeContext(context.Background(), location)
+}
+
+// GeocodeContext est la variante annulable de Geocode
+func (gs *GeocodingService) GeocodeContext(ctx context.Context, location string) (*Coordinates, error) {
+	return gs.geocode(ctx, location, "")
+}
+
+// GeocodePlaceContext géocode un lieu canonique. Le code ISO du pays, quand
+// il est connu, restreint la recherche Nominatim à ce pays.
+func (gs *GeocodingService) GeocodePlaceContext(ctx context.Context, place models.Place) (*Coordinates, error) {
+	query := place.Query()
+	if query == "" {
+		return nil, fmt.Errorf("location invalide: %s", place.Key)
+	}
+	return gs.geocode(ctx, query, place.Country.Code)
+}
+
+// geocode interroge le cache puis Nominatim (countryCode optionnel)
+func (gs *GeocodingService) geocode(ctx context.Context, location, countryCode string) (*Coordinates, error) {
 	// Normaliser la location
 	location = strings.TrimSpace(location)
 	if location == "" {
@@ -71,7 +94,7 @@ func (gs *GeocodingService) Geocode(location string) (*Coordinates, error) {
 	}
 
 	// Appeler l'API Nominatim
-	coords, err := gs.fetchFromNominatim(location)
+	coords, err := gs.fetchFromNominatim(ctx, location, countryCode)
 	if err != nil {
 		return nil, err
 	}
@@ -84,16 +107,22 @@ func (gs *GeocodingService) Geocode(location string) (*Coordinates, error) {
 	}
 
 	// Respect du rate limiting (1 requête/seconde pour Nominatim)
-	time.Sleep(1 * time.Second)
+	select {
+	case <-time.After(1 * time.Second):
+	case <-ctx.Done():
+	}
 
 	return coords, nil
 }
 
 // fetchFromNominatim appelle l'API Nominatim
-func (gs *GeocodingService) fetchFromNominatim(location string) (*Coordinates, error) {
+func (gs *GeocodingService) fetchFromNominatim(ctx context.Context, location, countryCode string) (*Coordinates, error) {
 	// Construire l'URL avec les paramètres
 	params := url.Values{}
 	params.Set("q", location)
+	if countryCode != "" {
+		params.Set("countrycodes", strings.ToLower(countryCode))
+	}
 	params.Set("format", "json")
 	params.Set("limit", "1")
 	params.Set("addressdetails", "1")
@@ -101,7 +130,7 @@ func (gs *GeocodingService) fetchFromNominatim(location string) (*Coordinates, e
 	requestURL := fmt.Sprintf("%s?%s", gs.apiURL, params.Encode())
 
 	// Créer la requête
-	req, err := http.NewRequest("GET", requestURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("erreur création requête: %w", err)
 	}
@@ -166,32 +195,29 @@ func parseCoordinates(latStr, lonStr string) (float64, float64, error) {
 
 // GeocodeLocation convertit une location du format "city-country" en coordonnées
 func (gs *GeocodingService) GeocodeLocation(location string) (*Coordinates, error) {
-	// Parser la location (format: "los_angeles-usa")
-	city, country := ParseLocation(location)
-	
-	if city == "" && country == "" {
-		return nil, fmt.Errorf("location invalide: %s", location)
-	}
-
-	// Construire la requête de géocodage
-	query := ""
-	if city != "" && country != "" {
-		query = fmt.Sprintf("%s, %s", city, country)
-	} else if city != "" {
-		query = city
-	} else {
-		query = country
-	}
+	return gs.GeocodeLocationContext(context.Background(), location)
+}
 
-	return gs.Geocode(query)
+// GeocodeLocationContext est la variante annulable de GeocodeLocation
+func (gs *GeocodingService) GeocodeLocationContext(ctx context.Context, location string) (*Coordinates, error) {
+	// Lieu canonique (format: "los_angeles-usa" -> "Los Angeles, États-Unis")
+	return gs.GeocodePlaceContext(ctx, ParsePlace(location))
 }
 
 // BatchGeocode géocode plusieurs locations en parallèle (avec rate limiting)
 func (gs *GeocodingService) BatchGeocode(locations []string) map[string]*Coordinates {
+	return gs.BatchGeocodeContext(context.Background(), locations)
+}
+
+// BatchGeocodeContext s'arrête dès que ctx est annulé et retourne les résultats obtenus
+func (gs *GeocodingService) BatchGeocodeContext(ctx context.Context, locations []string) map[string]*Coordinates {
 	results := make(map[string]*Coordinates)
 	
 	for _, location := range locations {
-		coords, err := gs.GeocodeLocation(location)
+		if ctx.Err() != nil {
+			break
+		}
+		coords, err := gs.GeocodeLocationContext(ctx, location)
 		if err != nil {
 			fmt.Printf("⚠️  Erreur géocodage '%s': %v\n", location, err)
 			continue
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"groupie-tracker/models"
 	"sync"
@@ -10,6 +11,7 @@ import (
 // GeocodingPreloader gère le préchargement des géolocalisations
 type GeocodingPreloader struct {
 	geocoder *GeocodingService
+	source   DataSource
 	cache    map[string]*Coordinates
 	mu       sync.RWMutex
 	loaded   int
@@ -20,24 +22,38 @@ type GeocodingPreloader struct {
 func NewGeocodingPreloader(geocoder *GeocodingService) *GeocodingPreloader {
 	return &GeocodingPreloader{
 		geocoder: geocoder,
+		source:   DefaultDataSource(),
 		cache:    make(map[string]*Coordinates),
 	}
 }
 
+// SetDataSource change la source utilisée pour lister les locations
+func (gp *GeocodingPreloader) SetDataSource(source DataSource) {
+	gp.source = source
+}
+
 // PreloadAll précharge toutes les géolocalisations pour tous les artistes
 func (gp *GeocodingPreloader) PreloadAll(artists []models.Artist, onProgress func(int, int)) error {
+	return gp.PreloadAllContext(context.Background(), artists, onProgress)
+}
+
+// PreloadAllContext précharge les géolocalisations jusqu'à annulation de ctx
+func (gp *GeocodingPreloader) PreloadAllContext(ctx context.Context, artists []models.Artist, onProgress func(int, int)) error {
 	fmt.Println("🌍 Démarrage du préchargement des géolocalisations...")
 	startTime := time.Now()
 
-	// Collecter toutes les locations uniques
-	uniqueLocations := make(map[string]bool)
+	// Collecter toutes les locations uniques (chargement groupé via les index)
+	aggregates, err := LoadAllAggregatesContext(ctx, gp.source, artists)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
 
-	for _, artist := range artists {
-		aggregate, err := AggregateArtist(artist)
-		if err != nil {
-			continue
-		}
+	uniqueLocations := make(map[string]bool)
 
+	for _, aggregate := range aggregates {
 		for _, location := range aggregate.Locations.Locations {
 			uniqueLocations[location] = true
 		}
@@ -61,7 +77,7 @@ func (gp *GeocodingPreloader) PreloadAll(artists []models.Artist, onProgress fun
 	var wg sync.WaitGroup
 	for w := 0; w < numWorkers; w++ {
 		wg.Add(1)
-		go gp.worker(w, jobs, results, &wg)
+		go gp.worker(ctx, w, jobs, results, &wg)
 	}
 
 	// Envoyer les jobs
@@ -95,6 +111,11 @@ func (gp *GeocodingPreloader) PreloadAll(artists []models.Artist, onProgress fun
 		}
 	}
 
+	if ctx.Err() != nil {
+		fmt.Printf("⏹️ Préchargement interrompu: %d/%d locations\n", gp.loaded, gp.total)
+		return ctx.Err()
+	}
+
 	duration := time.Since(startTime)
 	fmt.Printf("✅ Préchargement terminé: %d/%d locations en %v\n", gp.loaded, gp.total, duration.Round(time.Second))
 
@@ -102,10 +123,16 @@ func (gp *GeocodingPreloader) PreloadAll(artists []models.Artist, onProgress fun
 }
 
 // worker géocode les locations
-func (gp *GeocodingPreloader) worker(id int, jobs <-chan string, results chan<- bool, wg *sync.WaitGroup) {
+func (gp *GeocodingPreloader) worker(ctx context.Context, id int, jobs <-chan string, results chan<- bool, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	for location := range jobs {
+		// Contexte annulé : on vide la file sans travailler
+		if ctx.Err() != nil {
+			results <- false
+			continue
+		}
+
 		// Vérifier le cache d'abord
 		if coords, exists := gp.geocoder.GetFromCache(location); exists {
 			gp.mu.Lock()
@@ -116,7 +143,7 @@ func (gp *GeocodingPreloader) worker(id int, jobs <-chan string, results chan<-
 		}
 
 		// Géocoder
-		coords, err := gp.geocoder.GeocodeLocation(location)
+		coords, err := gp.geocoder.GeocodeLocationContext(ctx, location)
 		if err != nil {
 			fmt.Printf("⚠️  Worker %d: Erreur '%s': %v\n", id, location, err)
 			results <- false
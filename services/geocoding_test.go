@@ -1,6 +1,10 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -213,6 +217,34 @@ func TestDistanceSamePoint(t *testing.T) {
 	}
 }
 
+func TestGeocodeContext_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	gs := NewGeocodingService()
+	gs.apiURL = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := gs.GeocodeContext(ctx, "Paris, France")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Attendu context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("La requête Nominatim aurait dû être interrompue")
+	}
+}
+
 // Test d'intégration (nécessite une connexion internet)
 // Décommenter pour tester avec l'API réelle
 /*
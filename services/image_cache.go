@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"groupie-tracker/models"
 	"image"
@@ -30,6 +31,11 @@ func NewImageCache() *ImageCache {
 
 // PreloadImages précharge toutes les images des artistes
 func (ic *ImageCache) PreloadImages(artists []models.Artist, progressCallback func(current, total int)) error {
+	return ic.PreloadImagesContext(context.Background(), artists, progressCallback)
+}
+
+// PreloadImagesContext précharge les images jusqu'à annulation de ctx
+func (ic *ImageCache) PreloadImagesContext(ctx context.Context, artists []models.Artist, progressCallback func(current, total int)) error {
 	total := len(artists)
 	
 	// Limiter le nombre de goroutines simultanées
@@ -45,8 +51,15 @@ func (ic *ImageCache) PreloadImages(artists []models.Artist, progressCallback fu
 			semaphore <- struct{}{} // Acquérir
 			defer func() { <-semaphore }() // Libérer
 			
+			if ctx.Err() != nil {
+				return
+			}
+			
 			// Charger l'image
-			img, err := ic.loadImage(a.Image)
+			img, err := ic.loadImage(ctx, a.Image)
+			if ctx.Err() != nil {
+				return // annulé : ni image ni erreur enregistrée
+			}
 			
 			ic.mu.Lock()
 			if err != nil {
@@ -64,7 +77,7 @@ func (ic *ImageCache) PreloadImages(artists []models.Artist, progressCallback fu
 	}
 	
 	wg.Wait()
-	return nil
+	return ctx.Err()
 }
 
 // GetImage retourne une image depuis le cache
@@ -95,7 +108,7 @@ func (ic *ImageCache) GetProgress() (loaded, total int) {
 }
 
 // loadImage charge une image depuis une URL
-func (ic *ImageCache) loadImage(url string) (image.Image, error) {
+func (ic *ImageCache) loadImage(ctx context.Context, url string) (image.Image, error) {
 	if url == "" {
 		return nil, fmt.Errorf("URL vide")
 	}
@@ -105,7 +118,12 @@ func (ic *ImageCache) loadImage(url string) (image.Image, error) {
 		Timeout: 10 * time.Second,
 	}
 	
-	resp, err := client.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erreur requête: %w", err)
+	}
+	
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("erreur HTTP: %w", err)
 	}
@@ -2,6 +2,10 @@ package services
 
 import (
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"groupie-tracker/models"
 )
 
 // InitialsSearchEngine gère la recherche par initiales
@@ -17,54 +21,53 @@ func NewInitialsSearchEngine(baseEngine *SearchEngine) *InitialsSearchEngine {
 }
 
 // SearchByInitials recherche par initiales
-// Exemples: "fm" → "Freddie Mercury", "qotsa" → "Queen of the Stone Age"
+// Exemples: "fm" → "Freddie Mercury", "qotsa" → "Queen of the Stone Age",
+// "s&g" → "Simon & Garfunkel". Un alias du dictionnaire ("gnr") désigne
+// directement son nom complet.
 func (ise *InitialsSearchEngine) SearchByInitials(initials string) []SearchResult {
-	initials = strings.ToLower(strings.TrimSpace(initials))
+	target, hasAlias := ise.baseEngine.AliasTarget(initials)
+	initials = strings.ReplaceAll(looseText(initials), " ", "")
 
 	if initials == "" {
 		return []SearchResult{}
 	}
+	if hasAlias {
+		target = looseText(target)
+	}
 
 	results := []SearchResult{}
 	seen := make(map[string]bool)
 
-	for _, artist := range ise.baseEngine.artists {
-		// Recherche dans le nom de l'artiste
-		if ise.matchesInitials(artist.Name, initials) {
-			key := artist.Name + "-artist"
-			if !seen[key] {
-				score := ise.calculateInitialsScore(artist.Name, initials, SearchTypeArtist)
-				results = append(results, SearchResult{
-					ArtistID:    artist.ID,
-					ArtistName:  artist.Name,
-					MatchedText: artist.Name,
-					Type:        SearchTypeArtist,
-					Score:       score,
-					MatchStart:  0,
-					MatchEnd:    len(artist.Name),
-				})
-				seen[key] = true
+	add := func(artist models.Artist, text, key string, searchType SearchType) {
+		query := initials
+		if !ise.matchesInitials(text, initials) {
+			if !hasAlias || looseText(text) != target {
+				return
 			}
+			query = ise.extractInitials(text) // alias : initiales complètes
 		}
+		if seen[key] {
+			return
+		}
+		results = append(results, SearchResult{
+			ArtistID:    artist.ID,
+			ArtistName:  artist.Name,
+			MatchedText: text,
+			Type:        searchType,
+			Score:       ise.calculateInitialsScore(text, query, searchType),
+			MatchStart:  0,
+			MatchEnd:    utf8.RuneCountInString(text),
+		})
+		seen[key] = true
+	}
+
+	for _, artist := range ise.baseEngine.store.CachedArtists() {
+		// Recherche dans le nom de l'artiste
+		add(artist, artist.Name, artist.Name+"-artist", SearchTypeArtist)
 
 		// Recherche dans les membres
 		for _, member := range artist.Members {
-			if ise.matchesInitials(member, initials) {
-				key := artist.Name + "-" + member
-				if !seen[key] {
-					score := ise.calculateInitialsScore(member, initials, SearchTypeMember)
-					results = append(results, SearchResult{
-						ArtistID:    artist.ID,
-						ArtistName:  artist.Name,
-						MatchedText: member,
-						Type:        SearchTypeMember,
-						Score:       score,
-						MatchStart:  0,
-						MatchEnd:    len(member),
-					})
-					seen[key] = true
-				}
-			}
+			add(artist, member, artist.Name+"-"+member, SearchTypeMember)
 		}
 	}
 
@@ -74,42 +77,58 @@ func (ise *InitialsSearchEngine) SearchByInitials(initials string) []SearchResul
 
 // matchesInitials vérifie si un texte correspond aux initiales
 func (ise *InitialsSearchEngine) matchesInitials(text, initials string) bool {
-	text = strings.ToLower(text)
-	initials = strings.ToLower(initials)
-
-	// Extraire les initiales du texte
-	words := strings.Fields(text)
-	textInitials := ""
+	_, ok := ise.matchInitials(text, strings.ReplaceAll(looseText(initials), " ", ""))
+	return ok
+}
 
-	for _, word := range words {
-		if len(word) > 0 {
-			textInitials += string(word[0])
+// matchInitials retourne les initiales du texte commençant par initials
+// (déjà normalisées) : d'abord mot à mot sans ponctuation ("Guns N' Roses" ->
+// "gnr"), puis avec "&" lu "and" ("Simon & Garfunkel" -> "sag")
+func (ise *InitialsSearchEngine) matchInitials(text, initials string) (string, bool) {
+	for _, textInitials := range []string{ise.extractInitials(text), ise.extractLooseInitials(text)} {
+		if strings.HasPrefix(textInitials, initials) {
+			return textInitials, true
 		}
 	}
-
-	// Vérifier si les initiales correspondent
-	return textInitials == initials || strings.HasPrefix(textInitials, initials)
+	return "", false
 }
 
-// extractInitials extrait les initiales d'un texte
+// extractInitials extrait les initiales d'un texte, repliées ("Émile Zola" ->
+// "ez"). La ponctuation est ignorée : "AC/DC" -> "a", "Simon & Garfunkel" -> "sg".
 func (ise *InitialsSearchEngine) extractInitials(text string) string {
-	words := strings.Fields(strings.ToLower(text))
 	initials := ""
 
-	for _, word := range words {
-		if len(word) > 0 {
-			initials += string(word[0])
+	for _, word := range strings.Fields(text) {
+		for _, r := range word {
+			if folded := foldRune(r); folded != "" && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				initials += folded
+				break
+			}
 		}
 	}
 
 	return initials
 }
 
+// extractLooseInitials extrait les initiales de la forme souple, où "&", "et"
+// et "n" sont lus "and"
+func (ise *InitialsSearchEngine) extractLooseInitials(text string) string {
+	initials := ""
+	for _, word := range strings.Fields(looseText(text)) {
+		first, _ := utf8.DecodeRuneInString(word)
+		initials += string(first)
+	}
+	return initials
+}
+
 // calculateInitialsScore calcule le score pour une recherche par initiales
 func (ise *InitialsSearchEngine) calculateInitialsScore(text, initials string, searchType SearchType) int {
 	score := 400
 
-	textInitials := ise.extractInitials(text)
+	textInitials, ok := ise.matchInitials(text, initials)
+	if !ok {
+		textInitials = ise.extractInitials(text)
+	}
 
 	// Match exact des initiales = bonus
 	if textInitials == initials {
@@ -144,7 +163,7 @@ func (ise *InitialsSearchEngine) calculateInitialsScore(text, initials string, s
 
 // SmartInitialsSearch combine recherche normale et initiales
 func (ise *InitialsSearchEngine) SmartInitialsSearch(query string) []SearchResult {
-	query = strings.ToLower(strings.TrimSpace(query))
+	query = foldText(strings.TrimSpace(query))
 
 	// Si la query a des espaces, utiliser recherche normale
 	if strings.Contains(query, " ") {
@@ -152,7 +171,7 @@ func (ise *InitialsSearchEngine) SmartInitialsSearch(query string) []SearchResul
 	}
 
 	// Si la query est courte (2-5 caractères), essayer initiales
-	if len(query) >= 2 && len(query) <= 5 {
+	if length := utf8.RuneCountInString(query); length >= 2 && length <= 5 {
 		initialsResults := ise.SearchByInitials(query)
 
 		// Si on trouve des résultats par initiales, les combiner avec recherche normale
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// =====================
+// NORMALISATION DU TEXTE
+// =====================
+//
+// La recherche compare des formes "repliées" : minuscules, sans diacritiques
+// ("Motörhead" -> "motorhead", "Beyoncé" -> "beyonce", "Straße" ->
+// "strasse"). Les positions de match sont ensuite ramenées en runes du texte
+// d'origine pour que le surlignage ne coupe jamais un caractère.
+
+// foldTable associe les lettres latines accentuées (en minuscules) à leur
+// forme de base
+var foldTable = func() map[rune]string {
+	table := make(map[rune]string)
+	for base, letters := range map[string]string{
+		"a":  "àáâãäåāăąǎ",
+		"c":  "çćĉċč",
+		"d":  "ďđð",
+		"e":  "èéêëēĕėęěẽ",
+		"g":  "ĝğġģ",
+		"h":  "ĥħ",
+		"i":  "ìíîïĩīĭįı",
+		"j":  "ĵ",
+		"k":  "ķ",
+		"l":  "ĺļľŀł",
+		"n":  "ñńņňŉ",
+		"o":  "òóôõöøōŏőǒ",
+		"r":  "ŕŗř",
+		"s":  "śŝşšș",
+		"t":  "ţťŧț",
+		"u":  "ùúûüũūŭůűųǔ",
+		"w":  "ŵ",
+		"y":  "ýÿŷ",
+		"z":  "źżž",
+		"ss": "ß",
+		"ae": "æ",
+		"oe": "œ",
+		"th": "þ",
+	} {
+		for _, letter := range letters {
+			table[letter] = base
+		}
+	}
+	return table
+}()
+
+// foldRune retourne la forme repliée d'une rune ("" pour un accent combinant)
+func foldRune(r rune) string {
+	if unicode.Is(unicode.Mn, r) {
+		return "" // diacritique combinant (texte décomposé : "e" + U+0301)
+	}
+	r = unicode.ToLower(r)
+	if folded, ok := foldTable[r]; ok {
+		return folded
+	}
+	return string(r)
+}
+
+// foldText replie la casse et les diacritiques d'un texte
+func foldText(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		b.WriteString(foldRune(r))
+	}
+	return b.String()
+}
+
+// foldedText est un texte replié avec, pour chaque octet, l'indice de la
+// rune d'origine qui l'a produit
+type foldedText struct {
+	text  string
+	runes []int // len(text)+1 entrées ; la dernière vaut le nombre de runes
+}
+
+// foldWithOffsets replie s en conservant la correspondance des positions
+func foldWithOffsets(s string) foldedText {
+	var b strings.Builder
+	b.Grow(len(s))
+	runes := make([]int, 0, len(s)+1)
+
+	i := 0
+	for _, r := range s {
+		folded := foldRune(r)
+		b.WriteString(folded)
+		for range len(folded) {
+			runes = append(runes, i)
+		}
+		i++
+	}
+	return foldedText{text: b.String(), runes: append(runes, i)}
+}
+
+// runeSpan convertit un intervalle d'octets du texte replié en intervalle de
+// runes du texte d'origine. Une lettre développée ("ß" -> "ss") partiellement
+// couverte est incluse entière ; les accents combinants suivant le match aussi.
+func (f foldedText) runeSpan(start, end int) (int, int) {
+	runeStart := f.runes[start]
+	if end <= start {
+		return runeStart, runeStart
+	}
+	runeEnd := f.runes[end]
+	if last := f.runes[end-1]; runeEnd <= last {
+		runeEnd = last + 1
+	}
+	return runeStart, runeEnd
+}
+
+// index cherche query (déjà repliée) et retourne l'intervalle de runes du
+// texte d'origine, ou (-1, -1)
+func (f foldedText) index(query string) (int, int) {
+	pos := strings.Index(f.text, query)
+	if pos == -1 {
+		return -1, -1
+	}
+	return f.runeSpan(pos, pos+len(query))
+}
+
+// wordsSpan cherche les mots entiers words (déjà repliés) et retourne
+// l'intervalle de runes du texte d'origine qui les couvre tous, ou (-1, -1)
+// si l'un d'eux est absent
+func (f foldedText) wordsSpan(words []string) (int, int) {
+	start, end := -1, -1
+	padded := " " + f.text + " "
+	for _, word := range words {
+		pos := strings.Index(padded, " "+word+" ")
+		if pos == -1 {
+			return -1, -1
+		}
+		// pos est la position du mot dans f.text (décalage de l'espace
+		// ajoutée) ; le mot s'arrête à sa dernière lettre, sans la
+		// ponctuation qui le suit ("Angeles," -> "Angeles")
+		wordStart, wordEnd := f.runes[pos], f.runes[pos+len(word)-1]+1
+		if start == -1 || wordStart < start {
+			start = wordStart
+		}
+		end = max(end, wordEnd)
+	}
+	return start, end
+}
+
+// foldIndex cherche query (déjà repliée) dans text ; les positions sont en
+// runes de text
+func foldIndex(text, query string) (int, int) {
+	return foldWithOffsets(text).index(query)
+}
+
+// runeSlice retourne les runes [start, end) de s
+func runeSlice(s string, start, end int) string {
+	runes := []rune(s)
+	if start < 0 || end > len(runes) || start > end {
+		return ""
+	}
+	return string(runes[start:end])
+}
+
+// runeIndex convertit une position en octets de s en position en runes
+func runeIndex(s string, bytePos int) int {
+	return utf8.RuneCountInString(s[:bytePos])
+}
+
+// conjunctions sont les mots lus "and" par la forme souple : "&" (seul),
+// "et", et le "N'" de "Guns N' Roses"
+var conjunctions = map[string]bool{"and": true, "et": true, "n": true}
+
+// looseWithOffsets est foldWithOffsets sans ponctuation : "AC/DC" -> "acdc",
+// "Guns N' Roses" -> "guns and roses", "Simon & Garfunkel" -> "simon and
+// garfunkel". Les mots sont séparés par une seule espace.
+func looseWithOffsets(s string) foldedText {
+	var b strings.Builder
+	runes := []int{}
+
+	i, wordStart := 0, 0
+	word := []rune{}
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+
+		var w strings.Builder
+		offsets := []int{}
+		ampersand := -1
+		for k, r := range word {
+			if r == '&' {
+				ampersand = wordStart + k
+			}
+			for _, f := range foldRune(r) {
+				if unicode.IsLetter(f) || unicode.IsDigit(f) {
+					w.WriteRune(f)
+					for range utf8.RuneLen(f) {
+						offsets = append(offsets, wordStart+k)
+					}
+				}
+			}
+		}
+
+		text := w.String()
+		switch {
+		case text == "" && ampersand != -1:
+			text, offsets = "and", []int{ampersand, ampersand, ampersand}
+		case text != "and" && conjunctions[text]:
+			// Surligne le mot d'origine entier ("N'" compte pour "and")
+			last := offsets[len(offsets)-1]
+			text, offsets = "and", []int{offsets[0], last, last}
+		}
+
+		if text != "" {
+			if b.Len() > 0 {
+				b.WriteByte(' ')
+				runes = append(runes, wordStart-1)
+			}
+			b.WriteString(text)
+			runes = append(runes, offsets...)
+		}
+		word = word[:0]
+	}
+
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			flush()
+			wordStart = i + 1
+		} else {
+			word = append(word, r)
+		}
+		i++
+	}
+	flush()
+
+	return foldedText{text: b.String(), runes: append(runes, i)}
+}
+
+// looseText retourne la forme souple (sans ponctuation) d'un texte
+func looseText(s string) string {
+	return looseWithOffsets(s).text
+}
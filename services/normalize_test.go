@@ -0,0 +1,109 @@
+package services
+
+import "testing"
+
+func TestFoldText(t *testing.T) {
+	tests := map[string]string{
+		"Motörhead":     "motorhead",
+		"Beyoncé":       "beyonce",
+		"ÉTATS-UNIS":    "etats-unis",
+		"Straße":        "strasse",
+		"Sigur Rós":     "sigur ros",
+		"Beyonce\u0301": "beyonce", // forme décomposée
+		"Œuvre Łódź":    "oeuvre lodz",
+		"AC/DC 1973":    "ac/dc 1973",
+		"東京":            "東京",
+	}
+
+	for input, expected := range tests {
+		if got := foldText(input); got != expected {
+			t.Errorf("foldText(%q) = %q, attendu %q", input, got, expected)
+		}
+	}
+}
+
+func TestFoldIndex_RuneOffsets(t *testing.T) {
+	tests := []struct {
+		text, query string
+		start, end  int
+	}{
+		{"Motörhead", "torh", 2, 6},
+		{"Beyoncé", "ce", 5, 7},
+		{"Straße", "ss", 4, 5},
+		{"Straße", "s", 0, 1},
+		{"Strasse Straße", "straße", -1, -1},       // la requête doit être repliée
+		{"Beyonce\u0301 Knowles", "beyonce", 0, 8}, // accent combinant inclus
+		{"Queen", "zz", -1, -1},
+	}
+
+	for _, tt := range tests {
+		start, end := foldIndex(tt.text, tt.query)
+		if start != tt.start || end != tt.end {
+			t.Errorf("foldIndex(%q, %q) = (%d, %d), attendu (%d, %d)", tt.text, tt.query, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestLooseText(t *testing.T) {
+	tests := map[string]string{
+		"AC/DC":              "acdc",
+		"Guns N' Roses":      "guns and roses",
+		"Simon & Garfunkel":  "simon and garfunkel",
+		"Simon et Garfunkel": "simon and garfunkel",
+		"Earth, Wind & Fire": "earth wind and fire",
+		"  a-ha  ":           "aha",
+		"Mötley Crüe":        "motley crue",
+		"R&B":                "rb",
+		"...":                "",
+		"Sum 41 (live)":      "sum 41 live",
+		"Beyoncé & Jay-Z":   "beyonce and jayz",
+	}
+
+	for input, expected := range tests {
+		if got := looseText(input); got != expected {
+			t.Errorf("looseText(%q) = %q, attendu %q", input, got, expected)
+		}
+	}
+}
+
+func TestLooseWithOffsets_RuneOffsets(t *testing.T) {
+	tests := []struct {
+		text, query string
+		match       string // texte d'origine surligné
+	}{
+		{"AC/DC", "acdc", "AC/DC"},
+		{"AC/DC", "cd", "C/D"},
+		{"Guns N' Roses", "guns and roses", "Guns N' Roses"},
+		{"Guns N' Roses", "and", "N'"},
+		{"Simon & Garfunkel", "and garfunkel", "& Garfunkel"},
+		{"Mötley Crüe", "motley crue", "Mötley Crüe"},
+	}
+
+	for _, tt := range tests {
+		start, end := looseWithOffsets(tt.text).index(tt.query)
+		if got := runeSlice(tt.text, start, end); got != tt.match {
+			t.Errorf("looseWithOffsets(%q).index(%q) surligne %q, attendu %q", tt.text, tt.query, got, tt.match)
+		}
+	}
+}
+
+func TestWordsSpan(t *testing.T) {
+	tests := []struct {
+		text  string
+		words []string
+		match string
+	}{
+		{"Los Angeles, États-Unis", []string{"angeles"}, "Angeles"},
+		{"Los Angeles, États-Unis", []string{"angeles", "los"}, "Los Angeles"},
+		{"Freddie Mercury", []string{"mercury"}, "Mercury"},
+		{"Guns N' Roses", []string{"and", "roses"}, "N' Roses"},
+		{"Freddie Mercury", []string{"merc"}, ""}, // mots entiers uniquement
+	}
+
+	for _, tt := range tests {
+		start, end := looseWithOffsets(tt.text).wordsSpan(tt.words)
+		if got := runeSlice(tt.text, start, end); got != tt.match {
+			t.Errorf("wordsSpan(%q, %v) surligne %q, attendu %q", tt.text, tt.words, got, tt.match)
+		}
+	}
+}
@@ -0,0 +1,582 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+)
+
+// =====================
+// CODAGES PHONÉTIQUES
+// =====================
+
+// DoubleMetaphone calcule les codes phonétiques principal et alternatif d'un
+// mot (algorithme Double Metaphone de Lawrence Philips, codes de 4 lettres).
+// Le texte est replié (accents, casse) avant codage.
+func DoubleMetaphone(word string) (primary, alternate string) {
+	folded := strings.ToUpper(foldText(word))
+	letters := make([]byte, 0, len(folded))
+	for i := 0; i < len(folded); i++ {
+		if c := folded[i]; c >= 'A' && c <= 'Z' {
+			letters = append(letters, c)
+		}
+	}
+	if len(letters) == 0 {
+		return "", ""
+	}
+
+	m := &metaphone{word: string(letters), last: len(letters) - 1}
+	m.encode()
+	return truncateCode(m.primary.String()), truncateCode(m.alternate.String())
+}
+
+const metaphoneLength = 4
+
+func truncateCode(code string) string {
+	if len(code) > metaphoneLength {
+		return code[:metaphoneLength]
+	}
+	return code
+}
+
+type metaphone struct {
+	word      string
+	last      int
+	primary   strings.Builder
+	alternate strings.Builder
+}
+
+func (m *metaphone) at(i int) byte {
+	if i < 0 || i > m.last {
+		return 0
+	}
+	return m.word[i]
+}
+
+// stringAt indique si l'une des chaînes apparaît à la position start
+func (m *metaphone) stringAt(start int, options ...string) bool {
+	if start < 0 {
+		return false
+	}
+	for _, option := range options {
+		if strings.HasPrefix(m.word[min(start, len(m.word)):], option) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *metaphone) isVowel(i int) bool {
+	switch m.at(i) {
+	case 'A', 'E', 'I', 'O', 'U', 'Y':
+		return true
+	}
+	return false
+}
+
+// slavoGermanic détecte les mots d'origine slave ou germanique
+func (m *metaphone) slavoGermanic() bool {
+	return strings.ContainsAny(m.word, "WK") || strings.Contains(m.word, "CZ") || strings.Contains(m.word, "WITZ")
+}
+
+func (m *metaphone) add(code string) {
+	m.addAlt(code, code)
+}
+
+func (m *metaphone) addAlt(primary, alternate string) {
+	m.primary.WriteString(primary)
+	m.alternate.WriteString(alternate)
+}
+
+func (m *metaphone) done() bool {
+	return m.primary.Len() >= metaphoneLength && m.alternate.Len() >= metaphoneLength
+}
+
+func (m *metaphone) encode() {
+	current := 0
+
+	// Lettres initiales muettes
+	if m.stringAt(0, "GN", "KN", "PN", "WR", "PS") {
+		current++
+	}
+	// X initial prononcé S ("Xavier")
+	if m.at(0) == 'X' {
+		m.add("S")
+		current++
+	}
+
+	for current <= m.last && !m.done() {
+		switch m.at(current) {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if current == 0 {
+				m.add("A")
+			}
+			current++
+		case 'B':
+			m.add("P")
+			current += m.skipDouble(current, 'B')
+		case 'C':
+			current = m.encodeC(current)
+		case 'D':
+			switch {
+			case m.stringAt(current, "DG") && m.stringAt(current+2, "I", "E", "Y"):
+				m.add("J")
+				current += 3
+			case m.stringAt(current, "DG"):
+				m.add("TK")
+				current += 2
+			case m.stringAt(current, "DT", "DD"):
+				m.add("T")
+				current += 2
+			default:
+				m.add("T")
+				current++
+			}
+		case 'F':
+			m.add("F")
+			current += m.skipDouble(current, 'F')
+		case 'G':
+			current = m.encodeG(current)
+		case 'H':
+			if (current == 0 || m.isVowel(current-1)) && m.isVowel(current+1) {
+				m.add("H")
+				current += 2
+			} else {
+				current++
+			}
+		case 'J':
+			current = m.encodeJ(current)
+		case 'K':
+			m.add("K")
+			current += m.skipDouble(current, 'K')
+		case 'L':
+			if m.at(current+1) == 'L' {
+				// "cabrillo", "gallegos" : L espagnol
+				if (current == m.last-2 && m.stringAt(current-1, "ILLO", "ILLA", "ALLE")) ||
+					((m.stringAt(m.last-1, "AS", "OS") || m.stringAt(m.last, "A", "O")) && m.stringAt(current-1, "ALLE")) {
+					m.addAlt("L", "")
+					current += 2
+					continue
+				}
+				current += 2
+			} else {
+				current++
+			}
+			m.add("L")
+		case 'M':
+			if (m.stringAt(current-1, "UMB") && (current+1 == m.last || m.stringAt(current+2, "ER"))) || m.at(current+1) == 'M' {
+				current += 2
+			} else {
+				current++
+			}
+			m.add("M")
+		case 'N':
+			m.add("N")
+			current += m.skipDouble(current, 'N')
+		case 'P':
+			if m.at(current+1) == 'H' {
+				m.add("F")
+				current += 2
+				continue
+			}
+			if m.stringAt(current+1, "P", "B") {
+				current += 2
+			} else {
+				current++
+			}
+			m.add("P")
+		case 'Q':
+			m.add("K")
+			current += m.skipDouble(current, 'Q')
+		case 'R':
+			// "rogier" : R final muet en français
+			if current == m.last && !m.slavoGermanic() && m.stringAt(current-2, "IE") && !m.stringAt(current-4, "ME", "MA") {
+				m.addAlt("", "R")
+			} else {
+				m.add("R")
+			}
+			current += m.skipDouble(current, 'R')
+		case 'S':
+			current = m.encodeS(current)
+		case 'T':
+			current = m.encodeT(current)
+		case 'V':
+			m.add("F")
+			current += m.skipDouble(current, 'V')
+		case 'W':
+			current = m.encodeW(current)
+		case 'X':
+			// "breaux" : X final muet en français
+			if !(current == m.last && (m.stringAt(current-3, "IAU", "EAU") || m.stringAt(current-2, "AU", "OU"))) {
+				m.add("KS")
+			}
+			if m.stringAt(current+1, "C", "X") {
+				current += 2
+			} else {
+				current++
+			}
+		case 'Z':
+			if m.at(current+1) == 'H' {
+				m.add("J")
+				current += 2
+				continue
+			}
+			if m.stringAt(current+1, "ZO", "ZI", "ZA") || (m.slavoGermanic() && current > 0 && m.at(current-1) != 'T') {
+				m.addAlt("S", "TS")
+			} else {
+				m.add("S")
+			}
+			current += m.skipDouble(current, 'Z')
+		default:
+			current++
+		}
+	}
+}
+
+func (m *metaphone) skipDouble(current int, letter byte) int {
+	if m.at(current+1) == letter {
+		return 2
+	}
+	return 1
+}
+
+func (m *metaphone) encodeC(current int) int {
+	switch {
+	// Germanique : "bacher", "macher"
+	case current > 1 && !m.isVowel(current-2) && m.stringAt(current-1, "ACH") &&
+		m.at(current+2) != 'I' && (m.at(current+2) != 'E' || m.stringAt(current-2, "BACHER", "MACHER")):
+		m.add("K")
+		return current + 2
+	case current == 0 && m.stringAt(current, "CAESAR"):
+		m.add("S")
+		return current + 2
+	case m.stringAt(current, "CHIA"): // "chianti"
+		m.add("K")
+		return current + 2
+	case m.stringAt(current, "CH"):
+		switch {
+		case current > 0 && m.stringAt(current, "CHAE"): // "michael"
+			m.addAlt("K", "X")
+		case current == 0 && (m.stringAt(current+1, "HARAC", "HARIS") || m.stringAt(current+1, "HOR", "HYM", "HIA", "HEM")) && !m.stringAt(0, "CHORE"):
+			m.add("K") // racines grecques : "chorus", "chemistry"
+		case m.stringAt(0, "VAN", "VON", "SCH") || m.stringAt(current-2, "ORCHES", "ARCHIT", "ORCHID") || m.stringAt(current+2, "T", "S") ||
+			((current == 0 || m.stringAt(current-1, "A", "O", "U", "E")) && m.stringAt(current+2, "L", "R", "N", "M", "B", "H", "F", "V", "W")):
+			m.add("K")
+		case current > 0 && m.stringAt(0, "MC"):
+			m.add("K")
+		case current > 0:
+			m.addAlt("X", "K")
+		default:
+			m.add("X")
+		}
+		return current + 2
+	case m.stringAt(current, "CZ") && !m.stringAt(current-2, "WICZ"): // "czerny"
+		m.addAlt("S", "X")
+		return current + 2
+	case m.stringAt(current+1, "CIA"): // "focaccia"
+		m.add("X")
+		return current + 3
+	case m.stringAt(current, "CC") && !(current == 1 && m.at(0) == 'M'):
+		if m.stringAt(current+2, "I", "E", "H") && !m.stringAt(current+2, "HU") {
+			if (current == 1 && m.at(0) == 'A') || m.stringAt(current-1, "UCCEE", "UCCES") {
+				m.add("KS") // "accident", "succeed"
+			} else {
+				m.add("X") // "bellocchio"
+			}
+			return current + 3
+		}
+		m.add("K")
+		return current + 2
+	case m.stringAt(current, "CK", "CG", "CQ"):
+		m.add("K")
+		return current + 2
+	case m.stringAt(current, "CI", "CE", "CY"):
+		if m.stringAt(current, "CIO", "CIE", "CIA") {
+			m.addAlt("S", "X")
+		} else {
+			m.add("S")
+		}
+		return current + 2
+	}
+
+	m.add("K")
+	if m.stringAt(current+1, "C", "K", "Q") && !m.stringAt(current+1, "CE", "CI") {
+		return current + 2
+	}
+	return current + 1
+}
+
+func (m *metaphone) encodeG(current int) int {
+	slavo := m.slavoGermanic()
+
+	if m.at(current+1) == 'H' {
+		switch {
+		case current > 0 && !m.isVowel(current-1):
+			m.add("K")
+		case current == 0:
+			if m.at(current+2) == 'I' {
+				m.add("J") // "ghislane"
+			} else {
+				m.add("K")
+			}
+		// Règle de Parker : "hugh", "bough", "broughton"
+		case (current > 1 && m.stringAt(current-2, "B", "H", "D")) ||
+			(current > 2 && m.stringAt(current-3, "B", "H", "D")) ||
+			(current > 3 && m.stringAt(current-4, "B", "H")):
+		case current > 2 && m.at(current-1) == 'U' && m.stringAt(current-3, "C", "G", "L", "R", "T"):
+			m.add("F") // "laugh", "tough"
+		case current > 0 && m.at(current-1) != 'I':
+			m.add("K")
+		}
+		return current + 2
+	}
+
+	if m.at(current+1) == 'N' {
+		switch {
+		case current == 1 && m.isVowel(0) && !slavo:
+			m.addAlt("KN", "N")
+		case !m.stringAt(current+2, "EY") && m.at(current+1) != 'Y' && !slavo:
+			m.addAlt("N", "KN")
+		default:
+			m.add("KN")
+		}
+		return current + 2
+	}
+
+	switch {
+	case m.stringAt(current+1, "LI") && !slavo: // "tagliaro"
+		m.addAlt("KL", "L")
+		return current + 2
+	case current == 0 && (m.at(current+1) == 'Y' || m.stringAt(current+1, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		m.addAlt("K", "J")
+		return current + 2
+	case (m.stringAt(current+1, "ER") || m.at(current+1) == 'Y') && !m.stringAt(0, "DANGER", "RANGER", "MANGER") &&
+		!m.stringAt(current-1, "E", "I") && !m.stringAt(current-1, "RGY", "OGY"):
+		m.addAlt("K", "J")
+		return current + 2
+	case m.stringAt(current+1, "E", "I", "Y") || m.stringAt(current-1, "AGGI", "OGGI"):
+		switch {
+		case m.stringAt(0, "VAN", "VON", "SCH") || m.stringAt(current+1, "ET"):
+			m.add("K")
+		case m.stringAt(current+1, "IER") && current+3 == m.last:
+			m.add("J")
+		default:
+			m.addAlt("J", "K")
+		}
+		return current + 2
+	}
+
+	m.add("K")
+	return current + m.skipDouble(current, 'G')
+}
+
+func (m *metaphone) encodeJ(current int) int {
+	if m.stringAt(current, "JOSE") || m.stringAt(0, "SAN") {
+		if (current == 0 && current+3 == m.last) || m.stringAt(0, "SAN") {
+			m.add("H")
+		} else {
+			m.addAlt("J", "H")
+		}
+		return current + 1
+	}
+
+	switch {
+	case current == 0:
+		m.addAlt("J", "A")
+	case m.isVowel(current-1) && !m.slavoGermanic() && (m.at(current+1) == 'A' || m.at(current+1) == 'O'):
+		m.addAlt("J", "H")
+	case current == m.last:
+		m.addAlt("J", "")
+	case !m.stringAt(current+1, "L", "T", "K", "S", "N", "M", "B", "Z") && !m.stringAt(current-1, "S", "K", "L"):
+		m.add("J")
+	}
+	return current + m.skipDouble(current, 'J')
+}
+
+func (m *metaphone) encodeS(current int) int {
+	switch {
+	case m.stringAt(current-1, "ISL", "YSL"): // "island", "carlysle"
+		return current + 1
+	case current == 0 && m.stringAt(current, "SUGAR"):
+		m.addAlt("X", "S")
+		return current + 1
+	case m.stringAt(current, "SH"):
+		if m.stringAt(current+1, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			m.add("S")
+		} else {
+			m.add("X")
+		}
+		return current + 2
+	case m.stringAt(current, "SIO", "SIA"):
+		if m.slavoGermanic() {
+			m.add("S")
+		} else {
+			m.addAlt("S", "X")
+		}
+		return current + 3
+	case (current == 0 && m.stringAt(current+1, "M", "N", "L", "W")) || m.stringAt(current+1, "Z"):
+		m.addAlt("S", "X")
+		if m.stringAt(current+1, "Z") {
+			return current + 2
+		}
+		return current + 1
+	case m.stringAt(current, "SC"):
+		if m.at(current+2) == 'H' {
+			switch {
+			case m.stringAt(current+3, "ER", "EN"):
+				m.addAlt("X", "SK")
+			case m.stringAt(current+3, "OO", "UY", "ED", "EM"):
+				m.add("SK")
+			case current == 0 && !m.isVowel(3) && m.at(3) != 'W':
+				m.addAlt("X", "S")
+			default:
+				m.add("X")
+			}
+		} else if m.stringAt(current+2, "I", "E", "Y") {
+			m.add("S")
+		} else {
+			m.add("SK")
+		}
+		return current + 3
+	}
+
+	// "resnais", "artois" : S final muet en français
+	if current == m.last && m.stringAt(current-2, "AI", "OI") {
+		m.addAlt("", "S")
+	} else {
+		m.add("S")
+	}
+	if m.stringAt(current+1, "S", "Z") {
+		return current + 2
+	}
+	return current + 1
+}
+
+func (m *metaphone) encodeT(current int) int {
+	switch {
+	case m.stringAt(current, "TION", "TIA", "TCH"):
+		m.add("X")
+		return current + 3
+	case m.stringAt(current, "TH", "TTH"):
+		if m.stringAt(current+2, "OM", "AM") || m.stringAt(0, "VAN", "VON", "SCH") {
+			m.add("T")
+		} else {
+			m.addAlt("0", "T") // "0" : son TH
+		}
+		return current + 2
+	}
+
+	m.add("T")
+	if m.stringAt(current+1, "T", "D") {
+		return current + 2
+	}
+	return current + 1
+}
+
+func (m *metaphone) encodeW(current int) int {
+	if m.stringAt(current, "WR") {
+		m.add("R")
+		return current + 2
+	}
+
+	if current == 0 && (m.isVowel(current+1) || m.stringAt(current, "WH")) {
+		if m.isVowel(current + 1) {
+			m.addAlt("A", "F")
+		} else {
+			m.add("A")
+		}
+	}
+
+	switch {
+	case (current == m.last && m.isVowel(current-1)) || m.stringAt(current-1, "EWSKI", "EWSKY", "OWSKI", "OWSKY") || m.stringAt(0, "SCH"):
+		m.addAlt("", "F") // "tsjaikowski"
+	case m.stringAt(current, "WICZ", "WITZ"):
+		m.addAlt("TS", "FX")
+		return current + 4
+	}
+	return current + 1
+}
+
+// =====================
+// VARIANTE FRANÇAISE
+// =====================
+
+// frenchRule est une réécriture appliquée dans l'ordre par FrenchPhonetic.
+// Les majuscules marquent des sons déjà codés (X = "ch", G = g dur,
+// 1/2/3 = nasales "in", "an", "on").
+type frenchRule struct {
+	pattern *regexp.Regexp
+	replace string
+}
+
+func compileFrenchRules(rules [][2]string) []frenchRule {
+	compiled := make([]frenchRule, len(rules))
+	for i, rule := range rules {
+		compiled[i] = frenchRule{regexp.MustCompile(rule[0]), rule[1]}
+	}
+	return compiled
+}
+
+// Les lettres doublées sont fusionnées entre chaque phase : les nasales et
+// les finales muettes se lisent sur le mot sans doublons ("Johnny" -> "joni")
+var frenchPhases = [][]frenchRule{
+	// Consonnes
+	compileFrenchRules([][2]string{
+		{`[^a-z]`, ""},
+		{`ph`, "f"},
+		{`th`, "t"},
+		{`gu([eiy])`, "G$1"},
+		{`g([eiy])`, "j$1"},
+		{`gn`, "n"},
+		{`s?ch|sh`, "X"},
+		{`c([eiy])`, "s$1"},
+		{`qu|q|ck|c`, "k"},
+		{`(au|eu|ou)x$`, "$1"}, // "Bordeaux", "deux"
+		{`x`, "ks"},
+		{`z`, "s"},
+		{`w`, "v"},
+		{`h`, ""},
+		{`y`, "i"},
+	}),
+	// Voyelles et nasales
+	compileFrenchRules([][2]string{
+		{`eau|au`, "o"},
+		{`ou`, "u"},
+		{`oi`, "oa"},
+		{`([aeiou])ile?`, "${1}i"}, // "Marseille", "soleil"
+		{`(?:ain|ein|aim|eim|in|im|un|um)([^aeiou]|$)`, "1$1"},
+		{`(?:an|am|en|em)([^aeiou]|$)`, "2$1"},
+		{`(?:on|om)([^aeiou]|$)`, "3$1"},
+		{`ai|ei|oe|eu`, "e"},
+		{`(?:er|ez|et)$`, "e"},
+		{`([a-z1-3])[stdpx]$`, "$1"},
+	}),
+	// E muet final
+	compileFrenchRules([][2]string{
+		{`(.)e$`, "$1"},
+	}),
+}
+
+// FrenchPhonetic calcule une clé phonétique adaptée à la prononciation
+// française : "Johnny Hallyday" et "Joni Aliday" ont les mêmes clés mot à mot,
+// tout comme "Indochine" et "Indauchine". La clé est en majuscules.
+func FrenchPhonetic(word string) string {
+	code := foldText(word)
+	for _, phase := range frenchPhases {
+		for _, rule := range phase {
+			code = rule.pattern.ReplaceAllString(code, rule.replace)
+		}
+		code = collapseDoubles(code)
+	}
+	return strings.ToUpper(code)
+}
+
+// collapseDoubles fusionne les lettres doublées ("ll" -> "l")
+func collapseDoubles(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if i == 0 || s[i] != s[i-1] {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// PhoneticAlgorithm désigne un codage phonétique des mots
+type PhoneticAlgorithm string
+
+const (
+	PhoneticMetaphone PhoneticAlgorithm = "metaphone" // Double Metaphone (prononciation anglaise)
+	PhoneticFrench    PhoneticAlgorithm = "french"    // variante française
+)
+
+// minPhoneticQueryLength : en dessous, trop de mots partagent le même son
+const minPhoneticQueryLength = 3
+
+// phoneticKeys retourne les clés d'index d'un mot pour un algorithme, préfixées
+// pour ne pas mélanger les codages ("m:SPLN", "f:SEPEL1")
+func phoneticKeys(word string, algorithm PhoneticAlgorithm) []string {
+	switch algorithm {
+	case PhoneticMetaphone:
+		primary, alternate := DoubleMetaphone(word)
+		if primary == "" && alternate == "" {
+			return nil
+		}
+		keys := []string{"m:" + primary}
+		if alternate != primary {
+			keys = append(keys, "m:"+alternate)
+		}
+		return keys
+	case PhoneticFrench:
+		if code := FrenchPhonetic(word); code != "" {
+			return []string{"f:" + code}
+		}
+	}
+	return nil
+}
+
+// PhoneticSearchEngine gère la recherche par le son ("Ledd Seppelin" →
+// "Led Zeppelin", "Metalika" → "Metallica") sur les noms, membres et villes
+type PhoneticSearchEngine struct {
+	baseEngine *SearchEngine
+}
+
+// NewPhoneticSearchEngine crée un moteur de recherche phonétique
+func NewPhoneticSearchEngine(baseEngine *SearchEngine) *PhoneticSearchEngine {
+	return &PhoneticSearchEngine{
+		baseEngine: baseEngine,
+	}
+}
+
+// PhoneticSearch combine Double Metaphone et la variante française
+func (pse *PhoneticSearchEngine) PhoneticSearch(query string) []SearchResult {
+	results := pse.SearchWith(query, PhoneticMetaphone)
+
+	seen := make(map[string]bool)
+	for _, r := range results {
+		seen[r.ArtistName+"-"+r.MatchedText] = true
+	}
+	for _, r := range pse.SearchWith(query, PhoneticFrench) {
+		if key := r.ArtistName + "-" + r.MatchedText; !seen[key] {
+			results = append(results, r)
+			seen[key] = true
+		}
+	}
+
+	return pse.baseEngine.sortByScore(results)
+}
+
+// SearchWith cherche les textes dont chaque mot de la requête a le même code
+// phonétique qu'un de leurs mots, avec un seul algorithme
+func (pse *PhoneticSearchEngine) SearchWith(query string, algorithm PhoneticAlgorithm) []SearchResult {
+	query = foldText(strings.TrimSpace(query))
+
+	if utf8.RuneCountInString(query) < minPhoneticQueryLength {
+		return []SearchResult{}
+	}
+
+	pse.baseEngine.mu.RLock()
+	candidates := pse.baseEngine.index.phoneticCandidates(query, algorithm)
+	pse.baseEngine.mu.RUnlock()
+
+	words := 0
+	for _, word := range strings.Fields(query) {
+		if len(phoneticKeys(word, algorithm)) > 0 {
+			words++
+		}
+	}
+
+	results := []SearchResult{}
+	seen := make(map[string]bool)
+	for _, doc := range candidates {
+		if seen[doc.key] {
+			continue
+		}
+		seen[doc.key] = true
+
+		start, end := 0, utf8.RuneCountInString(doc.text)
+		if doc.typ == SearchTypeLocation {
+			// Seule la ville est codée : on surligne la ville
+			if pos := strings.Index(doc.text, doc.place.City); pos != -1 && doc.place.City != "" {
+				start = runeIndex(doc.text, pos)
+				end = start + utf8.RuneCountInString(doc.place.City)
+			}
+		}
+
+		results = append(results, SearchResult{
+			ArtistID:    doc.artistID,
+			ArtistName:  doc.artistName,
+			MatchedText: doc.text,
+			Type:        doc.typ,
+			Score:       pse.calculatePhoneticScore(words, doc.words, doc.typ),
+			MatchStart:  start,
+			MatchEnd:    end,
+		})
+	}
+
+	return pse.baseEngine.sortByScore(results)
+}
+
+// calculatePhoneticScore calcule le score d'un résultat phonétique, un peu
+// en dessous d'un résultat flou à distance 0
+func (pse *PhoneticSearchEngine) calculatePhoneticScore(queryWords, textWords int, searchType SearchType) int {
+	// Score de base
+	score := 450
+
+	// Bonus selon le type
+	switch searchType {
+	case SearchTypeArtist:
+		score += 300
+	case SearchTypeMember:
+		score += 200
+	case SearchTypeLocation:
+		score += 100
+	}
+
+	// Bonus si tous les mots du texte ont été prononcés
+	if queryWords == textWords {
+		score += 100
+	}
+
+	return score
+}
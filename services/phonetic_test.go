@@ -0,0 +1,161 @@
+package services
+
+import (
+	"testing"
+
+	"groupie-tracker/models"
+)
+
+func TestDoubleMetaphone(t *testing.T) {
+	tests := []struct {
+		word               string
+		primary, alternate string
+	}{
+		{"Zeppelin", "SPLN", "SPLN"},
+		{"Seppelin", "SPLN", "SPLN"},
+		{"Led", "LT", "LT"},
+		{"Ledd", "LT", "LT"},
+		{"Metallica", "MTLK", "MTLK"},
+		{"Metalika", "MTLK", "MTLK"},
+		{"Thomas", "TMS", "TMS"},
+		{"Smith", "SM0", "XMT"},
+		{"Schmidt", "XMT", "SMT"},
+		{"Knight", "NT", "NT"},
+		{"Michael", "MKL", "MXL"},
+		{"Jose", "HS", "HS"},
+		{"Xavier", "SF", "SFR"},
+		{"Björk", "PJRK", "PJRK"},
+		{"", "", ""},
+		{"1986", "", ""},
+	}
+
+	for _, tt := range tests {
+		primary, alternate := DoubleMetaphone(tt.word)
+		if primary != tt.primary || alternate != tt.alternate {
+			t.Errorf("DoubleMetaphone(%q) = (%q, %q), attendu (%q, %q)", tt.word, primary, alternate, tt.primary, tt.alternate)
+		}
+	}
+}
+
+func TestFrenchPhonetic_SameSound(t *testing.T) {
+	pairs := [][2]string{
+		{"Johnny", "Joni"},
+		{"Hallyday", "Aliday"},
+		{"Indochine", "Indauchine"},
+		{"Phoenix", "Fenix"},
+		{"Marseille", "Marseye"},
+		{"Bordeaux", "Bordo"},
+		{"Stromae", "Stromaé"},
+		{"Guetta", "Gueta"},
+	}
+
+	for _, pair := range pairs {
+		a, b := FrenchPhonetic(pair[0]), FrenchPhonetic(pair[1])
+		if a == "" || a != b {
+			t.Errorf("FrenchPhonetic(%q) = %q, FrenchPhonetic(%q) = %q : même son attendu", pair[0], a, pair[1], b)
+		}
+	}
+
+	// Sons différents
+	if FrenchPhonetic("Gilles") == FrenchPhonetic("Guilles") {
+		t.Error("\"gi\" (j) et \"gui\" (g dur) ne doivent pas se confondre")
+	}
+}
+
+func newPhoneticTestEngine(t *testing.T) *SearchEngine {
+	t.Helper()
+	artists := append(createTestArtists(),
+		models.Artist{ID: 4, Name: "Led Zeppelin", Members: []string{"Robert Plant", "Jimmy Page"}},
+		models.Artist{ID: 5, Name: "Metallica", Members: []string{"James Hetfield", "Lars Ulrich"}},
+		models.Artist{ID: 6, Name: "Johnny Hallyday"},
+	)
+	aggregates := map[int]models.ArtistAggregate{
+		6: {Artist: artists[5], Locations: models.Location{ID: 6, Locations: []string{"marseille-france", "bordeaux-france"}}},
+	}
+
+	store := NewArtistStore(newStoreSource())
+	store.Replace(artists, aggregates)
+	engine := NewSearchEngineWithStore(store)
+	t.Cleanup(engine.Close)
+	return engine
+}
+
+func TestPhoneticSearch_SoundAlikes(t *testing.T) {
+	phonetic := NewPhoneticSearchEngine(newPhoneticTestEngine(t))
+
+	tests := []struct {
+		query    string
+		expected string
+		typ      SearchType
+	}{
+		{"Ledd Seppelin", "Led Zeppelin", SearchTypeArtist},
+		{"Led Zepelin", "Led Zeppelin", SearchTypeArtist},
+		{"Metalika", "Metallica", SearchTypeArtist},
+		{"Johny Aliday", "Johnny Hallyday", SearchTypeArtist},
+		{"freddy merkury", "Freddie Mercury", SearchTypeMember},
+		{"Marseye", "Marseille, France", SearchTypeLocation},
+	}
+
+	for _, tt := range tests {
+		results := phonetic.PhoneticSearch(tt.query)
+		if len(results) == 0 {
+			t.Errorf("PhoneticSearch(%q): aucun résultat", tt.query)
+			continue
+		}
+		if results[0].MatchedText != tt.expected || results[0].Type != tt.typ {
+			t.Errorf("PhoneticSearch(%q): attendu %q (%s), got %q (%s)", tt.query, tt.expected, tt.typ, results[0].MatchedText, results[0].Type)
+		}
+	}
+}
+
+func TestPhoneticSearch_ResultShape(t *testing.T) {
+	phonetic := NewPhoneticSearchEngine(newPhoneticTestEngine(t))
+
+	results := phonetic.PhoneticSearch("Bordo")
+	if len(results) != 1 {
+		t.Fatalf("attendu 1 résultat, got %d", len(results))
+	}
+	r := results[0]
+	if r.ArtistID != 6 || r.ArtistName != "Johnny Hallyday" {
+		t.Errorf("mauvais artiste: %+v", r)
+	}
+	// Seule la ville est surlignée
+	if got := runeSlice(r.MatchedText, r.MatchStart, r.MatchEnd); got != "Bordeaux" {
+		t.Errorf("surlignage attendu \"Bordeaux\", got %q", got)
+	}
+}
+
+func TestPhoneticSearch_Algorithms(t *testing.T) {
+	phonetic := NewPhoneticSearchEngine(newPhoneticTestEngine(t))
+
+	// Chaque algorithme s'utilise seul
+	if results := phonetic.SearchWith("Ledd Seppelin", PhoneticMetaphone); len(results) == 0 {
+		t.Error("Double Metaphone devrait reconnaître \"Ledd Seppelin\"")
+	}
+	// "Aliday" : variante française
+	if results := phonetic.SearchWith("Aliday", PhoneticFrench); len(results) == 0 || results[0].ArtistName != "Johnny Hallyday" {
+		t.Errorf("La variante française devrait reconnaître \"Aliday\", got %+v", results)
+	}
+}
+
+func TestPhoneticSearch_ShortOrSilentQueries(t *testing.T) {
+	phonetic := NewPhoneticSearchEngine(newPhoneticTestEngine(t))
+
+	for _, query := range []string{"", "  ", "qu", "1986", "zzzzzz"} {
+		if results := phonetic.PhoneticSearch(query); len(results) != 0 {
+			t.Errorf("PhoneticSearch(%q): aucun résultat attendu, got %+v", query, results)
+		}
+	}
+}
+
+func TestPhoneticSearch_FollowsStore(t *testing.T) {
+	engine := newPhoneticTestEngine(t)
+	phonetic := NewPhoneticSearchEngine(engine)
+
+	engine.Store().AddAggregates(map[int]models.ArtistAggregate{
+		4: {Locations: models.Location{ID: 4, Locations: []string{"seattle-usa"}}},
+	})
+	if results := phonetic.PhoneticSearch("Siatle"); len(results) != 1 || results[0].ArtistID != 4 {
+		t.Errorf("Le lieu ajouté devrait être trouvé, got %+v", results)
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"groupie-tracker/models"
+)
+
+// Tables de référence embarquées : pays (ISO 3166-1) et régions connues
+//
+//go:embed data/countries.csv data/regions.csv
+var placeData embed.FS
+
+// region est un état ou une province reconnu dans une clé de l'API
+type region struct {
+	country string // code ISO du pays
+	name    string
+}
+
+// placeTables regroupe les tables chargées une seule fois
+type placeTables struct {
+	countries []models.Country          // triés par nom
+	byToken   map[string]models.Country // jeton normalisé (API, alias, nom, code) -> pays
+	regions   map[string]region         // "US/north_carolina", "US/nc" -> région
+}
+
+var (
+	placesOnce sync.Once
+	places     *placeTables
+)
+
+func loadPlaceTables() *placeTables {
+	placesOnce.Do(func() {
+		tables := &placeTables{
+			byToken: make(map[string]models.Country),
+			regions: make(map[string]region),
+		}
+
+		for _, row := range readPlaceCSV("data/countries.csv", 4) {
+			country := models.Country{Code: row[0], Name: row[1], Continent: row[2]}
+			tables.countries = append(tables.countries, country)
+
+			tables.byToken[placeKey(country.Code)] = country
+			tables.byToken[placeKey(country.Name)] = country
+			for _, alias := range strings.Fields(row[3]) {
+				tables.byToken[placeKey(alias)] = country
+			}
+		}
+		sort.Slice(tables.countries, func(i, j int) bool {
+			return tables.countries[i].Name < tables.countries[j].Name
+		})
+
+		for _, row := range readPlaceCSV("data/regions.csv", 4) {
+			r := region{country: row[0], name: row[2]}
+			tables.regions[row[0]+"/"+normalizePlaceToken(row[1])] = r
+			for _, abbr := range strings.Fields(row[3]) {
+				tables.regions[row[0]+"/"+normalizePlaceToken(abbr)] = r
+			}
+		}
+
+		places = tables
+	})
+	return places
+}
+
+// readPlaceCSV lit une table embarquée (les lignes "#" sont des commentaires).
+// Une table invalide est une erreur de programmation : on panique.
+func readPlaceCSV(name string, fields int) [][]string {
+	file, err := placeData.Open(name)
+	if err != nil {
+		panic(fmt.Sprintf("table %s introuvable: %v", name, err))
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = fields
+
+	rows := [][]string{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			panic(fmt.Sprintf("table %s invalide: %v", name, err))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// normalizePlaceToken ramène un jeton à sa forme de comparaison :
+// minuscules, séparateurs remplacés par "_", drapeau éventuel retiré
+// ("🇺🇸 États-Unis" -> "états_unis", "Los Angeles" -> "los_angeles")
+func normalizePlaceToken(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r >= 0x1F1E6 && r <= 0x1F1FF {
+			return -1 // symbole indicateur régional (drapeau)
+		}
+		return r
+	}, s)
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	}), "_")
+}
+
+// placeKey est la forme de comparaison d'un jeton de lieu, insensible aux
+// accents ("États-Unis" et "etats_unis" -> "etats_unis")
+func placeKey(s string) string {
+	return foldText(normalizePlaceToken(s))
+}
+
+// LookupCountry retrouve un pays à partir d'un jeton de l'API ("usa"), d'un
+// alias ("united_kingdom"), d'un code ISO ("GB") ou d'un nom affiché
+func LookupCountry(token string) (models.Country, bool) {
+	country, ok := loadPlaceTables().byToken[placeKey(token)]
+	return country, ok
+}
+
+// Countries retourne tous les pays de la table de référence, triés par nom
+func Countries() []models.Country {
+	countries := loadPlaceTables().countries
+	return append([]models.Country(nil), countries...)
+}
+
+// ParsePlace construit le lieu canonique d'une clé de l'API.
+//
+// Le dernier segment (séparé par "-") est le pays, le premier la ville ; un
+// segment intermédiaire est une région ("sydney-new_south_wales-australia").
+// Une région abrégée en suffixe de ville est aussi reconnue ("portland_or-usa").
+func ParsePlace(key string) models.Place {
+	place := models.Place{Key: key}
+
+	segments := []string{}
+	for _, segment := range strings.Split(key, "-") {
+		if segment = normalizePlaceToken(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return place
+	}
+
+	countryToken := segments[len(segments)-1]
+	place.Country = countryFromToken(countryToken)
+	rest := segments[:len(segments)-1]
+	if len(rest) == 0 {
+		return place
+	}
+
+	tables := loadPlaceTables()
+	code := place.Country.Code
+
+	// Région en dernier segment : "sydney-new_south_wales-australia", "austin-tx-usa"
+	if len(rest) > 1 {
+		if r, ok := tables.regions[code+"/"+rest[len(rest)-1]]; ok {
+			place.Region = r.name
+			rest = rest[:len(rest)-1]
+		}
+	}
+
+	// Région abrégée collée à la ville : "portland_or-usa"
+	city := strings.Join(rest, "-")
+	if place.Region == "" {
+		if i := strings.LastIndex(city, "_"); i > 0 && len(city)-i-1 <= 3 {
+			if r, ok := tables.regions[code+"/"+city[i+1:]]; ok {
+				place.Region = r.name
+				city = city[:i]
+			}
+		}
+	}
+
+	place.City = titleizeToken(city)
+	return place
+}
+
+// countryFromToken retourne le pays de la table, ou un pays inconnu
+// dont le nom est dérivé du jeton
+func countryFromToken(token string) models.Country {
+	if country, ok := LookupCountry(token); ok {
+		return country
+	}
+	return models.Country{Name: titleizeToken(token)}
+}
+
+// titleizeToken transforme un jeton de l'API en nom affiché
+// ("los_angeles" -> "Los Angeles", "winston-salem" -> "Winston-Salem")
+func titleizeToken(token string) string {
+	words := strings.Split(strings.ReplaceAll(token, "_", " "), " ")
+	for i, word := range words {
+		parts := strings.Split(word, "-")
+		for j, part := range parts {
+			runes := []rune(part)
+			if len(runes) > 0 {
+				runes[0] = unicode.ToUpper(runes[0])
+			}
+			parts[j] = string(runes)
+		}
+		words[i] = strings.Join(parts, "-")
+	}
+	return strings.Join(words, " ")
+}
+
+// MatchesPlace vérifie si wanted désigne le lieu : pays (jeton, alias, code
+// ou nom), ville, région, nom affiché ou clé brute
+func MatchesPlace(place models.Place, wanted string) bool {
+	token := placeKey(wanted)
+	if token == "" {
+		return false
+	}
+
+	if country, ok := LookupCountry(wanted); ok && country.Code == place.Country.Code {
+		return true
+	}
+
+	for _, candidate := range []string{place.City, place.Region, place.Country.Name, place.String(), place.Key} {
+		if candidate != "" && placeKey(candidate) == token {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+
+	"groupie-tracker/models"
+)
+
+func TestParsePlace(t *testing.T) {
+	tests := []struct {
+		key     string
+		city    string
+		region  string
+		code    string
+		country string
+	}{
+		{"los_angeles-usa", "Los Angeles", "", "US", "États-Unis"},
+		{"london-uk", "London", "", "GB", "Royaume-Uni"},
+		{"penrose-new_zealand", "Penrose", "", "NZ", "Nouvelle-Zélande"},
+		{"sydney-new_south_wales-australia", "Sydney", "New South Wales", "AU", "Australie"},
+		{"austin-tx-usa", "Austin", "Texas", "US", "États-Unis"},
+		{"portland_or-usa", "Portland", "Oregon", "US", "États-Unis"},
+		{"winston-salem-usa", "Winston-Salem", "", "US", "États-Unis"},
+		{"north_carolina-usa", "North Carolina", "", "US", "États-Unis"},
+		{"Playa_Del_Carmen-Mexico", "Playa Del Carmen", "", "MX", "Mexique"},
+		{"springfield-atlantis", "Springfield", "", "", "Atlantis"},
+	}
+
+	for _, tt := range tests {
+		place := ParsePlace(tt.key)
+		if place.City != tt.city || place.Region != tt.region || place.Country.Code != tt.code || place.Country.Name != tt.country {
+			t.Errorf("ParsePlace(%q) = %+v", tt.key, place)
+		}
+		if place.Key != tt.key {
+			t.Errorf("ParsePlace(%q) devrait conserver la clé brute, got %q", tt.key, place.Key)
+		}
+	}
+}
+
+func TestLookupCountry(t *testing.T) {
+	for _, token := range []string{"usa", "US", "united_states", "United States", "États-Unis", "🇺🇸 États-Unis"} {
+		country, ok := LookupCountry(token)
+		if !ok || country.Code != "US" {
+			t.Errorf("LookupCountry(%q) = %+v, %v", token, country, ok)
+		}
+	}
+
+	if country, _ := LookupCountry("uk"); country.Continent != "Europe" || country.Flag() != "🇬🇧" {
+		t.Errorf("LookupCountry(uk) = %+v", country)
+	}
+	if _, ok := LookupCountry("atlantis"); ok {
+		t.Error("Un pays inconnu ne devrait pas être trouvé")
+	}
+}
+
+func TestCountries_SortedAndUnique(t *testing.T) {
+	countries := Countries()
+	seen := map[string]bool{}
+	for i, country := range countries {
+		if seen[country.Code] {
+			t.Errorf("Code %s en double", country.Code)
+		}
+		seen[country.Code] = true
+		if i > 0 && countries[i-1].Name > country.Name {
+			t.Errorf("Pays non triés: %s avant %s", countries[i-1].Name, country.Name)
+		}
+	}
+}
+
+func TestMatchesPlace(t *testing.T) {
+	place := ParsePlace("sydney-new_south_wales-australia")
+
+	for _, wanted := range []string{"australia", "AU", "Australie", "🇦🇺 Australie", "sydney", "New South Wales", "Sydney, Australie"} {
+		if !MatchesPlace(place, wanted) {
+			t.Errorf("MatchesPlace(%q) devrait correspondre", wanted)
+		}
+	}
+	for _, wanted := range []string{"", "usa", "melbourne"} {
+		if MatchesPlace(place, wanted) {
+			t.Errorf("MatchesPlace(%q) ne devrait pas correspondre", wanted)
+		}
+	}
+}
+
+func TestSearchEngine_LocationByCountryAlias(t *testing.T) {
+	engine := NewSearchEngine([]models.Artist{{ID: 1, Name: "Queen"}})
+	engine.AddAggregates(map[int]models.ArtistAggregate{
+		1: {Locations: models.Location{Locations: []string{"london-uk"}}},
+	})
+
+	results := engine.SearchByType("uk", SearchTypeLocation)
+	if len(results) != 1 {
+		t.Fatalf("Attendu 1 résultat, got %d", len(results))
+	}
+
+	got := results[0]
+	if got.MatchedText != "London, Royaume-Uni" || got.MatchedText[got.MatchStart:got.MatchEnd] != "Royaume-Uni" {
+		t.Errorf("Résultat inattendu: %+v", got)
+	}
+}
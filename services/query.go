@@ -0,0 +1,511 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"groupie-tracker/models"
+)
+
+// =====================
+// LANGAGE DE REQUÊTE
+// =====================
+//
+// La barre de recherche accepte, en plus du texte libre, des critères par
+// champ combinés par un ET implicite :
+//
+//	member:john country:germany year:>=1995 concert:2019 "exact phrase" -exclude
+//
+// Les champs numériques (year, album, concert) acceptent une valeur exacte,
+// une comparaison (>, >=, <, <=) ou un intervalle inclusif (1990..1999).
+// Un "-" en tête de critère l'exclut (sauf devant un chiffre : "-19" reste
+// un texte, pour chercher dans les dates).
+
+// QueryField identifie le champ visé par un critère
+type QueryField string
+
+const (
+	FieldAny      QueryField = ""         // texte libre : tous les champs
+	FieldArtist   QueryField = "artist"   // nom de l'artiste
+	FieldMember   QueryField = "member"   // nom d'un membre
+	FieldLocation QueryField = "location" // lieu de concert
+	FieldCountry  QueryField = "country"  // pays d'un concert
+	FieldYear     QueryField = "year"     // année de création
+	FieldAlbum    QueryField = "album"    // date du premier album
+	FieldConcert  QueryField = "concert"  // date d'un concert
+)
+
+// QueryFieldInfo décrit un champ du langage de requête
+type QueryFieldInfo struct {
+	Field       QueryField
+	Aliases     []string
+	Description string
+	Numeric     bool // valeur comparable (année ou date)
+}
+
+var queryFields = []QueryFieldInfo{
+	{FieldArtist, []string{"name"}, "nom de l'artiste", false},
+	{FieldMember, nil, "nom d'un membre", false},
+	{FieldLocation, []string{"city"}, "ville ou lieu de concert", false},
+	{FieldCountry, nil, "pays d'un concert", false},
+	{FieldYear, []string{"created"}, "année de création (year:>=1995)", true},
+	{FieldAlbum, nil, "premier album (album:1970..1979)", true},
+	{FieldConcert, []string{"date"}, "date de concert (concert:2019)", true},
+}
+
+// QueryFields retourne les champs disponibles, dans l'ordre d'affichage
+func QueryFields() []QueryFieldInfo {
+	return append([]QueryFieldInfo(nil), queryFields...)
+}
+
+// lookupQueryField retrouve un champ par son nom ou un alias
+func lookupQueryField(name string) (QueryFieldInfo, bool) {
+	name = strings.ToLower(name)
+	for _, info := range queryFields {
+		if string(info.Field) == name {
+			return info, true
+		}
+		for _, alias := range info.Aliases {
+			if alias == name {
+				return info, true
+			}
+		}
+	}
+	return QueryFieldInfo{}, false
+}
+
+// QueryNode est un nœud de l'arbre syntaxique d'une requête
+type QueryNode interface {
+	String() string
+	queryNode()
+}
+
+// TermNode cherche un texte, dans un champ ou partout (FieldAny)
+type TermNode struct {
+	Field  QueryField
+	Text   string // en minuscules
+	Phrase bool   // entre guillemets
+}
+
+// CompareOp est l'opérateur d'un critère numérique
+type CompareOp string
+
+const (
+	OpEqual        CompareOp = "="
+	OpGreater      CompareOp = ">"
+	OpGreaterEqual CompareOp = ">="
+	OpLess         CompareOp = "<"
+	OpLessEqual    CompareOp = "<="
+	OpBetween      CompareOp = ".."
+)
+
+// QueryDate est une borne de comparaison : une année ou un jour précis
+type QueryDate struct {
+	Time     time.Time
+	YearOnly bool
+}
+
+// String retourne la borne telle qu'elle s'écrit dans une requête
+func (d QueryDate) String() string {
+	if d.YearOnly {
+		return strconv.Itoa(d.Time.Year())
+	}
+	return d.Time.Format(models.DateLayout)
+}
+
+// compare retourne -1, 0 ou 1 selon la position de t par rapport à la
+// borne, à la granularité de la borne
+func (d QueryDate) compare(t time.Time) int {
+	if d.YearOnly {
+		switch {
+		case t.Year() < d.Time.Year():
+			return -1
+		case t.Year() > d.Time.Year():
+			return 1
+		}
+		return 0
+	}
+	ty, tm, td := t.Date()
+	dy, dm, dd := d.Time.Date()
+	switch {
+	case ty != dy:
+		return sign(ty - dy)
+	case tm != dm:
+		return sign(int(tm) - int(dm))
+	}
+	return sign(td - dd)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// RangeNode compare une année ou une date à une borne (ou un intervalle)
+type RangeNode struct {
+	Field QueryField
+	Op    CompareOp
+	Value QueryDate
+	Upper QueryDate // borne haute pour OpBetween
+}
+
+// Matches indique si t satisfait le critère
+func (n RangeNode) Matches(t time.Time) bool {
+	cmp := n.Value.compare(t)
+	switch n.Op {
+	case OpGreater:
+		return cmp > 0
+	case OpGreaterEqual:
+		return cmp >= 0
+	case OpLess:
+		return cmp < 0
+	case OpLessEqual:
+		return cmp <= 0
+	case OpBetween:
+		return cmp >= 0 && n.Upper.compare(t) <= 0
+	}
+	return cmp == 0
+}
+
+// NotNode exclut les artistes correspondant au critère
+type NotNode struct {
+	Node QueryNode
+}
+
+func (TermNode) queryNode()  {}
+func (RangeNode) queryNode() {}
+func (NotNode) queryNode()   {}
+
+func (n TermNode) String() string {
+	text := n.Text
+	if n.Phrase || strings.ContainsAny(text, " \t") {
+		text = `"` + text + `"`
+	}
+	if n.Field == FieldAny {
+		return text
+	}
+	return string(n.Field) + ":" + text
+}
+
+func (n RangeNode) String() string {
+	switch n.Op {
+	case OpEqual:
+		return string(n.Field) + ":" + n.Value.String()
+	case OpBetween:
+		return string(n.Field) + ":" + n.Value.String() + ".." + n.Upper.String()
+	}
+	return string(n.Field) + ":" + string(n.Op) + n.Value.String()
+}
+
+func (n NotNode) String() string {
+	return "-" + n.Node.String()
+}
+
+// Query est une requête analysée : tous ses nœuds doivent être satisfaits
+type Query struct {
+	Input string
+	Nodes []QueryNode
+}
+
+// String retourne la forme normalisée de la requête
+func (q *Query) String() string {
+	parts := make([]string, len(q.Nodes))
+	for i, node := range q.Nodes {
+		parts[i] = node.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// Structured indique si la requête utilise la syntaxe (champ, phrase,
+// exclusion). Une requête de texte libre garde la recherche classique sur la
+// saisie entière.
+func (q *Query) Structured() bool {
+	for _, node := range q.Nodes {
+		term, ok := node.(TermNode)
+		if !ok || term.Field != FieldAny || term.Phrase {
+			return true
+		}
+	}
+	return false
+}
+
+// QuerySyntaxError signale une erreur de syntaxe et sa position (en octets)
+type QuerySyntaxError struct {
+	Pos         int
+	Message     string
+	Suggestions []string // corrections possibles ("member:"...)
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("position %d : %s", e.Pos+1, e.Message)
+}
+
+// ParseQuery analyse une saisie de la barre de recherche
+func ParseQuery(input string) (*Query, error) {
+	p := &queryParser{input: input}
+	query := &Query{Input: input}
+
+	for {
+		p.skipSpaces()
+		if p.done() {
+			return query, nil
+		}
+		node, err := p.parseNode()
+		if err != nil {
+			return nil, err
+		}
+		query.Nodes = append(query.Nodes, node)
+	}
+}
+
+type queryParser struct {
+	input string
+	pos   int
+}
+
+func (p *queryParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *queryParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *queryParser) skipSpaces() {
+	for !p.done() && isQuerySpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// word lit jusqu'au prochain espace
+func (p *queryParser) word() string {
+	start := p.pos
+	for !p.done() && !isQuerySpace(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// quoted lit un texte entre guillemets (le guillemet ouvrant est courant)
+func (p *queryParser) quoted() (string, error) {
+	start := p.pos
+	p.pos++
+	end := strings.IndexByte(p.input[p.pos:], '"')
+	if end == -1 {
+		p.pos = len(p.input)
+		return "", &QuerySyntaxError{Pos: start, Message: "guillemet fermant manquant"}
+	}
+	text := p.input[p.pos : p.pos+end]
+	p.pos += end + 1
+	if strings.TrimSpace(text) == "" {
+		return "", &QuerySyntaxError{Pos: start, Message: "phrase vide"}
+	}
+	return text, nil
+}
+
+func (p *queryParser) parseNode() (QueryNode, error) {
+	start := p.pos
+
+	// Exclusion : "-terme", mais "-19" reste un texte (dates)
+	if p.peek() == '-' && p.pos+1 < len(p.input) && !isASCIIDigit(p.input[p.pos+1]) {
+		p.pos++
+		if p.done() || isQuerySpace(p.peek()) || p.peek() == '-' {
+			return nil, &QuerySyntaxError{Pos: start, Message: "critère manquant après « - »"}
+		}
+		node, err := p.parseNode()
+		if err != nil {
+			return nil, err
+		}
+		return NotNode{Node: node}, nil
+	}
+
+	if p.peek() == '"' {
+		text, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return TermNode{Text: strings.ToLower(text), Phrase: true}, nil
+	}
+
+	// Champ : lettres suivies de ":"
+	nameEnd := p.pos
+	for nameEnd < len(p.input) && isASCIILetter(p.input[nameEnd]) {
+		nameEnd++
+	}
+	if nameEnd > p.pos && nameEnd < len(p.input) && p.input[nameEnd] == ':' {
+		name := p.input[p.pos:nameEnd]
+		info, ok := lookupQueryField(name)
+		if !ok {
+			return nil, &QuerySyntaxError{
+				Pos:         start,
+				Message:     fmt.Sprintf("champ inconnu « %s »", name),
+				Suggestions: suggestQueryFields(name),
+			}
+		}
+		p.pos = nameEnd + 1
+		return p.parseFieldValue(info, start)
+	}
+
+	return TermNode{Text: strings.ToLower(p.word())}, nil
+}
+
+// parseFieldValue lit la valeur d'un critère "champ:valeur"
+func (p *queryParser) parseFieldValue(info QueryFieldInfo, start int) (QueryNode, error) {
+	valueStart := p.pos
+	if p.done() || isQuerySpace(p.peek()) {
+		return nil, &QuerySyntaxError{Pos: start, Message: fmt.Sprintf("valeur manquante après « %s: »", info.Field)}
+	}
+
+	if !info.Numeric {
+		if p.peek() == '"' {
+			text, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			return TermNode{Field: info.Field, Text: strings.ToLower(text), Phrase: true}, nil
+		}
+		value := p.word()
+		if strings.ContainsAny(value[:1], "<>=") {
+			return nil, &QuerySyntaxError{Pos: valueStart, Message: fmt.Sprintf("« %s » n'accepte pas de comparaison", info.Field)}
+		}
+		return TermNode{Field: info.Field, Text: strings.ToLower(value)}, nil
+	}
+
+	value := p.word()
+	node := RangeNode{Field: info.Field, Op: OpEqual}
+	for _, op := range []CompareOp{OpGreaterEqual, OpLessEqual, OpGreater, OpLess, OpEqual} {
+		if strings.HasPrefix(value, string(op)) {
+			node.Op = op
+			value = value[len(op):]
+			break
+		}
+	}
+
+	// Mois entier ("concert:12/2019", "concert:2019-12") : intervalle de jours
+	if node.Op == OpEqual && info.Field != FieldYear {
+		if period, ok := ParseDateQuery(value); ok && period.Precision == PrecisionMonth {
+			node.Op = OpBetween
+			node.Value, node.Upper = QueryDate{Time: period.From}, QueryDate{Time: period.To}
+			return node, nil
+		}
+	}
+
+	var err error
+	if from, to, isRange := strings.Cut(value, ".."); isRange && node.Op == OpEqual {
+		node.Op = OpBetween
+		if node.Value, err = parseQueryDate(info, from, valueStart); err != nil {
+			return nil, err
+		}
+		if node.Upper, err = parseQueryDate(info, to, valueStart); err != nil {
+			return nil, err
+		}
+		if node.Upper.Time.Before(node.Value.Time) {
+			return nil, &QuerySyntaxError{Pos: valueStart, Message: "intervalle inversé"}
+		}
+		return node, nil
+	}
+
+	if node.Value, err = parseQueryDate(info, value, valueStart); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// parseQueryDate lit une année (AAAA) ou une date (JJ-MM-AAAA)
+func parseQueryDate(info QueryFieldInfo, value string, pos int) (QueryDate, error) {
+	if len(value) == 4 {
+		if year, err := strconv.Atoi(value); err == nil {
+			return QueryDate{Time: yearTime(year), YearOnly: true}, nil
+		}
+	}
+
+	if info.Field != FieldYear {
+		// JJ-MM-AAAA, JJ/MM/AAAA ou AAAA-MM-JJ
+		if period, ok := ParseDateQuery(value); ok && period.Precision == PrecisionDay {
+			return QueryDate{Time: period.From}, nil
+		}
+		return QueryDate{}, &QuerySyntaxError{Pos: pos, Message: fmt.Sprintf("date invalide « %s » (AAAA, JJ-MM-AAAA ou MM/AAAA)", value)}
+	}
+	return QueryDate{}, &QuerySyntaxError{Pos: pos, Message: fmt.Sprintf("année invalide « %s » (AAAA)", value)}
+}
+
+func yearTime(year int) time.Time {
+	return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// isQuerySpace teste un octet : les octets non ASCII d'une lettre accentuée
+// ne doivent pas être pris pour des espaces
+func isQuerySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// suggestQueryFields propose les champs proches d'un nom inconnu
+func suggestQueryFields(name string) []string {
+	suggestions := []string{}
+	for _, info := range queryFields {
+		field := string(info.Field)
+		if strings.HasPrefix(field, strings.ToLower(name)) || levenshteinDistance(name, field) <= 2 {
+			suggestions = append(suggestions, field+":")
+		}
+	}
+	return suggestions
+}
+
+// =====================
+// COMPLÉTION
+// =====================
+
+// CompleteQueryField propose les champs commençant par le dernier mot de la
+// saisie ("mem" -> "member:"). Retourne nil si le mot n'est pas un début de
+// champ (déjà qualifié, entre guillemets, vide...).
+func CompleteQueryField(input string) []string {
+	last := lastQueryWord(input)
+	last = strings.TrimPrefix(last, "-")
+	if last == "" || strings.ContainsAny(last, `:"`) {
+		return nil
+	}
+
+	completions := []string{}
+	for _, info := range queryFields {
+		for _, name := range append([]string{string(info.Field)}, info.Aliases...) {
+			if strings.HasPrefix(name, strings.ToLower(last)) {
+				completions = append(completions, string(info.Field)+":")
+				break
+			}
+		}
+	}
+	return completions
+}
+
+// ApplyQueryCompletion remplace le dernier mot de la saisie par completion
+// (en conservant un éventuel "-" d'exclusion)
+func ApplyQueryCompletion(input, completion string) string {
+	last := lastQueryWord(input)
+	prefix := input[:len(input)-len(last)]
+	if strings.HasPrefix(last, "-") {
+		prefix += "-"
+	}
+	return prefix + completion
+}
+
+func lastQueryWord(input string) string {
+	return input[strings.LastIndexAny(input, " \t\n\r")+1:]
+}
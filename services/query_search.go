@@ -0,0 +1,293 @@
+package services
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"groupie-tracker/models"
+)
+
+// queryPlace est un lieu d'un artiste avec ses dates de concert
+type queryPlace struct {
+	place   models.Place
+	display string
+	dates   []models.Date
+}
+
+// queryTarget rassemble les champs d'un artiste évalués par une requête
+type queryTarget struct {
+	artist models.Artist
+	loaded bool // agrégat chargé : lieux et concerts connus
+	places []queryPlace
+}
+
+// queryMatch est le texte retenu pour le surlignage d'un résultat
+type queryMatch struct {
+	text       string
+	typ        SearchType
+	start, end int // en runes
+}
+
+func newQueryTarget(state *StoreState, artist models.Artist) queryTarget {
+	target := queryTarget{artist: artist}
+
+	aggregate, exists := state.Aggregate(artist.ID)
+	if !exists {
+		return target
+	}
+	target.loaded = true
+
+	// Lieux de Locations puis lieux n'apparaissant que dans Relation
+	seen := make(map[string]bool)
+	keys := append([]string{}, aggregate.Locations.Locations...)
+	for location := range aggregate.Relation.DatesLocations {
+		keys = append(keys, location)
+	}
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		place := ParsePlace(key)
+		target.places = append(target.places, queryPlace{
+			place:   place,
+			display: place.String(),
+			dates:   aggregate.Relation.DatesLocations[key],
+		})
+	}
+	return target
+}
+
+// SearchQuery évalue une requête analysée : un résultat par artiste
+// satisfaisant tous les critères, surligné sur le premier critère textuel.
+// Les critères de concert (location, country, concert) doivent être
+// satisfaits par un même concert : "country:germany concert:>2019" trouve les
+// artistes ayant joué en Allemagne après 2019.
+func (se *SearchEngine) SearchQuery(query *Query) []SearchResult {
+	results := []SearchResult{}
+	if query == nil || len(query.Nodes) == 0 {
+		return results
+	}
+
+	state := se.store.State()
+	for _, artist := range state.Artists {
+		target := newQueryTarget(state, artist)
+		match, ok := se.evaluateQuery(query, target)
+		if !ok {
+			continue
+		}
+
+		result := SearchResult{
+			ArtistID:    artist.ID,
+			ArtistName:  artist.Name,
+			MatchedText: artist.Name,
+			Type:        SearchTypeArtist,
+		}
+		if match != nil {
+			result.MatchedText, result.Type = match.text, match.typ
+			result.MatchStart, result.MatchEnd = match.start, match.end
+		}
+		if result.MatchedText != "" {
+			result.Score = se.calculateScore(result.MatchedText, runeSlice(result.MatchedText, result.MatchStart, result.MatchEnd), result.MatchStart, result.Type)
+		}
+		results = append(results, result)
+	}
+
+	return se.sortByScore(results)
+}
+
+// evaluateQuery vérifie tous les nœuds pour un artiste et retourne le match
+// du premier critère textuel positif
+func (se *SearchEngine) evaluateQuery(query *Query, target queryTarget) (*queryMatch, bool) {
+	var highlight *queryMatch
+	concertNodes := []QueryNode{}
+
+	for _, node := range query.Nodes {
+		switch n := node.(type) {
+		case NotNode:
+			if isConcertNode(n.Node) {
+				// Exclu dès qu'un lieu ou un concert correspond
+				if _, found := matchConcert(target, []QueryNode{n.Node}); found {
+					return nil, false
+				}
+				continue
+			}
+			if _, ok := matchArtistNode(n.Node, target); ok {
+				return nil, false
+			}
+		default:
+			if isConcertNode(node) {
+				concertNodes = append(concertNodes, node)
+				continue
+			}
+			match, ok := matchArtistNode(node, target)
+			if !ok {
+				return nil, false
+			}
+			if highlight == nil {
+				highlight = match
+			}
+		}
+	}
+
+	if len(concertNodes) > 0 {
+		place, found := matchConcert(target, concertNodes)
+		if !found {
+			return nil, false
+		}
+		if highlight == nil {
+			highlight = place
+		}
+	}
+
+	return highlight, true
+}
+
+// isConcertNode indique si un critère porte sur un concert (lieu ou date)
+func isConcertNode(node QueryNode) bool {
+	switch n := node.(type) {
+	case TermNode:
+		return n.Field == FieldLocation || n.Field == FieldCountry
+	case RangeNode:
+		return n.Field == FieldConcert
+	}
+	return false
+}
+
+// matchArtistNode évalue un critère portant sur l'artiste lui-même. Un texte
+// libre cherche dans le nom, les membres, le premier album puis les lieux.
+func matchArtistNode(node QueryNode, target queryTarget) (*queryMatch, bool) {
+	artist := target.artist
+
+	switch n := node.(type) {
+	case RangeNode:
+		switch n.Field {
+		case FieldYear:
+			if artist.CreationDate == 0 {
+				return nil, false
+			}
+			return nil, n.Matches(yearTime(artist.CreationDate))
+		case FieldAlbum:
+			return nil, !artist.FirstAlbum.IsZero() && n.Matches(artist.FirstAlbum.Time)
+		}
+		return nil, false
+
+	case TermNode:
+		text := foldText(n.Text)
+		if n.Field == FieldAny || n.Field == FieldArtist {
+			if match := matchQueryText(artist.Name, text, SearchTypeArtist); match != nil {
+				return match, true
+			}
+		}
+		if n.Field == FieldAny || n.Field == FieldMember {
+			for _, member := range artist.Members {
+				if match := matchQueryText(member, text, SearchTypeMember); match != nil {
+					return match, true
+				}
+			}
+		}
+		if n.Field == FieldAny {
+			if match := matchQueryText(artist.FirstAlbum.String(), text, SearchTypeDate); match != nil {
+				return match, true
+			}
+			for _, place := range target.places {
+				if start, end := matchPlace(place.place, place.display, text); start != -1 {
+					return &queryMatch{text: place.display, typ: SearchTypeLocation, start: start, end: end}, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// matchConcert cherche un lieu satisfaisant tous les critères de lieu et
+// ayant au moins une date satisfaisant tous les critères de date
+func matchConcert(target queryTarget, nodes []QueryNode) (*queryMatch, bool) {
+	for _, place := range target.places {
+		var match *queryMatch
+		dateNodes := []RangeNode{}
+		placeOK := true
+
+		for _, node := range nodes {
+			switch n := node.(type) {
+			case TermNode:
+				m := matchPlaceNode(place, n)
+				if m == nil {
+					placeOK = false
+				} else if match == nil {
+					match = m
+				}
+			case RangeNode:
+				dateNodes = append(dateNodes, n)
+			}
+			if !placeOK {
+				break
+			}
+		}
+		if !placeOK {
+			continue
+		}
+
+		if len(dateNodes) > 0 && !anyDateMatches(place.dates, dateNodes) {
+			continue
+		}
+		if match == nil {
+			match = &queryMatch{text: place.display, typ: SearchTypeLocation}
+		}
+		return match, true
+	}
+	return nil, false
+}
+
+// matchPlaceNode évalue un critère location: ou country: sur un lieu
+func matchPlaceNode(place queryPlace, node TermNode) *queryMatch {
+	text := foldText(node.Text)
+
+	if node.Field == FieldCountry {
+		country := place.place.Country
+		if wanted, ok := LookupCountry(text); ok {
+			if wanted.Code != country.Code {
+				return nil
+			}
+		} else if !strings.Contains(foldText(country.Name), text) {
+			return nil
+		}
+		if pos := strings.LastIndex(place.display, country.Name); pos != -1 && country.Name != "" {
+			start := runeIndex(place.display, pos)
+			return &queryMatch{text: place.display, typ: SearchTypeLocation, start: start, end: start + utf8.RuneCountInString(country.Name)}
+		}
+		return &queryMatch{text: place.display, typ: SearchTypeLocation}
+	}
+
+	if start, end := matchPlace(place.place, place.display, text); start != -1 {
+		return &queryMatch{text: place.display, typ: SearchTypeLocation, start: start, end: end}
+	}
+	return nil
+}
+
+// anyDateMatches indique si une des dates satisfait tous les critères
+func anyDateMatches(dates []models.Date, nodes []RangeNode) bool {
+	for _, date := range dates {
+		if date.IsZero() {
+			continue
+		}
+		ok := true
+		for _, node := range nodes {
+			if !node.Matches(date.Time) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matchQueryText(text, query string, typ SearchType) *queryMatch {
+	if start, end := foldIndex(text, query); start != -1 {
+		return &queryMatch{text: text, typ: typ, start: start, end: end}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"testing"
+
+	"groupie-tracker/models"
+)
+
+// queryTestEngine : Queen, les Beatles et Pink Floyd avec quelques concerts
+func queryTestEngine() *SearchEngine {
+	artists := createTestArtists()
+	aggregates := map[int]models.ArtistAggregate{
+		1: {Artist: artists[0],
+			Locations: models.Location{Locations: []string{"london-uk", "los_angeles-usa"}},
+			Relation: models.Relation{DatesLocations: map[string][]models.Date{
+				"london-uk":       testDates("12-07-1986"),
+				"los_angeles-usa": testDates("20-04-2019"),
+			}}},
+		2: {Artist: artists[1],
+			Locations: models.Location{Locations: []string{"hamburg-germany", "liverpool-uk"}},
+			Relation: models.Relation{DatesLocations: map[string][]models.Date{
+				"hamburg-germany": testDates("17-08-1960"),
+				"liverpool-uk":    testDates("09-02-2020"),
+			}}},
+		3: {Artist: artists[2],
+			Locations: models.Location{Locations: []string{"berlin-germany"}},
+			Relation: models.Relation{DatesLocations: map[string][]models.Date{
+				"berlin-germany": testDates("21-07-2020"),
+			}}},
+	}
+
+	store := NewArtistStore(newStoreSource())
+	store.Replace(artists, aggregates)
+	return NewSearchEngineWithStore(store)
+}
+
+func resultIDs(results []SearchResult) map[int]bool {
+	ids := make(map[int]bool)
+	for _, result := range results {
+		ids[result.ArtistID] = true
+	}
+	return ids
+}
+
+func TestSearchQuery_Criteria(t *testing.T) {
+	engine := queryTestEngine()
+	defer engine.Close()
+
+	tests := []struct {
+		query    string
+		expected []int
+	}{
+		{"member:john", []int{1, 2}},
+		{"member:john country:germany", []int{2}},
+		// Le même concert doit être en Allemagne ET après 2019
+		{"member:john country:germany concert:>2019", []int{}},
+		{"country:germany concert:>=2020", []int{3}},
+		{"country:allemagne", []int{2, 3}},
+		{"-country:usa", []int{2, 3}},
+		{"year:>=1965", []int{1, 3}},
+		{"year:1960..1965", []int{2, 3}},
+		{"album:<1965", []int{2}},
+		{"album:14-12-1973", []int{1}},
+		{"concert:2019", []int{1}},
+		{"location:london", []int{1}},
+		{`"roger waters"`, []int{3}},
+		{`"roger  waters"`, []int{}},
+		{"roger -floyd", []int{1}},
+		{"artist:the", []int{2}},
+		{"-member:john -member:roger", []int{}},
+	}
+
+	for _, tt := range tests {
+		got := resultIDs(engine.Search(tt.query))
+		if len(got) != len(tt.expected) {
+			t.Errorf("%q: attendu %v, got %v", tt.query, tt.expected, got)
+			continue
+		}
+		for _, id := range tt.expected {
+			if !got[id] {
+				t.Errorf("%q: artiste %d manquant (got %v)", tt.query, id, got)
+			}
+		}
+	}
+}
+
+func TestSearchQuery_Highlight(t *testing.T) {
+	engine := queryTestEngine()
+	defer engine.Close()
+
+	results := engine.Search("year:>=1960 member:john country:uk")
+	if len(results) != 2 {
+		t.Fatalf("Attendu 2 résultats, got %+v", results)
+	}
+	for _, result := range results {
+		before, match, _ := engine.HighlightMatch(result)
+		if result.Type != SearchTypeMember || match != "John" || before != "" {
+			t.Errorf("Surlignage sur le premier critère textuel attendu, got %+v", result)
+		}
+	}
+
+	results = engine.Search("country:germany concert:2020")
+	if len(results) != 1 || results[0].Type != SearchTypeLocation || results[0].MatchedText != "Berlin, Allemagne" {
+		t.Fatalf("Résultat de lieu attendu, got %+v", results)
+	}
+	if _, match, _ := engine.HighlightMatch(results[0]); match != "Allemagne" {
+		t.Errorf("Le pays devrait être surligné, got %q", match)
+	}
+
+	// Critères numériques seuls : résultat sur l'artiste, sans surlignage
+	results = engine.Search("year:1970")
+	if len(results) != 1 || results[0].Type != SearchTypeArtist || results[0].MatchStart != results[0].MatchEnd {
+		t.Errorf("Résultat d'artiste attendu, got %+v", results)
+	}
+}
+
+func TestSearchQuery_InvalidFallsBackToText(t *testing.T) {
+	engine := queryTestEngine()
+	defer engine.Close()
+
+	// Syntaxe invalide : recherche classique sur la saisie entière, dont la
+	// ponctuation est ignorée
+	if results := engine.Search(`queen "`); len(results) != 1 || results[0].MatchedText != "Queen" {
+		t.Errorf("« queen \" » doit chercher « queen », got %+v", results)
+	}
+	if results := engine.Search("-19"); len(results) == 0 {
+		t.Error("« -19 » doit rester une recherche de date")
+	}
+}
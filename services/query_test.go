@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		input      string
+		normalized string
+		structured bool
+	}{
+		{"queen", "queen", false},
+		{"pink floyd", "pink floyd", false},
+		{"-19", "-19", false},
+		{"Member:John", "member:john", true},
+		{`member:"John Paul"`, `member:"john paul"`, true},
+		{"name:queen city:paris", "artist:queen location:paris", true},
+		{`"exact phrase" -exclude`, `"exact phrase" -exclude`, true},
+		{"-country:usa", "-country:usa", true},
+		{"year:>=1995 album:<1970 concert:2019", "year:>=1995 album:<1970 concert:2019", true},
+		{"album:1970..1979 concert:>12-07-2019", "album:1970..1979 concert:>12-07-2019", true},
+		{"beyoncé member:élodie", "beyoncé member:élodie", true},
+	}
+
+	for _, tt := range tests {
+		query, err := ParseQuery(tt.input)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): erreur inattendue %v", tt.input, err)
+			continue
+		}
+		if got := query.String(); got != tt.normalized {
+			t.Errorf("ParseQuery(%q) = %q, attendu %q", tt.input, got, tt.normalized)
+		}
+		if query.Structured() != tt.structured {
+			t.Errorf("ParseQuery(%q).Structured() = %v", tt.input, query.Structured())
+		}
+	}
+}
+
+func TestParseQuery_AST(t *testing.T) {
+	query, err := ParseQuery(`member:john -country:usa year:1990..1999 "let it be"`)
+	if err != nil {
+		t.Fatalf("ParseQuery: %v", err)
+	}
+
+	expected := []QueryNode{
+		TermNode{Field: FieldMember, Text: "john"},
+		NotNode{Node: TermNode{Field: FieldCountry, Text: "usa"}},
+		RangeNode{Field: FieldYear, Op: OpBetween, Value: QueryDate{Time: yearTime(1990), YearOnly: true}, Upper: QueryDate{Time: yearTime(1999), YearOnly: true}},
+		TermNode{Text: "let it be", Phrase: true},
+	}
+	if !reflect.DeepEqual(query.Nodes, expected) {
+		t.Errorf("AST inattendu:\n got: %#v\nwant: %#v", query.Nodes, expected)
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+	}{
+		{`"unterminated`, 0},
+		{`queen member:"john`, 13},
+		{"queen member:", 6},
+		{"membre:john", 0},
+		{"year:>=19x5", 5},
+		{"year:12-07-1995", 5},
+		{"concert:32-01-2019", 8},
+		{"album:1979..1970", 6},
+		{"artist:>queen", 7},
+		{"queen - floyd", 6},
+		{`""`, 0},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.input)
+		var syntaxErr *QuerySyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseQuery(%q): QuerySyntaxError attendue, got %v", tt.input, err)
+			continue
+		}
+		if syntaxErr.Pos != tt.pos {
+			t.Errorf("ParseQuery(%q): position %d, attendu %d (%s)", tt.input, syntaxErr.Pos, tt.pos, syntaxErr.Message)
+		}
+	}
+
+	_, err := ParseQuery("membre:john")
+	if syntaxErr := err.(*QuerySyntaxError); !reflect.DeepEqual(syntaxErr.Suggestions, []string{"member:"}) {
+		t.Errorf("Suggestions pour « membre »: %v", syntaxErr.Suggestions)
+	}
+}
+
+func TestCompleteQueryField(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"mem", []string{"member:"}},
+		{"queen c", []string{"location:", "country:", "year:", "concert:"}}, // city, created
+		{"-cou", []string{"country:"}},
+		{"na", []string{"artist:"}},
+		{"member:jo", nil},
+		{"queen ", nil},
+		{"", nil},
+		{"xyz", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := CompleteQueryField(tt.input); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("CompleteQueryField(%q) = %v, attendu %v", tt.input, got, tt.expected)
+		}
+	}
+
+	if got := ApplyQueryCompletion("queen -cou", "country:"); got != "queen -country:" {
+		t.Errorf("ApplyQueryCompletion = %q", got)
+	}
+}
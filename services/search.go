@@ -1,8 +1,12 @@
 package services
 
 import (
+	"context"
 	"groupie-tracker/models"
+	"sort"
 	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 // SearchType représente le type de résultat de recherche
@@ -13,6 +17,7 @@ const (
 	SearchTypeMember   SearchType = "member"
 	SearchTypeLocation SearchType = "location"
 	SearchTypeDate     SearchType = "date"
+	SearchTypeConcert  SearchType = "concert" // date de concert (voir Location)
 )
 
 // SearchResult représente un résultat de recherche avec son type
@@ -22,142 +27,164 @@ type SearchResult struct {
 	MatchedText string     // Texte qui a matché
 	Type        SearchType // Type de match
 	Score       int        // Score de pertinence (plus élevé = plus pertinent)
-	MatchStart  int        // Position de début du match (pour highlighting)
-	MatchEnd    int        // Position de fin du match
+	MatchStart  int        // Début du match dans MatchedText, en runes (pour highlighting)
+	MatchEnd    int        // Fin du match (exclue), en runes
+	Location    string     // Lieu du concert (SearchTypeConcert uniquement)
 }
 
 // SearchEngine gère la recherche dans les artistes
 type SearchEngine struct {
-	artists    []models.Artist
-	aggregates map[int]models.ArtistAggregate // Cache des données agrégées
+	store *ArtistStore // Artistes et données agrégées partagés
+
+	mu          sync.RWMutex
+	index       *searchIndex // index inversé, tenu à jour à chaque publication du store
+	unsubscribe func()
+	aliases     *AliasDictionary // alias de l'utilisateur (nil = aucun)
 }
 
 // NewSearchEngine crée une nouvelle instance du moteur de recherche
+// avec son propre store (source par défaut)
 func NewSearchEngine(artists []models.Artist) *SearchEngine {
-	return &SearchEngine{
-		artists:    artists,
-		aggregates: make(map[int]models.ArtistAggregate),
-	}
+	store := NewArtistStore(DefaultDataSource())
+	store.SetArtists(artists)
+	return NewSearchEngineWithStore(store)
 }
 
-// LoadAggregateData charge les données agrégées pour un artiste (lazy loading)
-func (se *SearchEngine) LoadAggregateData(artistID int) error {
-	// Si déjà en cache, ne rien faire
-	if _, exists := se.aggregates[artistID]; exists {
-		return nil
+// NewSearchEngineWithStore crée un moteur qui lit ses données dans un store partagé
+func NewSearchEngineWithStore(store *ArtistStore) *SearchEngine {
+	se := &SearchEngine{store: store}
+
+	// Abonnement avant la construction : aucune publication ne peut être manquée
+	se.mu.Lock()
+	se.unsubscribe = store.Subscribe(se.onStoreChange)
+	se.index = buildSearchIndex(store.State())
+	se.mu.Unlock()
+
+	return se
+}
+
+// Close désabonne le moteur du store (l'index n'est plus mis à jour)
+func (se *SearchEngine) Close() {
+	se.mu.Lock()
+	unsubscribe := se.unsubscribe
+	se.unsubscribe = nil
+	se.mu.Unlock()
+
+	if unsubscribe != nil {
+		unsubscribe()
 	}
+}
 
-	// Trouver l'artiste
-	var artist models.Artist
-	for _, a := range se.artists {
-		if a.ID == artistID {
-			artist = a
-			break
-		}
+// onStoreChange met l'index à jour depuis le dernier état publié : les
+// notifications concurrentes peuvent arriver dans le désordre, l'index
+// reflète toujours l'état le plus récent
+func (se *SearchEngine) onStoreChange(change StoreChange) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	state := se.store.State()
+	if change.Kind == StoreArtistsChanged || len(change.ArtistIDs) == 0 {
+		se.index = buildSearchIndex(state)
+		return
+	}
+	for _, id := range change.ArtistIDs {
+		se.index.reindexLocations(state, id)
 	}
+	if se.index.needsCompaction() {
+		se.index = buildSearchIndex(state)
+	}
+}
+
+// SetAliases branche le dictionnaire d'alias consulté par la recherche
+func (se *SearchEngine) SetAliases(aliases *AliasDictionary) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+	se.aliases = aliases
+}
 
-	// Charger les données agrégées
-	aggregate, err := AggregateArtist(artist)
-	if err != nil {
-		return err
+// AliasTarget retourne la cible de l'alias saisi ("rhcp" -> "Red Hot Chili Peppers")
+func (se *SearchEngine) AliasTarget(query string) (string, bool) {
+	se.mu.RLock()
+	aliases := se.aliases
+	se.mu.RUnlock()
+
+	if aliases == nil {
+		return "", false
 	}
+	return aliases.Lookup(query)
+}
 
-	se.aggregates[artistID] = aggregate
-	return nil
+// Store retourne le store utilisé par le moteur
+func (se *SearchEngine) Store() *ArtistStore {
+	return se.store
+}
+
+// SetDataSource change la source utilisée pour charger les données agrégées
+func (se *SearchEngine) SetDataSource(source DataSource) {
+	se.store.SetSource(source)
+}
+
+// LoadAggregateData charge les données agrégées pour un artiste (lazy loading)
+func (se *SearchEngine) LoadAggregateData(artistID int) error {
+	return se.LoadAggregateDataContext(context.Background(), artistID)
+}
+
+// LoadAggregateDataContext est la variante annulable de LoadAggregateData
+func (se *SearchEngine) LoadAggregateDataContext(ctx context.Context, artistID int) error {
+	_, err := se.store.Aggregate(ctx, artistID)
+	return err
+}
+
+// SetArtists remplace la liste d'artistes (actualisation des données)
+func (se *SearchEngine) SetArtists(artists []models.Artist) {
+	se.store.SetArtists(artists)
+}
+
+// AddAggregates enregistre des données agrégées déjà chargées (chargement groupé)
+func (se *SearchEngine) AddAggregates(aggregates map[int]models.ArtistAggregate) {
+	se.store.AddAggregates(aggregates)
+}
+
+// Concerts retourne le service de concerts partageant le store du moteur
+func (se *SearchEngine) Concerts() *ConcertService {
+	return NewConcertService(se.store)
+}
+
+// GetAggregate retourne les données agrégées en cache pour un artiste
+func (se *SearchEngine) GetAggregate(artistID int) (models.ArtistAggregate, bool) {
+	return se.store.CachedAggregate(artistID)
 }
 
-// Search effectue une recherche case-insensitive sur tous les champs avec scoring
+// Search cherche query sur tous les champs, sans tenir compte de la casse,
+// des accents ni de la ponctuation, et trie les résultats par score
 func (se *SearchEngine) Search(query string) []SearchResult {
-	if query == "" {
+	// Langage de requête (champ:valeur, "phrase", -exclusion) ; une saisie
+	// invalide est cherchée telle quelle
+	if parsed, err := ParseQuery(query); err == nil && parsed.Structured() {
+		return se.SearchQuery(parsed)
+	}
+
+	query = strings.TrimSpace(query)
+	folded := foldText(query)
+	if folded == "" {
 		return []SearchResult{}
 	}
 
-	query = strings.ToLower(strings.TrimSpace(query))
+	// Texte libre : seuls les documents de l'index contenant tous les jetons
+	// sont vérifiés, avec l'ordre et les scores d'un parcours complet.
+	// La forme souple ("acdc" -> "AC/DC", "and" -> "&") sert de repli.
 	results := []SearchResult{}
 	seen := make(map[string]bool) // Pour éviter les doublons
+	results = se.appendMatches(results, seen, folded, looseText(query))
 
-	for _, artist := range se.artists {
-		// Recherche dans le nom de l'artiste
-		if matchPos := strings.Index(strings.ToLower(artist.Name), query); matchPos != -1 {
-			key := artist.Name + "-artist"
-			if !seen[key] {
-				score := se.calculateScore(artist.Name, query, matchPos, SearchTypeArtist)
-				results = append(results, SearchResult{
-					ArtistID:    artist.ID,
-					ArtistName:  artist.Name,
-					MatchedText: artist.Name,
-					Type:        SearchTypeArtist,
-					Score:       score,
-					MatchStart:  matchPos,
-					MatchEnd:    matchPos + len(query),
-				})
-				seen[key] = true
-			}
-		}
-
-		// Recherche dans les membres
-		for _, member := range artist.Members {
-			if matchPos := strings.Index(strings.ToLower(member), query); matchPos != -1 {
-				key := artist.Name + "-" + member
-				if !seen[key] {
-					score := se.calculateScore(member, query, matchPos, SearchTypeMember)
-					results = append(results, SearchResult{
-						ArtistID:    artist.ID,
-						ArtistName:  artist.Name,
-						MatchedText: member,
-						Type:        SearchTypeMember,
-						Score:       score,
-						MatchStart:  matchPos,
-						MatchEnd:    matchPos + len(query),
-					})
-					seen[key] = true
-				}
-			}
-		}
-
-		// Recherche dans la date du premier album
-		if matchPos := strings.Index(strings.ToLower(artist.FirstAlbum), query); matchPos != -1 {
-			key := artist.Name + "-" + artist.FirstAlbum
-			if !seen[key] {
-				score := se.calculateScore(artist.FirstAlbum, query, matchPos, SearchTypeDate)
-				results = append(results, SearchResult{
-					ArtistID:    artist.ID,
-					ArtistName:  artist.Name,
-					MatchedText: artist.FirstAlbum,
-					Type:        SearchTypeDate,
-					Score:       score,
-					MatchStart:  matchPos,
-					MatchEnd:    matchPos + len(query),
-				})
-				seen[key] = true
-			}
-		}
+	// Alias de l'utilisateur : "rhcp" cherche aussi "Red Hot Chili Peppers"
+	if target, ok := se.AliasTarget(query); ok {
+		results = se.appendMatches(results, seen, foldText(target), looseText(target))
+	}
 
-		// Recherche dans les locations (nécessite le chargement des données)
-		if aggregate, exists := se.aggregates[artist.ID]; exists {
-			for _, location := range aggregate.Locations.Locations {
-				city, country := ParseLocation(location)
-				fullLocation := city + " " + country
-
-				if matchPos := strings.Index(strings.ToLower(fullLocation), query); matchPos != -1 {
-					key := artist.Name + "-" + location
-					if !seen[key] {
-						displayLocation := city + ", " + strings.ToUpper(country)
-						score := se.calculateScore(fullLocation, query, matchPos, SearchTypeLocation)
-						results = append(results, SearchResult{
-							ArtistID:    artist.ID,
-							ArtistName:  artist.Name,
-							MatchedText: displayLocation,
-							Type:        SearchTypeLocation,
-							Score:       score,
-							MatchStart:  matchPos,
-							MatchEnd:    matchPos + len(query),
-						})
-						seen[key] = true
-					}
-				}
-			}
-		}
+	// Dates de concert : "2019", "12/2019", "juillet 2019", "2018..2020"...
+	if dateQuery, ok := ParseDateQuery(folded); ok {
+		results = append(results, se.searchConcerts(dateQuery)...)
 	}
 
 	// Trier par score (plus pertinent en premier)
@@ -166,15 +193,72 @@ func (se *SearchEngine) Search(query string) []SearchResult {
 	return results
 }
 
-// calculateScore calcule un score de pertinence pour un match
+// appendMatches ajoute les documents contenant query (repliée) ou, à défaut,
+// sa forme souple loose
+func (se *SearchEngine) appendMatches(results []SearchResult, seen map[string]bool, query, loose string) []SearchResult {
+	queries := []string{query}
+	if loose != "" && loose != query {
+		queries = append(queries, loose)
+	}
+
+	// Les documents sont immuables : ils restent valides une fois le verrou
+	// relâché, même si une publication remplace l'index entre-temps
+	se.mu.RLock()
+	candidates := se.index.candidates(queries...)
+	se.mu.RUnlock()
+
+	for _, doc := range candidates {
+		matchPos, matchEnd := doc.match(query, loose)
+		if matchPos == -1 || seen[doc.key] {
+			continue
+		}
+
+		// Texte réellement couvert (le nom du pays pour un alias de pays)
+		matched := runeSlice(doc.text, matchPos, matchEnd)
+
+		results = append(results, SearchResult{
+			ArtistID:    doc.artistID,
+			ArtistName:  doc.artistName,
+			MatchedText: doc.text,
+			Type:        doc.typ,
+			Score:       se.calculateScore(doc.text, matched, matchPos, doc.typ),
+			MatchStart:  matchPos,
+			MatchEnd:    matchEnd,
+		})
+		seen[doc.key] = true
+	}
+	return results
+}
+
+// matchPlace cherche query (repliée) dans le nom affiché d'un lieu. Si la
+// requête est un jeton de pays ("usa", "uk", "GB"...), c'est le nom du pays
+// qui est surligné. Les positions sont en runes ; (-1, -1) si le lieu ne
+// correspond pas.
+func matchPlace(place models.Place, display, query string) (int, int) {
+	if matchPos, matchEnd := foldIndex(display, query); matchPos != -1 {
+		return matchPos, matchEnd
+	}
+
+	country, ok := LookupCountry(query)
+	if !ok || country.Code != place.Country.Code {
+		return -1, -1
+	}
+	if matchPos := strings.LastIndex(display, country.Name); matchPos != -1 {
+		start := runeIndex(display, matchPos)
+		return start, start + utf8.RuneCountInString(country.Name)
+	}
+	return -1, -1
+}
+
+// calculateScore calcule un score de pertinence pour un match (matchPos en
+// runes de text ; longueurs comptées en runes)
 func (se *SearchEngine) calculateScore(text, query string, matchPos int, searchType SearchType) int {
 	score := 100
 
-	textLower := strings.ToLower(text)
-	queryLower := strings.ToLower(query)
+	runes := []rune(text)
 
 	// Match exact = score maximum
-	if textLower == queryLower {
+	if foldText(text) == foldText(query) {
 		score += 1000
 	}
 
@@ -184,12 +268,12 @@ func (se *SearchEngine) calculateScore(text, query string, matchPos int, searchT
 	}
 
 	// Match après un espace = bonus (début de mot)
-	if matchPos > 0 && text[matchPos-1] == ' ' {
+	if matchPos > 0 && matchPos <= len(runes) && runes[matchPos-1] == ' ' {
 		score += 300
 	}
 
 	// Plus le texte est court, plus il est pertinent
-	score += (100 - len(text))
+	score += (100 - len(runes))
 
 	// Bonus selon le type
 	switch searchType {
@@ -199,28 +283,27 @@ func (se *SearchEngine) calculateScore(text, query string, matchPos int, searchT
 		score += 300
 	case SearchTypeLocation:
 		score += 200
+	case SearchTypeConcert:
+		score += 150
 	case SearchTypeDate:
 		score += 100
 	}
 
 	// Bonus si la query couvre une grande partie du texte
-	coveragePercent := (len(query) * 100) / len(text)
-	score += coveragePercent * 2
+	if len(runes) > 0 {
+		coveragePercent := (utf8.RuneCountInString(query) * 100) / len(runes)
+		score += coveragePercent * 2
+	}
 
 	return score
 }
 
-// sortByScore trie les résultats par score décroissant
+// sortByScore trie les résultats par score décroissant (tri stable : à score
+// égal, l'ordre de parcours est conservé)
 func (se *SearchEngine) sortByScore(results []SearchResult) []SearchResult {
-	// Bubble sort simple (suffisant pour un petit nombre de résultats)
-	n := len(results)
-	for i := 0; i < n-1; i++ {
-		for j := 0; j < n-i-1; j++ {
-			if results[j].Score < results[j+1].Score {
-				results[j], results[j+1] = results[j+1], results[j]
-			}
-		}
-	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
 	return results
 }
 
@@ -272,17 +355,19 @@ func (se *SearchEngine) SearchWithMinScore(query string, minScore int) []SearchR
 	return filtered
 }
 
-// HighlightMatch retourne le texte avec le match mis en évidence
+// HighlightMatch retourne le texte avec le match mis en évidence (découpage
+// en runes : un caractère accentué n'est jamais coupé)
 func (se *SearchEngine) HighlightMatch(result SearchResult) (before, match, after string) {
 	text := result.MatchedText
-	
-	if result.MatchStart < 0 || result.MatchEnd > len(text) {
+	runes := []rune(text)
+
+	if result.MatchStart < 0 || result.MatchEnd > len(runes) || result.MatchStart > result.MatchEnd {
 		return text, "", ""
 	}
 
-	before = text[:result.MatchStart]
-	match = text[result.MatchStart:result.MatchEnd]
-	after = text[result.MatchEnd:]
+	before = string(runes[:result.MatchStart])
+	match = string(runes[result.MatchStart:result.MatchEnd])
+	after = string(runes[result.MatchEnd:])
 
 	return before, match, after
-}
\ No newline at end of file
+}
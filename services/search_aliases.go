@@ -0,0 +1,163 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultAliases pré-remplit le dictionnaire tant que l'utilisateur n'a pas
+// enregistré le sien
+var defaultAliases = map[string]string{
+	"rhcp":       "Red Hot Chili Peppers",
+	"gnr":        "Guns N' Roses",
+	"fab four":   "The Beatles",
+	"the boss":   "Bruce Springsteen",
+	"qotsa":      "Queens of the Stone Age",
+	"the stones": "The Rolling Stones",
+}
+
+// SearchAlias associe un surnom à un nom d'artiste, de membre ou de lieu
+type SearchAlias struct {
+	Alias  string `json:"alias"`
+	Target string `json:"target"`
+}
+
+// AliasDictionary est le dictionnaire d'alias de recherche modifiable par
+// l'utilisateur ("rhcp" -> "Red Hot Chili Peppers"). Les alias sont comparés
+// sous forme souple et sans espaces : "Fab-Four" et "fab four" sont le même
+// alias.
+type AliasDictionary struct {
+	mu       sync.RWMutex
+	aliases  map[string]SearchAlias // aliasKey -> alias
+	filePath string
+}
+
+// DefaultAliasPath retourne l'emplacement du dictionnaire (à côté de l'historique)
+func DefaultAliasPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".groupie-tracker", "search_aliases.json")
+}
+
+// NewAliasDictionary charge le dictionnaire de l'utilisateur
+func NewAliasDictionary() *AliasDictionary {
+	return NewAliasDictionaryAt(DefaultAliasPath())
+}
+
+// NewAliasDictionaryAt charge le dictionnaire stocké dans path ; sans fichier,
+// les alias par défaut sont utilisés
+func NewAliasDictionaryAt(path string) *AliasDictionary {
+	d := &AliasDictionary{
+		aliases:  make(map[string]SearchAlias),
+		filePath: path,
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		for alias, target := range defaultAliases {
+			d.aliases[aliasKey(alias)] = SearchAlias{Alias: alias, Target: target}
+		}
+	} else {
+		d.Load()
+	}
+
+	return d
+}
+
+// aliasKey est la clé de comparaison d'un alias : forme souple sans espaces
+func aliasKey(alias string) string {
+	return strings.ReplaceAll(looseText(alias), " ", "")
+}
+
+// Lookup retourne la cible d'un alias (saisie comparée sous forme souple)
+func (d *AliasDictionary) Lookup(query string) (string, bool) {
+	key := aliasKey(query)
+	if key == "" {
+		return "", false
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	alias, ok := d.aliases[key]
+	return alias.Target, ok
+}
+
+// Set ajoute ou remplace un alias et sauvegarde le dictionnaire
+func (d *AliasDictionary) Set(alias, target string) error {
+	alias, target = strings.TrimSpace(alias), strings.TrimSpace(target)
+	key := aliasKey(alias)
+	if key == "" || target == "" {
+		return nil
+	}
+
+	d.mu.Lock()
+	d.aliases[key] = SearchAlias{Alias: alias, Target: target}
+	d.mu.Unlock()
+
+	return d.Save()
+}
+
+// Remove supprime un alias et sauvegarde le dictionnaire
+func (d *AliasDictionary) Remove(alias string) error {
+	d.mu.Lock()
+	delete(d.aliases, aliasKey(alias))
+	d.mu.Unlock()
+
+	return d.Save()
+}
+
+// All retourne les alias triés par ordre alphabétique
+func (d *AliasDictionary) All() []SearchAlias {
+	d.mu.RLock()
+	aliases := make([]SearchAlias, 0, len(d.aliases))
+	for _, alias := range d.aliases {
+		aliases = append(aliases, alias)
+	}
+	d.mu.RUnlock()
+
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Alias < aliases[j].Alias
+	})
+	return aliases
+}
+
+// Save sauvegarde le dictionnaire sur disque (écriture atomique)
+func (d *AliasDictionary) Save() error {
+	if err := os.MkdirAll(filepath.Dir(d.filePath), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(d.All(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(d.filePath, data)
+}
+
+// Load charge le dictionnaire depuis le disque
+func (d *AliasDictionary) Load() error {
+	data, err := os.ReadFile(d.filePath)
+	if os.IsNotExist(err) {
+		return nil // Pas d'erreur, juste pas de dictionnaire
+	}
+	if err != nil {
+		return err
+	}
+
+	var aliases []SearchAlias
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, alias := range aliases {
+		if key := aliasKey(alias.Alias); key != "" && alias.Target != "" {
+			d.aliases[key] = alias
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"groupie-tracker/models"
+)
+
+func TestAliasDictionary_DefaultsAndPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search_aliases.json")
+
+	// Sans fichier : alias par défaut
+	aliases := NewAliasDictionaryAt(path)
+	if target, ok := aliases.Lookup("RHCP"); !ok || target != "Red Hot Chili Peppers" {
+		t.Errorf("alias par défaut attendu, got %q %v", target, ok)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("les alias par défaut ne doivent pas être écrits sans modification")
+	}
+
+	// Modifications sauvegardées à côté de l'historique
+	if err := aliases.Set("Freddie", "Freddie Mercury"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := aliases.Remove("rhcp"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	reloaded := NewAliasDictionaryAt(path)
+	if target, ok := reloaded.Lookup("freddie"); !ok || target != "Freddie Mercury" {
+		t.Errorf("alias ajouté perdu au rechargement: %q %v", target, ok)
+	}
+	if _, ok := reloaded.Lookup("rhcp"); ok {
+		t.Error("alias supprimé retrouvé au rechargement")
+	}
+	if _, ok := reloaded.Lookup("gnr"); !ok {
+		t.Error("les autres alias par défaut doivent être conservés")
+	}
+}
+
+func TestAliasDictionary_LooseKeys(t *testing.T) {
+	aliases := NewAliasDictionaryAt(filepath.Join(t.TempDir(), "aliases.json"))
+
+	for _, query := range []string{"fab four", "Fab-Four", "  FAB FOUR "} {
+		if target, ok := aliases.Lookup(query); !ok || target != "The Beatles" {
+			t.Errorf("Lookup(%q) = %q %v, attendu The Beatles", query, target, ok)
+		}
+	}
+	if _, ok := aliases.Lookup("..."); ok {
+		t.Error("une saisie sans lettres n'est pas un alias")
+	}
+}
+
+func newAliasTestEngine(t *testing.T) *SearchEngine {
+	t.Helper()
+	engine := newPipelineTestEngine(t,
+		models.Artist{ID: 4, Name: "AC/DC", Members: []string{"Angus Young"}},
+		models.Artist{ID: 5, Name: "Guns N' Roses", Members: []string{"Axl Rose", "Slash"}},
+		models.Artist{ID: 6, Name: "Red Hot Chili Peppers", Members: []string{"Anthony Kiedis", "Flea"}},
+		models.Artist{ID: 7, Name: "Simon & Garfunkel", Members: []string{"Paul Simon", "Art Garfunkel"}},
+	)
+	engine.SetAliases(NewAliasDictionaryAt(filepath.Join(t.TempDir(), "aliases.json")))
+	return engine
+}
+
+func TestSearchEngine_PunctuationInsensitive(t *testing.T) {
+	engine := newAliasTestEngine(t)
+
+	tests := []struct {
+		query, expected, highlight string
+	}{
+		{"acdc", "AC/DC", "AC/DC"},
+		{"ac-dc", "AC/DC", "AC/DC"},
+		{"guns and roses", "Guns N' Roses", "Guns N' Roses"},
+		{"guns & roses", "Guns N' Roses", "Guns N' Roses"},
+		{"simon and garfunkel", "Simon & Garfunkel", "Simon & Garfunkel"},
+		{"simon et garfunkel", "Simon & Garfunkel", "Simon & Garfunkel"},
+	}
+
+	for _, tt := range tests {
+		results := engine.Search(tt.query)
+		if len(results) == 0 || results[0].MatchedText != tt.expected {
+			t.Errorf("Search(%q): %q attendu en premier, got %+v", tt.query, tt.expected, results)
+			continue
+		}
+		if _, match, _ := engine.HighlightMatch(results[0]); match != tt.highlight {
+			t.Errorf("Search(%q): surlignage %q, attendu %q", tt.query, match, tt.highlight)
+		}
+	}
+}
+
+func TestSearchEngine_Aliases(t *testing.T) {
+	engine := newAliasTestEngine(t)
+
+	results := engine.Search("rhcp")
+	if len(results) == 0 || results[0].MatchedText != "Red Hot Chili Peppers" {
+		t.Fatalf("l'alias rhcp devrait trouver Red Hot Chili Peppers, got %+v", results)
+	}
+
+	// Alias ajouté par l'utilisateur
+	if err := engine.aliases.Set("slash", "Guns N' Roses"); err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, r := range engine.Search("slash") {
+		found[r.MatchedText] = true
+	}
+	if !found["Slash"] || !found["Guns N' Roses"] {
+		t.Errorf("le membre et la cible de l'alias sont attendus, got %v", found)
+	}
+
+	// Sans dictionnaire : aucun alias
+	engine.SetAliases(nil)
+	if results := engine.Search("rhcp"); len(results) != 0 {
+		t.Errorf("aucun résultat attendu sans dictionnaire, got %+v", results)
+	}
+}
+
+func TestInitialsSearch_PunctuationAndAliases(t *testing.T) {
+	engine := newAliasTestEngine(t)
+	initials := NewInitialsSearchEngine(engine)
+
+	tests := []struct {
+		query, expected string
+	}{
+		{"gnr", "Guns N' Roses"},
+		{"rhcp", "Red Hot Chili Peppers"},
+		{"s&g", "Simon & Garfunkel"},
+		{"sag", "Simon & Garfunkel"},
+		{"fab four", "The Beatles"}, // alias
+	}
+
+	for _, tt := range tests {
+		results := initials.SearchByInitials(tt.query)
+		if len(results) == 0 || results[0].MatchedText != tt.expected {
+			t.Errorf("SearchByInitials(%q): %q attendu, got %+v", tt.query, tt.expected, results)
+		}
+	}
+}
+
+func TestSearchPipeline_ExplainsAliases(t *testing.T) {
+	pipeline := NewSearchPipeline(newAliasTestEngine(t))
+
+	results := pipeline.Search("fab four", 5)
+	if len(results) == 0 || results[0].MatchedText != "The Beatles" {
+		t.Fatalf("The Beatles attendu, got %+v", results)
+	}
+	if explanation := results[0].Explain(); explanation != "alias « fab four » de « The Beatles »" {
+		t.Errorf("explication inattendue: %q", explanation)
+	}
+}
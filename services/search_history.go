@@ -1,6 +1,7 @@
 package services
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -16,21 +17,33 @@ type SearchHistoryEntry struct {
 
 // SearchHistory gère l'historique des recherches
 type SearchHistory struct {
-	entries  []SearchHistoryEntry
-	maxSize  int
-	filePath string
+	entries      []SearchHistoryEntry
+	personalized bool // classement personnalisé par les sélections (voir ClickModel)
+	maxSize      int
+	filePath     string
+}
+
+// searchHistoryFile est le contenu du fichier d'historique. Les anciennes
+// versions n'enregistraient que la liste des entrées.
+type searchHistoryFile struct {
+	Personalized bool                 `json:"personalized"`
+	Entries      []SearchHistoryEntry `json:"entries"`
 }
 
 // NewSearchHistory crée un nouvel historique de recherche
 func NewSearchHistory(maxSize int) *SearchHistory {
 	// Déterminer le chemin du fichier d'historique
 	homeDir, _ := os.UserHomeDir()
-	filePath := filepath.Join(homeDir, ".groupie-tracker", "search_history.json")
+	return NewSearchHistoryAt(filepath.Join(homeDir, ".groupie-tracker", "search_history.json"), maxSize)
+}
 
+// NewSearchHistoryAt crée un historique enregistré dans le fichier path
+func NewSearchHistoryAt(path string, maxSize int) *SearchHistory {
 	sh := &SearchHistory{
-		entries:  []SearchHistoryEntry{},
-		maxSize:  maxSize,
-		filePath: filePath,
+		entries:      []SearchHistoryEntry{},
+		personalized: true,
+		maxSize:      maxSize,
+		filePath:     path,
 	}
 
 	// Charger l'historique existant
@@ -128,7 +141,19 @@ func (sh *SearchHistory) GetMostFrequent(n int) []string {
 	return result
 }
 
-// Clear efface tout l'historique
+// Personalized indique si le classement personnalisé est activé
+func (sh *SearchHistory) Personalized() bool {
+	return sh.personalized
+}
+
+// SetPersonalized active ou désactive le classement personnalisé et
+// enregistre ce choix avec l'historique
+func (sh *SearchHistory) SetPersonalized(enabled bool) {
+	sh.personalized = enabled
+	sh.Save()
+}
+
+// Clear efface tout l'historique (le choix de personnalisation est conservé)
 func (sh *SearchHistory) Clear() {
 	sh.entries = []SearchHistoryEntry{}
 	sh.Save()
@@ -143,7 +168,10 @@ func (sh *SearchHistory) Save() error {
 	}
 
 	// Encoder en JSON
-	data, err := json.MarshalIndent(sh.entries, "", "  ")
+	data, err := json.MarshalIndent(searchHistoryFile{
+		Personalized: sh.personalized,
+		Entries:      sh.entries,
+	}, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -165,8 +193,21 @@ func (sh *SearchHistory) Load() error {
 		return err
 	}
 
+	// Ancien format : la liste des entrées seule
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(data, &sh.entries)
+	}
+
 	// Décoder le JSON
-	return json.Unmarshal(data, &sh.entries)
+	file := searchHistoryFile{Personalized: true}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	sh.personalized = file.Personalized
+	if file.Entries != nil {
+		sh.entries = file.Entries
+	}
+	return nil
 }
 
 // GetSuggestions retourne des suggestions basées sur l'historique
@@ -0,0 +1,518 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"groupie-tracker/models"
+)
+
+// =====================
+// INDEX INVERSÉ DE RECHERCHE
+// =====================
+//
+// Chaque champ cherchable (nom, membre, premier album, lieu) devient un
+// document. Les textes sont découpés en jetons (séparés par des espaces) et
+// tous les suffixes de chaque jeton sont indexés : un jeton de la requête
+// étant forcément contenu dans un jeton du texte, une recherche par préfixe
+// parmi les suffixes triés donne exactement les documents candidats, que le
+// moteur vérifie ensuite avec la même logique que le parcours linéaire.
+
+// indexedDoc est un champ cherchable d'un artiste
+type indexedDoc struct {
+	artistID   int
+	artistName string
+	pos        int // position de l'artiste dans la liste
+	seq        int // rang du champ dans l'artiste (ordre du parcours linéaire)
+	typ        SearchType
+	text       string       // texte affiché (MatchedText)
+	folded     foldedText   // texte replié (casse, accents) et positions d'origine
+	loose      foldedText   // forme souple : sans ponctuation, "&" lu "and"
+	key        string       // clé de dédoublonnage
+	place      models.Place // lieu analysé (SearchTypeLocation uniquement)
+	words      int          // mots codés phonétiquement (nom, membre ou ville)
+}
+
+// match retourne la position du match de query (déjà repliée) dans le
+// document, en runes du texte affiché, ou (-1, -1). À défaut, la forme souple
+// de la requête (loose) est cherchée dans celle du texte : "acdc" -> "AC/DC".
+func (d *indexedDoc) match(query, loose string) (int, int) {
+	start, end := -1, -1
+	if d.typ == SearchTypeLocation {
+		start, end = matchPlace(d.place, d.text, query)
+	} else {
+		start, end = d.folded.index(query)
+	}
+	if start == -1 && loose != "" {
+		return d.loose.index(loose)
+	}
+	return start, end
+}
+
+// maxDeadDocShare est la part de documents retirés au-delà de laquelle
+// l'index est reconstruit
+const maxDeadDocShare = 0.25
+
+// searchIndex est l'index inversé d'un état du store. Il n'est pas protégé :
+// le SearchEngine le modifie sous verrou d'écriture et le lit sous verrou de
+// lecture.
+type searchIndex struct {
+	docs      []*indexedDoc       // docID -> document (nil = retiré)
+	dead      int                 // documents retirés, encore référencés
+	artists   []models.Artist     // liste indexée (ordre de parcours)
+	positions map[int][]int       // ID d'artiste -> positions dans la liste
+	locations map[int][]int       // position -> docIDs de ses lieux
+	postings  map[string][]int    // suffixe de jeton -> docIDs
+	suffixes  []string            // clés de postings, triées
+	countries map[string][]int    // code ISO -> docIDs de lieux (alias de pays)
+	byLength  map[int][]int       // longueur repliée (en runes) -> docIDs noms/membres
+	phonetic  map[string][]int    // clé phonétique d'un mot -> docIDs noms/membres/villes
+	sounds    map[string][]string // mot replié -> clés phonétiques (les noms se répètent)
+
+	vocabulary map[string]*vocabularyWord // mot souple -> mot des noms, membres et villes
+	wordsByLen map[int][]string           // longueur (en runes) -> mots du vocabulaire
+}
+
+// vocabularyWord est un mot du vocabulaire proposé par la correction
+// orthographique
+type vocabularyWord struct {
+	display string // mot tel qu'affiché, en minuscules ("motörhead")
+	count   int    // nombre de documents qui le contiennent
+	docs    []int  // docIDs (les documents retirés sont ignorés à la lecture)
+}
+
+// buildSearchIndex indexe tous les artistes et les lieux déjà chargés d'un état
+func buildSearchIndex(state *StoreState) *searchIndex {
+	idx := &searchIndex{
+		artists:   state.Artists,
+		positions: make(map[int][]int),
+		locations: make(map[int][]int),
+		postings:  make(map[string][]int),
+		countries: make(map[string][]int),
+		byLength:  make(map[int][]int),
+		phonetic:  make(map[string][]int),
+		sounds:    make(map[string][]string),
+
+		vocabulary: make(map[string]*vocabularyWord),
+		wordsByLen: make(map[int][]string),
+	}
+
+	newSuffixes := []string{}
+	for pos, artist := range state.Artists {
+		idx.positions[artist.ID] = append(idx.positions[artist.ID], pos)
+
+		base := indexedDoc{artistID: artist.ID, artistName: artist.Name, pos: pos}
+
+		name := base
+		name.typ, name.text, name.key = SearchTypeArtist, artist.Name, artist.Name+"-artist"
+		newSuffixes = idx.add(&name, newSuffixes)
+
+		for i, member := range artist.Members {
+			doc := base
+			doc.seq, doc.typ, doc.text, doc.key = 1+i, SearchTypeMember, member, artist.Name+"-"+member
+			newSuffixes = idx.add(&doc, newSuffixes)
+		}
+
+		firstAlbum := artist.FirstAlbum.String()
+		album := base
+		album.seq, album.typ, album.text, album.key = 1+len(artist.Members), SearchTypeDate, firstAlbum, artist.Name+"-"+firstAlbum
+		newSuffixes = idx.add(&album, newSuffixes)
+
+		if aggregate, exists := state.Aggregate(artist.ID); exists {
+			newSuffixes = idx.addLocations(pos, aggregate, newSuffixes)
+		}
+	}
+
+	idx.mergeSuffixes(newSuffixes)
+	return idx
+}
+
+// reindexLocations remplace les lieux indexés d'un artiste par ceux de l'état
+func (idx *searchIndex) reindexLocations(state *StoreState, artistID int) {
+	aggregate, exists := state.Aggregate(artistID)
+
+	newSuffixes := []string{}
+	for _, pos := range idx.positions[artistID] {
+		for _, docID := range idx.locations[pos] {
+			idx.removeVocabulary(docID, idx.docs[docID].place.City)
+			idx.docs[docID] = nil // les postings obsolètes sont ignorés à la lecture
+			idx.dead++
+		}
+		delete(idx.locations, pos)
+
+		if exists {
+			newSuffixes = idx.addLocations(pos, aggregate, newSuffixes)
+		}
+	}
+	idx.mergeSuffixes(newSuffixes)
+}
+
+// needsCompaction indique si les documents retirés dépassent maxDeadDocShare
+// de l'index : il vaut alors mieux le reconstruire que de garder leurs
+// postings, suffixes et mots
+func (idx *searchIndex) needsCompaction() bool {
+	return float64(idx.dead) > maxDeadDocShare*float64(len(idx.docs))
+}
+
+// addLocations indexe les lieux d'un agrégat pour l'artiste à la position pos
+func (idx *searchIndex) addLocations(pos int, aggregate models.ArtistAggregate, newSuffixes []string) []string {
+	artist := idx.artists[pos]
+	for i, location := range aggregate.Locations.Locations {
+		place := ParsePlace(location)
+		doc := &indexedDoc{
+			artistID:   artist.ID,
+			artistName: artist.Name,
+			pos:        pos,
+			seq:        2 + len(artist.Members) + i,
+			typ:        SearchTypeLocation,
+			text:       place.String(),
+			key:        artist.Name + "-" + location,
+			place:      place,
+		}
+		newSuffixes = idx.add(doc, newSuffixes)
+
+		docID := len(idx.docs) - 1
+		idx.locations[pos] = append(idx.locations[pos], docID)
+		if place.Country.Code != "" {
+			idx.countries[place.Country.Code] = append(idx.countries[place.Country.Code], docID)
+		}
+	}
+	return newSuffixes
+}
+
+// add enregistre un document et ses suffixes. Les suffixes jamais vus sont
+// ajoutés à newSuffixes (fusionnés ensuite dans la liste triée).
+func (idx *searchIndex) add(doc *indexedDoc, newSuffixes []string) []string {
+	doc.folded = foldWithOffsets(doc.text)
+	doc.loose = looseWithOffsets(doc.text)
+	docID := len(idx.docs)
+	idx.docs = append(idx.docs, doc)
+
+	if doc.typ == SearchTypeArtist || doc.typ == SearchTypeMember {
+		length := utf8.RuneCountInString(doc.folded.text)
+		idx.byLength[length] = append(idx.byLength[length], docID)
+		idx.addPhonetic(docID, doc.folded.text)
+		idx.addVocabulary(docID, doc.text)
+	}
+	if doc.typ == SearchTypeLocation {
+		idx.addPhonetic(docID, foldText(doc.place.City))
+		idx.addVocabulary(docID, doc.place.City)
+	}
+
+	tokens := strings.Fields(doc.folded.text)
+	if doc.loose.text != doc.folded.text {
+		tokens = append(tokens, strings.Fields(doc.loose.text)...)
+	}
+	for _, token := range tokens {
+		for i := 0; i < len(token); i++ {
+			if !utf8.RuneStart(token[i]) {
+				continue
+			}
+			suffix := token[i:]
+			postings, known := idx.postings[suffix]
+			if !known {
+				newSuffixes = append(newSuffixes, suffix)
+			}
+			// Un même jeton peut apparaître deux fois dans un texte
+			if n := len(postings); n == 0 || postings[n-1] != docID {
+				idx.postings[suffix] = append(postings, docID)
+			}
+		}
+	}
+	return newSuffixes
+}
+
+// addPhonetic indexe les clés phonétiques de chaque mot de text
+func (idx *searchIndex) addPhonetic(docID int, text string) {
+	for _, word := range strings.Fields(text) {
+		keys, known := idx.sounds[word]
+		if !known {
+			keys = append(phoneticKeys(word, PhoneticMetaphone), phoneticKeys(word, PhoneticFrench)...)
+			idx.sounds[word] = keys
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		idx.docs[docID].words++
+		for _, key := range keys {
+			postings := idx.phonetic[key]
+			if n := len(postings); n == 0 || postings[n-1] != docID {
+				idx.phonetic[key] = append(postings, docID)
+			}
+		}
+	}
+}
+
+// phoneticCandidates retourne les documents dont les mots couvrent tous les
+// mots de la requête (déjà repliée) selon l'algorithme donné
+func (idx *searchIndex) phoneticCandidates(query string, algorithm PhoneticAlgorithm) []*indexedDoc {
+	var ids map[int]bool
+	for _, word := range strings.Fields(query) {
+		keys := phoneticKeys(word, algorithm)
+		if len(keys) == 0 {
+			continue // ponctuation, chiffres : pas de son
+		}
+		wordIDs := make(map[int]bool)
+		for _, key := range keys {
+			for _, docID := range idx.phonetic[key] {
+				if ids == nil || ids[docID] {
+					wordIDs[docID] = true
+				}
+			}
+		}
+		ids = wordIDs
+		if len(ids) == 0 {
+			break
+		}
+	}
+	return idx.ordered(ids)
+}
+
+// addVocabulary ajoute les mots de text (document docID) au vocabulaire
+func (idx *searchIndex) addVocabulary(docID int, text string) {
+	for _, word := range strings.Fields(text) {
+		key := looseText(word)
+		if key == "" {
+			continue
+		}
+		entry, known := idx.vocabulary[key]
+		if !known {
+			display := strings.ToLower(word)
+			if conjunctions[key] {
+				display = key // "N'", "&"
+			}
+			entry = &vocabularyWord{display: display}
+			idx.vocabulary[key] = entry
+			length := utf8.RuneCountInString(key)
+			idx.wordsByLen[length] = append(idx.wordsByLen[length], key)
+		}
+		// Un même mot peut apparaître deux fois dans un texte
+		if n := len(entry.docs); n == 0 || entry.docs[n-1] != docID {
+			entry.docs = append(entry.docs, docID)
+			entry.count++
+		}
+	}
+}
+
+// removeVocabulary décompte les mots d'un document retiré. Un mot retiré
+// partout reste connu avec un compte nul.
+func (idx *searchIndex) removeVocabulary(docID int, text string) {
+	removed := make(map[string]bool)
+	for _, word := range strings.Fields(text) {
+		key := looseText(word)
+		if entry, known := idx.vocabulary[key]; known && !removed[key] {
+			entry.count--
+			removed[key] = true
+		}
+	}
+}
+
+// knownWord indique si word (forme souple) apparaît dans le vocabulaire
+func (idx *searchIndex) knownWord(word string) bool {
+	entry, known := idx.vocabulary[word]
+	return known && entry.count > 0
+}
+
+// vocabularyMatch est un mot du vocabulaire proche d'un mot cherché
+type vocabularyMatch struct {
+	key      string
+	entry    *vocabularyWord
+	distance int
+}
+
+// similarWords retourne les mots du vocabulaire à distance de Levenshtein au
+// plus maxDistance de word (forme souple). Seuls les mots de longueur proche
+// sont comparés, et chaque comparaison s'arrête dès que maxDistance est
+// dépassée.
+func (idx *searchIndex) similarWords(word string, maxDistance int) []vocabularyMatch {
+	matches := []vocabularyMatch{}
+	target := []rune(word)
+	for length := len(target) - maxDistance; length <= len(target)+maxDistance; length++ {
+		for _, key := range idx.wordsByLen[length] {
+			entry := idx.vocabulary[key]
+			if entry.count <= 0 {
+				continue
+			}
+			if distance := boundedLevenshtein(target, []rune(key), maxDistance); distance <= maxDistance {
+				matches = append(matches, vocabularyMatch{key: key, entry: entry, distance: distance})
+			}
+		}
+	}
+	return matches
+}
+
+// closestWords retourne au plus limit mots du vocabulaire (autres que word) à
+// distance au plus maxDistance de word, du plus proche au plus éloigné puis
+// du plus fréquent au plus rare
+func (idx *searchIndex) closestWords(word string, maxDistance, limit int) []wordCorrection {
+	matches := []wordCorrection{}
+	for _, similar := range idx.similarWords(word, maxDistance) {
+		if similar.key != word {
+			matches = append(matches, wordCorrection{
+				word:     similar.entry.display,
+				distance: similar.distance,
+				count:    similar.entry.count,
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		if matches[i].count != matches[j].count {
+			return matches[i].count > matches[j].count
+		}
+		return matches[i].word < matches[j].word
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// tokenMatch est la correspondance mot à mot d'une requête avec un document
+type tokenMatch struct {
+	distance int      // somme des distances de chaque mot de la requête
+	words    []string // mots du document reconnus (forme souple)
+}
+
+// fuzzyTokenCandidates retourne les noms, membres et lieux (par leur ville)
+// dont les mots couvrent tous les mots de query (forme souple), chaque mot
+// avec au plus tokenEditBudget fautes. Pour un document, la meilleure
+// correspondance de chaque mot est retenue.
+func (idx *searchIndex) fuzzyTokenCandidates(query string, maxDistance int) map[int]tokenMatch {
+	var matches map[int]tokenMatch
+	for _, word := range strings.Fields(query) {
+		wordMatches := make(map[int]tokenMatch)
+		for _, similar := range idx.similarWords(word, tokenEditBudget(word, maxDistance)) {
+			for _, docID := range similar.entry.docs {
+				previous, covered := matches[docID]
+				if idx.docs[docID] == nil || (matches != nil && !covered) {
+					continue
+				}
+				distance := previous.distance + similar.distance
+				if current, found := wordMatches[docID]; found && current.distance <= distance {
+					continue
+				}
+				words := append(append([]string{}, previous.words...), similar.key)
+				wordMatches[docID] = tokenMatch{distance: distance, words: words}
+			}
+		}
+		matches = wordMatches
+		if len(matches) == 0 {
+			break
+		}
+	}
+	return matches
+}
+
+// mergeSuffixes insère les nouveaux suffixes dans la liste triée
+func (idx *searchIndex) mergeSuffixes(newSuffixes []string) {
+	if len(newSuffixes) == 0 {
+		return
+	}
+	sort.Strings(newSuffixes)
+
+	merged := make([]string, 0, len(idx.suffixes)+len(newSuffixes))
+	i, j := 0, 0
+	for i < len(idx.suffixes) && j < len(newSuffixes) {
+		if idx.suffixes[i] < newSuffixes[j] {
+			merged = append(merged, idx.suffixes[i])
+			i++
+		} else {
+			merged = append(merged, newSuffixes[j])
+			j++
+		}
+	}
+	merged = append(merged, idx.suffixes[i:]...)
+	merged = append(merged, newSuffixes[j:]...)
+	idx.suffixes = merged
+}
+
+// candidates retourne, dans l'ordre du parcours linéaire, les documents
+// pouvant contenir l'une des requêtes (repliées, sans espaces autour)
+func (idx *searchIndex) candidates(queries ...string) []*indexedDoc {
+	ids := make(map[int]bool)
+	for _, query := range queries {
+		for docID := range idx.candidateIDs(query) {
+			ids[docID] = true
+		}
+	}
+	return idx.ordered(ids)
+}
+
+// candidateIDs retourne les documents contenant tous les jetons de query
+func (idx *searchIndex) candidateIDs(query string) map[int]bool {
+	// Chaque jeton de la requête doit apparaître dans le document : les
+	// ensembles de candidats de chaque jeton sont intersectés
+	var ids map[int]bool
+	for _, token := range strings.Fields(query) {
+		tokenIDs := make(map[int]bool)
+		start := sort.SearchStrings(idx.suffixes, token)
+		for i := start; i < len(idx.suffixes) && strings.HasPrefix(idx.suffixes[i], token); i++ {
+			for _, docID := range idx.postings[idx.suffixes[i]] {
+				if ids == nil || ids[docID] {
+					tokenIDs[docID] = true
+				}
+			}
+		}
+		ids = tokenIDs
+		if len(ids) == 0 {
+			break
+		}
+	}
+	if ids == nil {
+		ids = make(map[int]bool)
+	}
+
+	// "usa", "uk"... désignent un pays sans apparaître dans le texte affiché
+	if country, ok := LookupCountry(query); ok {
+		for _, docID := range idx.countries[country.Code] {
+			ids[docID] = true
+		}
+	}
+	return ids
+}
+
+// fuzzyCandidates retourne les noms et membres dont la longueur permet une
+// distance de Levenshtein inférieure ou égale à maxDistance
+func (idx *searchIndex) fuzzyCandidates(query string, maxDistance int) []*indexedDoc {
+	ids := make(map[int]bool)
+	queryLength := utf8.RuneCountInString(query)
+	for length := queryLength - maxDistance; length <= queryLength+maxDistance; length++ {
+		for _, docID := range idx.byLength[length] {
+			ids[docID] = true
+		}
+	}
+	return idx.ordered(ids)
+}
+
+// ordered trie les documents encore présents par (artiste, champ)
+func (idx *searchIndex) ordered(ids map[int]bool) []*indexedDoc {
+	docIDs := idx.orderedIDs(ids)
+	docs := make([]*indexedDoc, len(docIDs))
+	for i, docID := range docIDs {
+		docs[i] = idx.docs[docID]
+	}
+	return docs
+}
+
+// orderedIDs trie les docIDs encore présents par (artiste, champ)
+func (idx *searchIndex) orderedIDs(ids map[int]bool) []int {
+	docIDs := make([]int, 0, len(ids))
+	for docID := range ids {
+		if idx.docs[docID] != nil {
+			docIDs = append(docIDs, docID)
+		}
+	}
+	sort.Slice(docIDs, func(i, j int) bool {
+		a, b := idx.docs[docIDs[i]], idx.docs[docIDs[j]]
+		if a.pos != b.pos {
+			return a.pos < b.pos
+		}
+		return a.seq < b.seq
+	})
+	return docIDs
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"groupie-tracker/models"
+)
+
+// linearSearch est la recherche par parcours complet des artistes, gardée
+// comme référence : l'index doit retourner exactement les mêmes résultats
+func linearSearch(se *SearchEngine, query string) []SearchResult {
+	loose := looseText(query)
+	query = foldText(strings.TrimSpace(query))
+	if query == "" {
+		return []SearchResult{}
+	}
+
+	results := []SearchResult{}
+	seen := make(map[string]bool)
+	add := func(key string, result SearchResult, matched string) {
+		if seen[key] {
+			return
+		}
+		result.Score = se.calculateScore(result.MatchedText, matched, result.MatchStart, result.Type)
+		results = append(results, result)
+		seen[key] = true
+	}
+
+	state := se.store.State()
+	for _, artist := range state.Artists {
+		texts := []struct {
+			text string
+			typ  SearchType
+			key  string
+		}{{artist.Name, SearchTypeArtist, artist.Name + "-artist"}}
+		for _, member := range artist.Members {
+			texts = append(texts, struct {
+				text string
+				typ  SearchType
+				key  string
+			}{member, SearchTypeMember, artist.Name + "-" + member})
+		}
+		firstAlbum := artist.FirstAlbum.String()
+		texts = append(texts, struct {
+			text string
+			typ  SearchType
+			key  string
+		}{firstAlbum, SearchTypeDate, artist.Name + "-" + firstAlbum})
+
+		for _, field := range texts {
+			matchPos, matchEnd := foldIndex(field.text, query)
+			if matchPos == -1 && loose != "" {
+				matchPos, matchEnd = looseWithOffsets(field.text).index(loose)
+			}
+			if matchPos != -1 {
+				add(field.key, SearchResult{ArtistID: artist.ID, ArtistName: artist.Name, MatchedText: field.text,
+					Type: field.typ, MatchStart: matchPos, MatchEnd: matchEnd}, runeSlice(field.text, matchPos, matchEnd))
+			}
+		}
+
+		if aggregate, exists := state.Aggregate(artist.ID); exists {
+			for _, location := range aggregate.Locations.Locations {
+				place := ParsePlace(location)
+				display := place.String()
+				matchPos, matchEnd := matchPlace(place, display, query)
+				if matchPos == -1 && loose != "" {
+					matchPos, matchEnd = looseWithOffsets(display).index(loose)
+				}
+				if matchPos != -1 {
+					add(artist.Name+"-"+location, SearchResult{ArtistID: artist.ID, ArtistName: artist.Name, MatchedText: display,
+						Type: SearchTypeLocation, MatchStart: matchPos, MatchEnd: matchEnd}, runeSlice(display, matchPos, matchEnd))
+				}
+			}
+		}
+	}
+
+	if dateQuery, ok := ParseDateQuery(query); ok {
+		results = append(results, se.searchConcerts(dateQuery)...)
+	}
+
+	return se.sortByScore(results)
+}
+
+var syntheticLocations = []string{
+	"london-uk", "paris-france", "los_angeles-usa", "new_york-usa", "sydney-new_south_wales-australia",
+	"berlin-germany", "tokyo-japan", "sao_paulo-brazil", "montreal-quebec-canada", "lyon-france",
+}
+
+var syntheticNames = []string{"Queen", "Pink Floyd", "The Rolling Stones", "Daft Punk", "Nirvana", "Muse", "Björk", "AC/DC"}
+
+// syntheticStore génère n artistes avec membres et lieux (dont des doublons)
+func syntheticStore(n int) *ArtistStore {
+	artists := make([]models.Artist, 0, n)
+	aggregates := make(map[int]models.ArtistAggregate, n)
+	for i := 1; i <= n; i++ {
+		artist := models.Artist{
+			ID:         i,
+			Name:       fmt.Sprintf("%s %d", syntheticNames[i%len(syntheticNames)], i),
+			Members:    []string{fmt.Sprintf("Freddie Mercury %d", i), fmt.Sprintf("Roger Taylor %d", i%50), "Brian May"},
+			FirstAlbum: models.MustParseDate(fmt.Sprintf("%02d-%02d-%d", 1+i%28, 1+i%12, 1960+i%60)),
+		}
+		artists = append(artists, artist)
+
+		locations := []string{}
+		for j := 0; j < 1+i%len(syntheticLocations); j++ {
+			locations = append(locations, syntheticLocations[(i+j)%len(syntheticLocations)])
+		}
+		aggregates[i] = models.ArtistAggregate{Artist: artist, Locations: models.Location{ID: i, Locations: locations}}
+	}
+
+	store := NewArtistStore(newStoreSource())
+	store.Replace(artists, aggregates)
+	return store
+}
+
+var equivalenceQueries = []string{
+	"queen", "QUEEN 1", "pink floyd", "floyd 2", "o", "roger", "taylor 7", "brian may", "mercury 12",
+	"1986", "-19", "paris", "usa", "uk", "GB", "france", "sydney, new", "états", "etats", "björk", "bjork", "ac/dc",
+	"  muse  ", "zzz", "e 3", "new south wales", "acdc", "ac dc", "ac-dc 1", "queen & 8", "los-angeles",
+}
+
+func assertSameResults(t *testing.T, engine *SearchEngine, queries []string) {
+	t.Helper()
+	for _, query := range queries {
+		got := engine.Search(query)
+		want := linearSearch(engine, query)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Search(%q): %d résultats, référence %d\n got: %+v\nwant: %+v", query, len(got), len(want), got, want)
+		}
+	}
+}
+
+func TestSearchIndex_SameResultsAsLinearScan(t *testing.T) {
+	engine := NewSearchEngineWithStore(syntheticStore(300))
+	defer engine.Close()
+
+	assertSameResults(t, engine, equivalenceQueries)
+}
+
+func TestSearchIndex_IncrementalUpdates(t *testing.T) {
+	source := newStoreSource()
+	store := NewArtistStore(source)
+	engine := NewSearchEngineWithStore(store)
+	defer engine.Close()
+
+	// Store vide au départ, puis liste d'artistes
+	if results := engine.Search("queen"); len(results) != 0 {
+		t.Fatalf("Aucun résultat attendu avant chargement, got %d", len(results))
+	}
+	store.SetArtists(source.Artists)
+	assertSameResults(t, engine, equivalenceQueries)
+
+	// Agrégats chargés un par un (lazy loading)
+	for _, artist := range source.Artists {
+		if _, err := store.Aggregate(t.Context(), artist.ID); err != nil {
+			t.Fatalf("Aggregate(%d): %v", artist.ID, err)
+		}
+		assertSameResults(t, engine, equivalenceQueries)
+	}
+
+	// Agrégat remplacé : les anciens lieux ne doivent plus sortir
+	first := source.Artists[0]
+	store.AddAggregates(map[int]models.ArtistAggregate{
+		first.ID: {Artist: first, Locations: models.Location{ID: first.ID, Locations: []string{"tokyo-japan"}}},
+	})
+	assertSameResults(t, engine, append(equivalenceQueries, "tokyo", "japon"))
+
+	// Actualisation complète
+	store.Replace(source.Artists[:1], nil)
+	assertSameResults(t, engine, equivalenceQueries)
+}
+
+func TestSearchIndex_RepeatedRefreshKeepsSizeStable(t *testing.T) {
+	store := syntheticStore(50)
+	engine := NewSearchEngineWithStore(store)
+	defer engine.Close()
+
+	// size compte les documents et les références des postings
+	size := func() (docs, postings int) {
+		engine.mu.RLock()
+		defer engine.mu.RUnlock()
+		for _, ids := range engine.index.postings {
+			postings += len(ids)
+		}
+		return len(engine.index.docs), postings
+	}
+	baseDocs, basePostings := size()
+
+	artist := store.State().Artists[0]
+	for i := range 200 {
+		locations := []string{"tokyo-japan", "paris-france"}
+		if i%2 == 1 {
+			locations = []string{"sydney-australia"}
+		}
+		store.AddAggregates(map[int]models.ArtistAggregate{
+			artist.ID: {Artist: artist, Locations: models.Location{ID: artist.ID, Locations: locations}},
+		})
+	}
+
+	// Les documents retirés ne dépassent jamais maxDeadDocShare de l'index
+	docs, postings := size()
+	if limit := int(float64(baseDocs)/(1-maxDeadDocShare)) + 2; docs > limit {
+		t.Errorf("%d documents après 200 actualisations (au départ %d, limite %d)", docs, baseDocs, limit)
+	}
+	if limit := int(float64(basePostings)/(1-maxDeadDocShare)) + 100; postings > limit {
+		t.Errorf("%d postings après 200 actualisations (au départ %d, limite %d)", postings, basePostings, limit)
+	}
+	assertSameResults(t, engine, append(equivalenceQueries, "tokyo", "sydney"))
+}
+
+func TestSearchIndex_Close(t *testing.T) {
+	store := NewArtistStore(newStoreSource())
+	engine := NewSearchEngineWithStore(store)
+	engine.Close()
+	engine.Close() // sans effet
+
+	store.SetArtists(createTestArtists())
+	if results := engine.Search("queen"); len(results) != 0 {
+		t.Errorf("Un moteur fermé ne suit plus le store, got %d résultats", len(results))
+	}
+}
+
+// fuzzyReference est la règle de la recherche floue appliquée sans index :
+// texte entier à distance 2 au plus, ou chaque mot de la requête proche d'un
+// mot du texte
+func fuzzyReference(query, text string, wholeText bool) bool {
+	if wholeText && levenshteinDistance(query, text) <= 2 {
+		return true
+	}
+	queryWords := strings.Fields(looseText(query))
+	textWords := strings.Fields(looseText(text))
+	for _, queryWord := range queryWords {
+		if !slices.ContainsFunc(textWords, func(word string) bool {
+			return levenshteinDistance(queryWord, word) <= tokenEditBudget(queryWord, 2)
+		}) {
+			return false
+		}
+	}
+	return len(queryWords) > 0
+}
+
+func TestFuzzySearch_IndexedCandidates(t *testing.T) {
+	engine := NewSearchEngineWithStore(syntheticStore(100))
+	defer engine.Close()
+	fuzzy := NewFuzzySearchEngine(engine)
+	state := engine.Store().State()
+
+	// Référence : noms et membres entiers ou mot à mot, villes mot à mot
+	for _, query := range []string{"brain may", "qeen 12", "mus 4", "mercuri 12", "fredie mercuri 7", "tailor", "los angelos", "berlinn"} {
+		want := 0
+		for _, artist := range state.Artists {
+			for _, text := range append([]string{artist.Name}, artist.Members...) {
+				if fuzzyReference(query, text, true) {
+					want++
+				}
+			}
+			aggregate, _ := state.Aggregate(artist.ID)
+			for _, location := range aggregate.Locations.Locations {
+				if fuzzyReference(query, ParsePlace(location).City, false) {
+					want++
+				}
+			}
+		}
+		// "Brian May" est partagé par tous les artistes : une entrée par artiste
+		if got := fuzzy.FuzzySearch(query, 2); len(got) != want || want == 0 {
+			t.Errorf("FuzzySearch(%q): attendu %d résultats, got %d", query, want, len(got))
+		}
+	}
+}
+
+// Benchmarks : index inversé contre parcours linéaire sur 2000 artistes
+func BenchmarkSearchIndex_Search(b *testing.B) {
+	engine := NewSearchEngineWithStore(syntheticStore(2000))
+	defer engine.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		engine.Search("taylor 42")
+	}
+}
+
+func BenchmarkSearchIndex_LinearSearch(b *testing.B) {
+	engine := NewSearchEngineWithStore(syntheticStore(2000))
+	defer engine.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		linearSearch(engine, "taylor 42")
+	}
+}
+
+func BenchmarkSearchIndex_Keystrokes(b *testing.B) {
+	engine := NewSearchEngineWithStore(syntheticStore(2000))
+	defer engine.Close()
+	query := "pink floyd 1234"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for n := 1; n <= len(query); n++ {
+			engine.Search(query[:n])
+		}
+	}
+}
+
+func BenchmarkSearchIndex_Build(b *testing.B) {
+	state := syntheticStore(2000).State()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buildSearchIndex(state)
+	}
+}
+
+func BenchmarkFuzzySearch_Indexed(b *testing.B) {
+	engine := NewSearchEngineWithStore(syntheticStore(2000))
+	defer engine.Close()
+	fuzzy := NewFuzzySearchEngine(engine)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fuzzy.FuzzySearch("qeen 12", 2)
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// =====================
+// PERSONNALISATION DU CLASSEMENT
+// =====================
+//
+// Chaque sélection enregistrée dans la SearchHistory (saisie + artiste
+// choisi) est un clic. Quand la saisie courante prolonge une saisie passée
+// (ou l'inverse : "qu" puis "queen"), l'artiste choisi alors reçoit un bonus
+// de pertinence. Chaque clic pèse selon la part de saisie commune et perd la
+// moitié de son poids à chaque demi-vie ; le bonus sature avec le nombre de
+// clics. Aucun hasard : un même historique et une même horloge donnent
+// toujours le même classement.
+
+const (
+	// DefaultClickHalfLife est la demi-vie du poids d'un clic
+	DefaultClickHalfLife = 30 * 24 * time.Hour
+
+	// maxClickBoost est le bonus de pertinence maximal d'un artiste
+	maxClickBoost = 0.15
+)
+
+// ClickBoost est le bonus de pertinence d'un artiste pour une saisie
+type ClickBoost struct {
+	Boost  float64 // ajouté à la pertinence (0 à maxClickBoost)
+	Clicks int     // sélections passées correspondant à la saisie
+}
+
+// ClickModel apprend de l'historique quels artistes l'utilisateur choisit
+// pour une saisie. Il garde sa propre copie de l'historique : le
+// SearchWorker le consulte hors du thread UI.
+type ClickModel struct {
+	mu       sync.RWMutex
+	clicks   []click
+	halfLife time.Duration
+	now      func() time.Time
+	enabled  bool
+	history  *SearchHistory // où enregistrer le choix d'activation (peut être nil)
+}
+
+// click est une sélection de l'historique, saisie repliée
+type click struct {
+	query    string
+	artistID int
+	at       time.Time
+}
+
+// NewClickModel crée un modèle activé, sans historique
+func NewClickModel(halfLife time.Duration) *ClickModel {
+	return &ClickModel{
+		halfLife: halfLife,
+		now:      time.Now,
+		enabled:  true,
+	}
+}
+
+// NewClickModelFromHistory crée un modèle sur les sélections de history et
+// reprend le choix d'activation enregistré avec elles. SetEnabled l'y
+// enregistre ensuite.
+func NewClickModelFromHistory(history *SearchHistory, halfLife time.Duration) *ClickModel {
+	m := NewClickModel(halfLife)
+	m.history = history
+	m.enabled = history.Personalized()
+	m.SetHistory(history.GetAll())
+	return m
+}
+
+// SetClock remplace l'horloge utilisée pour l'ancienneté des clics (tests)
+func (m *ClickModel) SetClock(now func() time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
+
+// SetEnabled active ou désactive la personnalisation (et enregistre ce choix
+// dans l'historique associé)
+func (m *ClickModel) SetEnabled(enabled bool) {
+	m.mu.Lock()
+	m.enabled = enabled
+	history := m.history
+	m.mu.Unlock()
+
+	if history != nil {
+		history.SetPersonalized(enabled)
+	}
+}
+
+// Enabled indique si la personnalisation est active
+func (m *ClickModel) Enabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled
+}
+
+// SetHistory remplace les clics par les entrées de l'historique (copiées)
+func (m *ClickModel) SetHistory(entries []SearchHistoryEntry) {
+	clicks := make([]click, 0, len(entries))
+	for _, entry := range entries {
+		query := foldText(strings.TrimSpace(entry.Query))
+		if query == "" || entry.ResultID == 0 {
+			continue // sélection depuis l'historique récent, sans saisie
+		}
+		clicks = append(clicks, click{query: query, artistID: entry.ResultID, at: entry.Timestamp})
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clicks = clicks
+}
+
+// Boosts retourne le bonus de chaque artiste choisi pour une saisie
+// proche de query. Retourne nil si la personnalisation est désactivée.
+func (m *ClickModel) Boosts(query string) map[int]ClickBoost {
+	if m == nil {
+		return nil
+	}
+	query = foldText(strings.TrimSpace(query))
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.enabled || query == "" {
+		return nil
+	}
+
+	now := m.now()
+	weights := make(map[int]float64)
+	counts := make(map[int]int)
+	for _, c := range m.clicks {
+		coverage := prefixCoverage(query, c.query)
+		if coverage == 0 {
+			continue
+		}
+		weights[c.artistID] += coverage * m.decay(now.Sub(c.at))
+		counts[c.artistID]++
+	}
+
+	boosts := make(map[int]ClickBoost, len(weights))
+	for artistID, weight := range weights {
+		// Saturation : un clic récent donne la moitié du bonus maximal
+		boosts[artistID] = ClickBoost{
+			Boost:  maxClickBoost * weight / (weight + 1),
+			Clicks: counts[artistID],
+		}
+	}
+	return boosts
+}
+
+// decay retourne le poids d'un clic selon son ancienneté (1 pour un clic
+// tout juste enregistré, 1/2 après une demi-vie)
+func (m *ClickModel) decay(age time.Duration) float64 {
+	if age <= 0 || m.halfLife <= 0 {
+		return 1
+	}
+	return math.Exp2(-float64(age) / float64(m.halfLife))
+}
+
+// prefixCoverage retourne la part commune de deux saisies repliées dont l'une
+// prolonge l'autre ("que" et "queen" : 3/5), ou 0
+func prefixCoverage(a, b string) float64 {
+	if !strings.HasPrefix(a, b) && !strings.HasPrefix(b, a) {
+		return 0
+	}
+	shorter, longer := utf8.RuneCountInString(a), utf8.RuneCountInString(b)
+	if shorter > longer {
+		shorter, longer = longer, shorter
+	}
+	return float64(shorter) / float64(longer)
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testNow est l'horloge fixe des tests de personnalisation
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestClickModel crée un modèle sur un historique synthétique
+func newTestClickModel(entries ...SearchHistoryEntry) *ClickModel {
+	model := NewClickModel(DefaultClickHalfLife)
+	model.SetClock(func() time.Time { return testNow })
+	model.SetHistory(entries)
+	return model
+}
+
+// clickAt est une sélection de artistID pour query, il y a age
+func clickAt(query string, artistID int, age time.Duration) SearchHistoryEntry {
+	return SearchHistoryEntry{Query: query, ResultID: artistID, Timestamp: testNow.Add(-age)}
+}
+
+func TestClickModel_PrefixMatching(t *testing.T) {
+	model := newTestClickModel(
+		clickAt("que", 1, 0),
+		clickAt("Queen", 1, 0),
+		clickAt("pink", 3, 0),
+		clickAt("", 2, 0), // sélection depuis l'historique récent
+	)
+
+	tests := []struct {
+		query  string
+		clicks map[int]int // artiste -> clics retenus
+	}{
+		{"q", map[int]int{1: 2}},
+		{"QUE", map[int]int{1: 2}},
+		{"queen live", map[int]int{1: 2}}, // la saisie prolonge une saisie passée
+		{"pi", map[int]int{3: 1}},
+		{"beatles", map[int]int{}},
+	}
+
+	for _, tt := range tests {
+		boosts := model.Boosts(tt.query)
+		clicks := make(map[int]int)
+		for artistID, boost := range boosts {
+			clicks[artistID] = boost.Clicks
+		}
+		if !reflect.DeepEqual(clicks, tt.clicks) {
+			t.Errorf("Boosts(%q) = %v, attendu %v", tt.query, clicks, tt.clicks)
+		}
+	}
+}
+
+func TestClickModel_BoostSaturatesAndDecays(t *testing.T) {
+	// Un clic récent et complet donne la moitié du bonus maximal
+	boost := newTestClickModel(clickAt("queen", 1, 0)).Boosts("queen")[1].Boost
+	if math.Abs(boost-maxClickBoost/2) > 1e-9 {
+		t.Errorf("bonus d'un clic = %v, attendu %v", boost, maxClickBoost/2)
+	}
+
+	// Une demi-vie plus tard, le clic pèse moitié moins : 0.5 / 1.5
+	old := newTestClickModel(clickAt("queen", 1, DefaultClickHalfLife)).Boosts("queen")[1].Boost
+	if math.Abs(old-maxClickBoost/3) > 1e-9 {
+		t.Errorf("bonus d'un clic ancien = %v, attendu %v", old, maxClickBoost/3)
+	}
+
+	// Les clics répétés augmentent le bonus sans dépasser le maximum
+	clicks := []SearchHistoryEntry{}
+	previous := 0.0
+	for i := range 20 {
+		clicks = append(clicks, clickAt("queen", 1, time.Duration(i)*time.Hour))
+		boost := newTestClickModel(clicks...).Boosts("queen")[1].Boost
+		if boost <= previous || boost >= maxClickBoost {
+			t.Fatalf("%d clics : bonus %v (précédent %v)", i+1, boost, previous)
+		}
+		previous = boost
+	}
+
+	// Un clic récent compte plus que deux clics vieux de deux demi-vies
+	model := newTestClickModel(
+		clickAt("roger", 1, 2*DefaultClickHalfLife),
+		clickAt("roger", 1, 2*DefaultClickHalfLife),
+		clickAt("roger", 3, time.Hour),
+	)
+	boosts := model.Boosts("roger")
+	if boosts[3].Boost <= boosts[1].Boost {
+		t.Errorf("le clic récent devrait l'emporter, got %+v", boosts)
+	}
+}
+
+func TestClickModel_Toggle(t *testing.T) {
+	model := newTestClickModel(clickAt("queen", 1, 0))
+
+	model.SetEnabled(false)
+	if model.Enabled() || model.Boosts("queen") != nil {
+		t.Error("aucun bonus attendu quand la personnalisation est désactivée")
+	}
+	model.SetEnabled(true)
+	if len(model.Boosts("queen")) != 1 {
+		t.Error("bonus attendu après réactivation")
+	}
+
+	var none *ClickModel
+	if none.Boosts("queen") != nil {
+		t.Error("un modèle nil ne donne aucun bonus")
+	}
+}
+
+func TestClickModel_PersistsToggle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search_history.json")
+
+	history := NewSearchHistoryAt(path, 50)
+	history.Add("queen", 1)
+	model := NewClickModelFromHistory(history, DefaultClickHalfLife)
+	if !model.Enabled() || len(model.Boosts("queen")) != 1 {
+		t.Fatal("personnalisation active par défaut, avec les clics de l'historique")
+	}
+
+	// Le choix est enregistré dans le fichier de l'historique
+	model.SetEnabled(false)
+	reloaded := NewClickModelFromHistory(NewSearchHistoryAt(path, 50), DefaultClickHalfLife)
+	if reloaded.Enabled() {
+		t.Error("la désactivation doit survivre au redémarrage")
+	}
+	reloaded.SetEnabled(true)
+	if again := NewClickModelFromHistory(NewSearchHistoryAt(path, 50), DefaultClickHalfLife); !again.Enabled() {
+		t.Error("la réactivation doit survivre au redémarrage")
+	}
+}
+
+func TestSearchHistory_LegacyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search_history.json")
+	legacy := `[{"query": "queen", "timestamp": "2024-06-01T12:00:00Z", "result_id": 1}]`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	history := NewSearchHistoryAt(path, 50)
+	if entries := history.GetAll(); len(entries) != 1 || entries[0].ResultID != 1 {
+		t.Errorf("entrées de l'ancien format perdues: %+v", entries)
+	}
+	if !history.Personalized() {
+		t.Error("l'ancien format n'enregistrait pas de choix : personnalisation active")
+	}
+}
+
+func TestSearchPipeline_PersonalizedRanking(t *testing.T) {
+	pipeline := NewSearchPipeline(newPipelineTestEngine(t))
+
+	// Sans historique : à pertinence égale, Queen (premier artiste) d'abord
+	baseline := pipeline.Search("roger", 2)
+	if len(baseline) != 2 || baseline[0].MatchedText != "Roger Taylor" || baseline[1].MatchedText != "Roger Waters" {
+		t.Fatalf("classement de référence inattendu: %+v", baseline)
+	}
+
+	// L'utilisateur choisit régulièrement Pink Floyd en tapant "rog"
+	model := newTestClickModel(
+		clickAt("rog", 3, 48*time.Hour),
+		clickAt("rog", 3, 24*time.Hour),
+		clickAt("roger w", 3, time.Hour),
+	)
+	pipeline.SetClickModel(model)
+
+	results := pipeline.Search("roger", 2)
+	if len(results) != 2 || results[0].MatchedText != "Roger Waters" {
+		t.Fatalf("Roger Waters attendu en premier, got %+v", results)
+	}
+	last := results[0].Reasons[len(results[0].Reasons)-1]
+	if last.Engine != MatchHistory || last.Detail != "déjà choisi 3 fois pour cette saisie" {
+		t.Errorf("raison de personnalisation attendue, got %s", results[0].Explain())
+	}
+	if results[0].Relevance <= baseline[1].Relevance {
+		t.Errorf("la pertinence doit augmenter: %v <= %v", results[0].Relevance, baseline[1].Relevance)
+	}
+
+	// Déterministe : même historique, même horloge, même classement
+	for range 5 {
+		if again := pipeline.Search("roger", 2); !reflect.DeepEqual(again, results) {
+			t.Fatalf("classement non déterministe:\n%+v\n%+v", again, results)
+		}
+	}
+
+	// Désactivée : classement de référence
+	model.SetEnabled(false)
+	if disabled := pipeline.Search("roger", 2); !reflect.DeepEqual(disabled, baseline) {
+		t.Errorf("désactivée, le classement devrait être celui de référence, got %+v", disabled)
+	}
+}
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// =====================
+// PIPELINE DE RECHERCHE UNIFIÉ
+// =====================
+//
+// Chaque moteur (exact, initiales, flou, phonétique) a sa propre formule de
+// score : les comparer directement ferait toujours gagner un match exact
+// faible contre des initiales parfaites. Le pipeline ramène chaque match à une
+// pertinence entre 0 et 1, propre à son moteur (part du texte couverte,
+// fautes, mots prononcés...), plafonnée par la confiance accordée au moteur,
+// puis fusionne les doublons en gardant les raisons de chaque moteur.
+
+// MatchEngine identifie le moteur ayant produit un match
+type MatchEngine string
+
+const (
+	MatchExact    MatchEngine = "exact"    // texte contenu dans un champ
+	MatchQuery    MatchEngine = "query"    // requête structurée (champ:valeur)
+	MatchConcert  MatchEngine = "concert"  // date de concert
+	MatchInitials MatchEngine = "initials" // initiales ("fm")
+	MatchFuzzy    MatchEngine = "fuzzy"    // distance de Levenshtein
+	MatchPhonetic MatchEngine = "phonetic" // même son
+	MatchHistory  MatchEngine = "history"  // sélections passées (personnalisation)
+)
+
+// engineConfidence est la pertinence maximale d'un match de chaque moteur
+var engineConfidence = map[MatchEngine]float64{
+	MatchExact:    1.0,
+	MatchQuery:    1.0,
+	MatchConcert:  1.0,
+	MatchInitials: 0.9,
+	MatchFuzzy:    0.85,
+	MatchPhonetic: 0.75,
+}
+
+// fuzzyMaxDistance est la distance de Levenshtein tolérée par le pipeline
+const fuzzyMaxDistance = 2
+
+// MatchReason explique pourquoi un moteur a retenu un résultat
+type MatchReason struct {
+	Engine    MatchEngine
+	RawScore  int     // score d'origine du moteur
+	Relevance float64 // pertinence normalisée (0 à 1)
+	Detail    string  // explication lisible
+}
+
+// RankedResult est un résultat fusionné du pipeline. Score vaut la
+// pertinence sur 1000 plus un léger bonus de type (artistes d'abord à
+// pertinence égale). Le bonus de personnalisation s'ajoute à la pertinence,
+// qui peut alors dépasser 1.
+type RankedResult struct {
+	SearchResult
+	Relevance float64
+	Reasons   []MatchReason // de la plus pertinente à la moins pertinente
+}
+
+// Explain résume les raisons du match ("« que » dans le nom · initiales « Q »")
+func (r RankedResult) Explain() string {
+	details := make([]string, len(r.Reasons))
+	for i, reason := range r.Reasons {
+		details[i] = reason.Detail
+	}
+	return strings.Join(details, " · ")
+}
+
+// SearchPipeline interroge tous les moteurs et classe leurs résultats sur une
+// échelle commune
+type SearchPipeline struct {
+	engine   *SearchEngine
+	initials *InitialsSearchEngine
+	fuzzy    *FuzzySearchEngine
+	phonetic *PhoneticSearchEngine
+	clicks   *ClickModel // personnalisation (nil : aucune)
+}
+
+// NewSearchPipeline crée un pipeline sur un moteur de recherche
+func NewSearchPipeline(engine *SearchEngine) *SearchPipeline {
+	return &SearchPipeline{
+		engine:   engine,
+		initials: NewInitialsSearchEngine(engine),
+		fuzzy:    NewFuzzySearchEngine(engine),
+		phonetic: NewPhoneticSearchEngine(engine),
+	}
+}
+
+// SetClickModel active la personnalisation du classement par l'historique.
+// À appeler avant la première recherche.
+func (p *SearchPipeline) SetClickModel(clicks *ClickModel) {
+	p.clicks = clicks
+}
+
+// Search retourne au plus limit résultats classés (limit <= 0 : tous)
+func (p *SearchPipeline) Search(query string, limit int) []RankedResult {
+	results, _ := p.SearchContext(context.Background(), query, limit)
+	return results
+}
+
+// SearchContext est Search interrompue entre deux moteurs dès que ctx est
+// annulé (requête périmée pendant la frappe) : retourne alors ctx.Err()
+func (p *SearchPipeline) SearchContext(ctx context.Context, query string, limit int) ([]RankedResult, error) {
+	query = strings.TrimSpace(query)
+	folded := foldText(query)
+	if folded == "" {
+		return []RankedResult{}, nil
+	}
+
+	merger := newResultMerger()
+	merger.personalize(p.clicks.Boosts(query))
+
+	// Requête structurée : seul le langage de requête s'applique
+	if parsed, err := ParseQuery(query); err == nil && parsed.Structured() {
+		for _, r := range p.engine.SearchQuery(parsed) {
+			merger.add(r, MatchQuery, queryRelevance(r), fmt.Sprintf("correspond à %s", parsed))
+		}
+		return merger.rankedContext(ctx, limit)
+	}
+
+	dateQuery, isDate := ParseDateQuery(query)
+	target, isAlias := p.engine.AliasTarget(query)
+	for _, r := range p.engine.Search(query) {
+		if r.Type == SearchTypeConcert && isDate {
+			merger.add(r, MatchConcert, concertRelevance(dateQuery.Precision), "concert du "+r.MatchedText)
+			continue
+		}
+		span := runeSlice(r.MatchedText, r.MatchStart, r.MatchEnd)
+		detail := fmt.Sprintf("« %s » dans %s", span, typeLabel(r.Type))
+		if isAlias && looseText(span) == looseText(target) {
+			detail = fmt.Sprintf("alias « %s » de « %s »", query, target)
+		}
+		merger.add(r, MatchExact, exactRelevance(r), detail)
+	}
+
+	length := utf8.RuneCountInString(folded)
+	if length >= 2 && length <= 5 && !strings.Contains(folded, " ") {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, r := range p.initials.SearchByInitials(folded) {
+			merger.add(r, MatchInitials, initialsRelevance(folded, r.MatchedText),
+				fmt.Sprintf("initiales « %s »", strings.ToUpper(folded)))
+		}
+	}
+
+	if length >= 3 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		loose := looseText(folded)
+		for _, match := range p.fuzzy.fuzzyMatches(folded, fuzzyMaxDistance) {
+			if match.distance == 0 && strings.Contains(looseText(match.MatchedText), loose) {
+				continue // déjà trouvé par la recherche exacte
+			}
+			span := runeSlice(match.MatchedText, match.MatchStart, match.MatchEnd)
+			merger.add(match.SearchResult, MatchFuzzy, fuzzyRelevance(folded, span, match.distance),
+				fmt.Sprintf("orthographe proche (%d lettre(s) de différence)", match.distance))
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, r := range p.phonetic.PhoneticSearch(folded) {
+		span := runeSlice(r.MatchedText, r.MatchStart, r.MatchEnd)
+		if foldText(span) == folded {
+			continue // orthographe exacte : déjà trouvé par la recherche exacte
+		}
+		merger.add(r, MatchPhonetic, phoneticRelevance(folded, span), fmt.Sprintf("se prononce comme « %s »", span))
+	}
+
+	return merger.rankedContext(ctx, limit)
+}
+
+// =====================
+// NORMALISATION DES SCORES
+// =====================
+
+// exactRelevance : part du texte couverte, bonus en début de texte ou de mot.
+// Un texte égal à la requête vaut 1.
+func exactRelevance(r SearchResult) float64 {
+	length := utf8.RuneCountInString(r.MatchedText)
+	if length == 0 {
+		return 0
+	}
+	coverage := float64(r.MatchEnd-r.MatchStart) / float64(length)
+
+	relevance := 0.5 + 0.35*coverage
+	switch {
+	case r.MatchStart == 0:
+		relevance += 0.15
+	case runeSlice(r.MatchedText, r.MatchStart-1, r.MatchStart) == " ":
+		relevance += 0.08
+	}
+	return min(relevance, 1) * engineConfidence[MatchExact]
+}
+
+// queryRelevance : un résultat de requête structurée satisfait tous les
+// critères ; le surlignage, s'il existe, départage
+func queryRelevance(r SearchResult) float64 {
+	if r.MatchEnd <= r.MatchStart {
+		return 0.8 * engineConfidence[MatchQuery]
+	}
+	return exactRelevance(r)
+}
+
+// concertRelevance : plus la date saisie est précise, plus le concert compte
+func concertRelevance(precision DatePrecision) float64 {
+	relevance := 0.7
+	switch precision {
+	case PrecisionDay:
+		relevance = 0.95
+	case PrecisionMonth:
+		relevance = 0.85
+	case PrecisionYear:
+		relevance = 0.75
+	}
+	return relevance * engineConfidence[MatchConcert]
+}
+
+// initialsRelevance : part des mots du texte couverte par les initiales
+func initialsRelevance(initials, text string) float64 {
+	words := len(strings.Fields(text))
+	if words == 0 {
+		return 0
+	}
+	coverage := min(float64(utf8.RuneCountInString(initials))/float64(words), 1)
+	return coverage * engineConfidence[MatchInitials]
+}
+
+// fuzzyRelevance : proportion de lettres correctes
+func fuzzyRelevance(query, text string, distance int) float64 {
+	length := max(utf8.RuneCountInString(query), utf8.RuneCountInString(foldText(text)))
+	if length == 0 {
+		return 0
+	}
+	return max(1-float64(distance)/float64(length), 0) * engineConfidence[MatchFuzzy]
+}
+
+// phoneticRelevance : part des mots du texte prononcés dans la requête
+func phoneticRelevance(query, matched string) float64 {
+	words := len(strings.Fields(matched))
+	if words == 0 {
+		return 0
+	}
+	coverage := min(float64(len(strings.Fields(query)))/float64(words), 1)
+	return coverage * engineConfidence[MatchPhonetic]
+}
+
+// typeBonus départage les pertinences égales (sur 1000)
+func typeBonus(searchType SearchType) int {
+	switch searchType {
+	case SearchTypeArtist:
+		return 30
+	case SearchTypeMember:
+		return 20
+	case SearchTypeLocation, SearchTypeConcert:
+		return 10
+	}
+	return 0
+}
+
+// typeLabel décrit le champ d'un résultat dans une explication
+func typeLabel(searchType SearchType) string {
+	switch searchType {
+	case SearchTypeArtist:
+		return "le nom"
+	case SearchTypeMember:
+		return "un membre"
+	case SearchTypeLocation:
+		return "un lieu"
+	case SearchTypeDate:
+		return "la date du premier album"
+	case SearchTypeConcert:
+		return "un concert"
+	}
+	return string(searchType)
+}
+
+// =====================
+// FUSION DES RÉSULTATS
+// =====================
+
+// agreementBonus récompense un résultat trouvé par plusieurs moteurs
+const agreementBonus = 0.02
+
+// resultMerger fusionne les matchs d'un même champ d'un même artiste
+type resultMerger struct {
+	results []*RankedResult
+	byKey   map[string]*RankedResult
+	boosts  map[int]ClickBoost // bonus de personnalisation par artiste
+}
+
+func newResultMerger() *resultMerger {
+	return &resultMerger{byKey: make(map[string]*RankedResult)}
+}
+
+// personalize ajoute à chaque résultat le bonus de son artiste
+func (m *resultMerger) personalize(boosts map[int]ClickBoost) {
+	m.boosts = boosts
+}
+
+// add enregistre le match d'un moteur. Le surlignage retenu est celui du
+// moteur le plus pertinent.
+func (m *resultMerger) add(r SearchResult, engine MatchEngine, relevance float64, detail string) {
+	reason := MatchReason{Engine: engine, RawScore: r.Score, Relevance: relevance, Detail: detail}
+
+	key := fmt.Sprintf("%d-%s-%s", r.ArtistID, r.MatchedText, r.Type)
+	if r.Type == SearchTypeConcert {
+		key += "-" + r.Location // un même jour peut compter plusieurs concerts
+	}
+
+	existing, ok := m.byKey[key]
+	if !ok {
+		ranked := &RankedResult{SearchResult: r, Reasons: []MatchReason{reason}}
+		m.byKey[key] = ranked
+		m.results = append(m.results, ranked)
+		return
+	}
+
+	for _, known := range existing.Reasons {
+		if known.Engine == engine {
+			return // un moteur ne compte qu'une fois par résultat
+		}
+	}
+	if relevance > existing.Reasons[0].Relevance {
+		existing.SearchResult = r
+	}
+	existing.Reasons = append(existing.Reasons, reason)
+	sort.SliceStable(existing.Reasons, func(i, j int) bool {
+		return existing.Reasons[i].Relevance > existing.Reasons[j].Relevance
+	})
+}
+
+// rankedContext est ranked, sauf si ctx a été annulé entre-temps
+func (m *resultMerger) rankedContext(ctx context.Context, limit int) ([]RankedResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ranked(limit), nil
+}
+
+// ranked calcule les scores unifiés et trie (à score égal, l'ordre d'arrivée
+// est conservé : recherche exacte d'abord)
+func (m *resultMerger) ranked(limit int) []RankedResult {
+	results := make([]RankedResult, 0, len(m.results))
+	for _, r := range m.results {
+		r.Relevance = min(r.Reasons[0].Relevance+agreementBonus*float64(len(r.Reasons)-1), 1)
+		if boost := m.boosts[r.ArtistID]; boost.Boost > 0 {
+			r.Relevance += boost.Boost
+			r.Reasons = append(r.Reasons, MatchReason{
+				Engine:    MatchHistory,
+				RawScore:  int(boost.Boost*1000 + 0.5),
+				Relevance: boost.Boost,
+				Detail:    fmt.Sprintf("déjà choisi %d fois pour cette saisie", boost.Clicks),
+			})
+		}
+		r.Score = int(r.Relevance*1000+0.5) + typeBonus(r.Type)
+		results = append(results, *r)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
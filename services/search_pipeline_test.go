@@ -0,0 +1,146 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"groupie-tracker/models"
+)
+
+func newPipelineTestEngine(t *testing.T, extra ...models.Artist) *SearchEngine {
+	t.Helper()
+	store := NewArtistStore(newStoreSource())
+	store.Replace(append(createTestArtists(), extra...), nil)
+	engine := NewSearchEngineWithStore(store)
+	t.Cleanup(engine.Close)
+	return engine
+}
+
+func TestSearchPipeline_PerfectInitialsBeatWeakExact(t *testing.T) {
+	engine := newPipelineTestEngine(t, models.Artist{ID: 4, Name: "Golfmania"})
+	pipeline := NewSearchPipeline(engine)
+
+	results := pipeline.Search("fm", 0)
+	if len(results) < 2 {
+		t.Fatalf("attendu au moins 2 résultats, got %+v", results)
+	}
+	if results[0].MatchedText != "Freddie Mercury" || results[0].Reasons[0].Engine != MatchInitials {
+		t.Errorf("Les initiales parfaites devraient passer devant, got %q (%s)", results[0].MatchedText, results[0].Explain())
+	}
+
+	// L'ancienne concaténation mettait le match exact en premier
+	if exact := engine.Search("fm"); len(exact) == 0 || exact[0].MatchedText != "Golfmania" {
+		t.Fatalf("Golfmania devrait être un match exact, got %+v", exact)
+	}
+}
+
+func TestSearchPipeline_ExactMatchFirst(t *testing.T) {
+	pipeline := NewSearchPipeline(newPipelineTestEngine(t))
+
+	results := pipeline.Search("Queen", 5)
+	if len(results) == 0 || results[0].MatchedText != "Queen" {
+		t.Fatalf("Queen attendu en premier, got %+v", results)
+	}
+	if results[0].Relevance != 1 || results[0].Score != 1000+typeBonus(SearchTypeArtist) {
+		t.Errorf("Un match exact complet vaut 1, got %.2f (%d)", results[0].Relevance, results[0].Score)
+	}
+	// La recherche floue (distance 0) ne duplique pas le match exact
+	if n := len(results[0].Reasons); n != 1 {
+		t.Errorf("une seule raison attendue, got %s", results[0].Explain())
+	}
+}
+
+func TestSearchPipeline_MergesEngines(t *testing.T) {
+	engine := newPipelineTestEngine(t, models.Artist{ID: 4, Name: "Metallica"})
+	pipeline := NewSearchPipeline(engine)
+
+	results := pipeline.Search("Metalika", 0)
+	if len(results) != 1 {
+		t.Fatalf("un seul résultat fusionné attendu, got %+v", results)
+	}
+	r := results[0]
+	engines := map[MatchEngine]bool{}
+	for _, reason := range r.Reasons {
+		engines[reason.Engine] = true
+	}
+	if !engines[MatchFuzzy] || !engines[MatchPhonetic] {
+		t.Errorf("flou et phonétique attendus, got %+v", r.Reasons)
+	}
+	if r.Relevance <= r.Reasons[0].Relevance {
+		t.Errorf("L'accord de plusieurs moteurs devrait augmenter la pertinence: %.3f", r.Relevance)
+	}
+	explanation := r.Explain()
+	if !strings.Contains(explanation, "orthographe proche") || !strings.Contains(explanation, "se prononce comme") {
+		t.Errorf("Explication incomplète: %q", explanation)
+	}
+}
+
+func TestSearchPipeline_ScoresAreComparable(t *testing.T) {
+	pipeline := NewSearchPipeline(newPipelineTestEngine(t))
+
+	for _, query := range []string{"e", "qu", "bm", "qeen", "roger", "brain may", "1973"} {
+		results := pipeline.Search(query, 0)
+		for i, r := range results {
+			if r.Relevance < 0 || r.Relevance > 1 {
+				t.Errorf("%q: pertinence hors de [0, 1]: %+v", query, r)
+			}
+			if len(r.Reasons) == 0 || r.Explain() == "" {
+				t.Errorf("%q: résultat sans explication: %+v", query, r)
+			}
+			if i > 0 && results[i-1].Score < r.Score {
+				t.Errorf("%q: résultats non triés", query)
+			}
+		}
+	}
+}
+
+func TestSearchPipeline_StructuredAndConcerts(t *testing.T) {
+	engine := queryTestEngine()
+	defer engine.Close()
+	pipeline := NewSearchPipeline(engine)
+
+	results := pipeline.Search("member:freddie", 0)
+	if len(results) != 1 || results[0].Reasons[0].Engine != MatchQuery {
+		t.Errorf("Requête structurée: attendu un résultat MatchQuery, got %+v", results)
+	}
+
+	concerts := 0
+	for _, r := range pipeline.Search("20/04/2019", 0) {
+		if r.Type == SearchTypeConcert {
+			concerts++
+			if r.Reasons[0].Engine != MatchConcert || !strings.Contains(r.Explain(), "20/04/2019") {
+				t.Errorf("Concert mal expliqué: %+v", r)
+			}
+		}
+	}
+	if concerts != 1 {
+		t.Errorf("attendu 1 concert, got %d", concerts)
+	}
+}
+
+func TestSearchPipeline_LimitAndEmpty(t *testing.T) {
+	pipeline := NewSearchPipeline(newPipelineTestEngine(t))
+
+	if results := pipeline.Search("   ", 10); len(results) != 0 {
+		t.Errorf("requête vide: aucun résultat attendu, got %+v", results)
+	}
+	if results := pipeline.Search("e", 2); len(results) != 2 {
+		t.Errorf("limite de 2 non respectée: %d", len(results))
+	}
+}
+
+func TestSearchPipeline_TokenFuzzy(t *testing.T) {
+	pipeline := NewSearchPipeline(newPipelineTestEngine(t))
+
+	for _, query := range []string{"fredie mercuri", "mercury freddie"} {
+		results := pipeline.Search(query, 5)
+		if len(results) == 0 || results[0].MatchedText != "Freddie Mercury" {
+			t.Errorf("Search(%q): Freddie Mercury attendu en premier, got %+v", query, results)
+			continue
+		}
+		if !slices.ContainsFunc(results[0].Reasons, func(r MatchReason) bool { return r.Engine == MatchFuzzy }) {
+			t.Errorf("Search(%q): raison floue attendue, got %s", query, results[0].Explain())
+		}
+	}
+}
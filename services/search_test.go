@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"groupie-tracker/models"
+	"sync"
 	"testing"
 )
 
@@ -13,21 +15,21 @@ func createTestArtists() []models.Artist {
 			Name:         "Queen",
 			Members:      []string{"Freddie Mercury", "Brian May", "Roger Taylor", "John Deacon"},
 			CreationDate: 1970,
-			FirstAlbum:   "14-12-1973",
+			FirstAlbum:   models.MustParseDate("14-12-1973"),
 		},
 		{
 			ID:           2,
 			Name:         "The Beatles",
 			Members:      []string{"John Lennon", "Paul McCartney", "George Harrison", "Ringo Starr"},
 			CreationDate: 1960,
-			FirstAlbum:   "22-03-1963",
+			FirstAlbum:   models.MustParseDate("22-03-1963"),
 		},
 		{
 			ID:           3,
 			Name:         "Pink Floyd",
 			Members:      []string{"Roger Waters", "David Gilmour", "Nick Mason", "Richard Wright"},
 			CreationDate: 1965,
-			FirstAlbum:   "05-08-1967",
+			FirstAlbum:   models.MustParseDate("05-08-1967"),
 		},
 	}
 }
@@ -293,6 +295,176 @@ func TestSearchEngine_MatchPositions(t *testing.T) {
 	}
 }
 
+// TestSearchEngine_ConcurrentLoadAndSearch reproduit ArtistListView.preload :
+// les agrégats arrivent depuis une goroutine pendant que l'UI recherche.
+// À lancer avec -race.
+func TestSearchEngine_ConcurrentLoadAndSearch(t *testing.T) {
+	source := newStoreSource()
+	store := NewArtistStore(source)
+	store.SetArtists(source.Artists)
+	engine := NewSearchEngineWithStore(store)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, artist := range source.Artists {
+			if _, err := store.Aggregate(context.Background(), artist.ID); err != nil {
+				t.Errorf("Aggregate(%d): %v", artist.ID, err)
+			}
+		}
+	}()
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				results := engine.SearchByType("paris", SearchTypeLocation)
+				if len(results) > len(source.Artists) {
+					t.Errorf("Résultats incohérents: %d", len(results))
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if results := engine.SearchByType("paris", SearchTypeLocation); len(results) != len(source.Artists) {
+		t.Errorf("Après chargement, attendu %d résultats, got %d", len(source.Artists), len(results))
+	}
+}
+
+func TestSearchEngine_AccentInsensitive(t *testing.T) {
+	engine := NewSearchEngine([]models.Artist{
+		{ID: 1, Name: "Motörhead", Members: []string{"Lemmy Kilmister"}},
+		{ID: 2, Name: "Beyoncé"},
+		{ID: 3, Name: "Sigur Rós", Members: []string{"Jónsi Birgisson"}},
+	})
+
+	tests := []struct {
+		query string
+		id    int
+		match string
+	}{
+		{"motorhead", 1, "Motörhead"},
+		{"MOTÖR", 1, "Motör"},
+		{"beyonce", 2, "Beyoncé"},
+		{"ONCÉ", 2, "oncé"},
+		{"ros", 3, "Rós"},
+		{"jonsi", 3, "Jónsi"},
+	}
+
+	for _, tt := range tests {
+		results := engine.Search(tt.query)
+		if len(results) != 1 || results[0].ArtistID != tt.id {
+			t.Errorf("Search(%q): attendu l'artiste %d, got %+v", tt.query, tt.id, results)
+			continue
+		}
+		if _, match, _ := engine.HighlightMatch(results[0]); match != tt.match {
+			t.Errorf("Search(%q): surlignage %q, attendu %q", tt.query, match, tt.match)
+		}
+	}
+}
+
+func TestSearchEngine_HighlightRunes(t *testing.T) {
+	engine := NewSearchEngine([]models.Artist{{ID: 1, Name: "Mötley Crüe"}})
+
+	results := engine.Search("crue")
+	if len(results) != 1 {
+		t.Fatalf("Attendu 1 résultat, got %d", len(results))
+	}
+
+	// Positions en runes : "Mötley " fait 7 runes mais 8 octets
+	if results[0].MatchStart != 7 || results[0].MatchEnd != 11 {
+		t.Errorf("Positions attendues (7, 11), got (%d, %d)", results[0].MatchStart, results[0].MatchEnd)
+	}
+
+	before, match, after := engine.HighlightMatch(results[0])
+	if before != "Mötley " || match != "Crüe" || after != "" {
+		t.Errorf("HighlightMatch = %q | %q | %q", before, match, after)
+	}
+
+	// Positions hors limites : texte intact
+	if before, match, _ := engine.HighlightMatch(SearchResult{MatchedText: "Crüe", MatchStart: 2, MatchEnd: 5}); before != "Crüe" || match != "" {
+		t.Errorf("Positions invalides mal gérées: %q %q", before, match)
+	}
+}
+
+func TestFuzzyAndInitials_AccentInsensitive(t *testing.T) {
+	engine := NewSearchEngine([]models.Artist{
+		{ID: 1, Name: "Motörhead"},
+		{ID: 2, Name: "Émile Zola Band", Members: []string{"Éric Über"}},
+	})
+
+	fuzzy := NewFuzzySearchEngine(engine).FuzzySearch("motorhed", 2)
+	if len(fuzzy) != 1 || fuzzy[0].ArtistID != 1 || fuzzy[0].MatchEnd != 9 {
+		t.Errorf("FuzzySearch(motorhed): got %+v", fuzzy)
+	}
+	if distance := levenshteinDistance("motorhead", "Motörhead"); distance != 0 {
+		t.Errorf("Distance entre formes repliées identiques = %d", distance)
+	}
+
+	initials := NewInitialsSearchEngine(engine)
+	results := initials.SearchByInitials("eu")
+	if len(results) != 1 || results[0].MatchedText != "Éric Über" || results[0].MatchEnd != 9 {
+		t.Errorf("SearchByInitials(eu): got %+v", results)
+	}
+	if results := initials.SearchByInitials("ÉZ"); len(results) != 1 || results[0].ArtistID != 2 {
+		t.Errorf("SearchByInitials(ÉZ): got %+v", results)
+	}
+}
+
+func TestFuzzySearch_MultiToken(t *testing.T) {
+	fuzzy := NewFuzzySearchEngine(queryTestEngine())
+
+	tests := []struct {
+		query, expected, highlight string
+	}{
+		{"fredie mercuri", "Freddie Mercury", "Freddie Mercury"}, // une faute par mot
+		{"mercuri", "Freddie Mercury", "Mercury"},                // une partie du nom
+		{"mercury fredie", "Freddie Mercury", "Freddie Mercury"}, // ordre des mots libre
+		{"rogr tailor", "Roger Taylor", "Roger Taylor"},
+		{"los angelos", "Los Angeles, États-Unis", "Los Angeles"}, // lieux
+		{"liverpol", "Liverpool, Royaume-Uni", "Liverpool"},
+	}
+
+	for _, tt := range tests {
+		results := fuzzy.FuzzySearch(tt.query, 2)
+		if len(results) == 0 || results[0].MatchedText != tt.expected {
+			t.Errorf("FuzzySearch(%q): %q attendu en premier, got %+v", tt.query, tt.expected, results)
+			continue
+		}
+		if match := runeSlice(results[0].MatchedText, results[0].MatchStart, results[0].MatchEnd); match != tt.highlight {
+			t.Errorf("FuzzySearch(%q): surlignage %q, attendu %q", tt.query, match, tt.highlight)
+		}
+	}
+
+	// Budget par mot selon sa longueur : aucune faute sur un mot de 3 lettres
+	if results := fuzzy.FuzzySearch("mai", 2); len(results) != 0 {
+		t.Errorf("FuzzySearch(mai): aucun résultat attendu, got %+v", results)
+	}
+	if results := fuzzy.FuzzySearch("brain maj", 2); len(results) != 0 {
+		t.Errorf("FuzzySearch(brain maj): aucun résultat attendu, got %+v", results)
+	}
+}
+
+func TestBoundedLevenshtein(t *testing.T) {
+	words := []string{"", "queen", "qeen", "mercury", "mercuri", "freddie", "freddy", "kitten", "sitting", "abc", "cba"}
+
+	for _, a := range words {
+		for _, b := range words {
+			distance := levenshteinDistance(a, b)
+			for limit := 0; limit <= 3; limit++ {
+				want := min(distance, limit+1)
+				if got := boundedLevenshtein([]rune(a), []rune(b), limit); got != want {
+					t.Errorf("boundedLevenshtein(%q, %q, %d) = %d, attendu %d", a, b, limit, got, want)
+				}
+			}
+		}
+	}
+}
+
 // Benchmarks
 func BenchmarkSearchEngine_Search(b *testing.B) {
 	artists := createTestArtists()
@@ -321,4 +493,4 @@ func BenchmarkSearchEngine_CalculateScore(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		engine.calculateScore("Queen", "queen", 0, SearchTypeArtist)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultSearchDelay est l'attente après la dernière frappe avant de lancer
+// la recherche
+const DefaultSearchDelay = 150 * time.Millisecond
+
+// SearchResponse est le résultat d'une recherche du SearchWorker
+type SearchResponse struct {
+	Generation uint64 // numéro de la saisie ayant produit ces résultats
+	Query      string
+	Results    []RankedResult
+	Correction *Correction // "Vouliez-vous dire" quand Results est vide
+	Duration   time.Duration
+}
+
+// SearchWorker exécute la recherche pendant la frappe hors du thread UI.
+// Chaque saisie reçoit un numéro de génération croissant : la recherche ne
+// démarre qu'après delay sans nouvelle frappe, une saisie plus récente annule
+// la recherche en cours, et une réponse n'est livrée que si elle correspond
+// encore à la dernière saisie au moment de la livraison.
+type SearchWorker struct {
+	pipeline  *SearchPipeline
+	delay     time.Duration
+	limit     int
+	onResults func(SearchResponse)
+	dispatch  func(func()) // exécute la livraison (thread UI), appel direct par défaut
+	corrector *SpellCorrector
+
+	mu         sync.Mutex
+	generation uint64
+	timer      *time.Timer
+	cancel     context.CancelFunc
+	closed     bool
+}
+
+// NewSearchWorker crée un worker livrant au plus limit résultats à onResults
+func NewSearchWorker(pipeline *SearchPipeline, delay time.Duration, limit int, onResults func(SearchResponse)) *SearchWorker {
+	return &SearchWorker{
+		pipeline:  pipeline,
+		delay:     delay,
+		limit:     limit,
+		onResults: onResults,
+		dispatch:  func(fn func()) { fn() },
+	}
+}
+
+// SetDispatcher choisit comment les réponses sont livrées (fyne.Do pour le
+// thread UI)
+func (w *SearchWorker) SetDispatcher(dispatch func(func())) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.dispatch = dispatch
+}
+
+// SetCorrector active la correction orthographique des saisies sans résultat
+func (w *SearchWorker) SetCorrector(corrector *SpellCorrector) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.corrector = corrector
+}
+
+// Submit planifie la recherche de query et retourne sa génération. La
+// recherche précédente, en attente ou en cours, est abandonnée.
+func (w *SearchWorker) Submit(query string) uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopLocked()
+	w.generation++
+	if w.closed {
+		return w.generation
+	}
+
+	generation := w.generation
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.timer = time.AfterFunc(w.delay, func() {
+		w.run(ctx, generation, query)
+	})
+	return generation
+}
+
+// Cancel abandonne la recherche en attente ou en cours : aucune réponse
+// antérieure ne sera plus livrée (saisie effacée)
+func (w *SearchWorker) Cancel() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopLocked()
+	w.generation++
+	return w.generation
+}
+
+// Generation retourne le numéro de la dernière saisie
+func (w *SearchWorker) Generation() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.generation
+}
+
+// Close arrête le worker ; les saisies suivantes sont ignorées
+func (w *SearchWorker) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopLocked()
+	w.generation++
+	w.closed = true
+}
+
+// stopLocked arrête le minuteur et annule la recherche en cours (w.mu tenu)
+func (w *SearchWorker) stopLocked() {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+	}
+}
+
+// run exécute la recherche dans la goroutine du minuteur
+func (w *SearchWorker) run(ctx context.Context, generation uint64, query string) {
+	start := time.Now()
+	results, err := w.pipeline.SearchContext(ctx, query, w.limit)
+	if err != nil {
+		return // saisie périmée
+	}
+
+	w.mu.Lock()
+	dispatch, corrector := w.dispatch, w.corrector
+	w.mu.Unlock()
+
+	response := SearchResponse{
+		Generation: generation,
+		Query:      query,
+		Results:    results,
+	}
+	if len(results) == 0 && corrector != nil {
+		if correction, ok := corrector.Suggest(query); ok {
+			response.Correction = &correction
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+	response.Duration = time.Since(start)
+
+	dispatch(func() {
+		// Vérifié au moment de la livraison : une frappe a pu arriver entre
+		// la fin de la recherche et l'exécution sur le thread UI
+		if w.Generation() != generation {
+			return
+		}
+		w.onResults(response)
+	})
+}
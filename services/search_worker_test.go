@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestWorker(t *testing.T, delay time.Duration) (*SearchWorker, chan SearchResponse) {
+	t.Helper()
+	responses := make(chan SearchResponse, 10)
+	pipeline := NewSearchPipeline(newPipelineTestEngine(t))
+	worker := NewSearchWorker(pipeline, delay, 10, func(r SearchResponse) {
+		responses <- r
+	})
+	t.Cleanup(worker.Close)
+	return worker, responses
+}
+
+func waitResponse(t *testing.T, responses chan SearchResponse) SearchResponse {
+	t.Helper()
+	select {
+	case r := <-responses:
+		return r
+	case <-time.After(2 * time.Second):
+		t.Fatal("aucune réponse livrée")
+		return SearchResponse{}
+	}
+}
+
+func expectNoResponse(t *testing.T, responses chan SearchResponse, wait time.Duration) {
+	t.Helper()
+	select {
+	case r := <-responses:
+		t.Errorf("réponse inattendue: génération %d %q", r.Generation, r.Query)
+	case <-time.After(wait):
+	}
+}
+
+func TestSearchWorker_Debounce(t *testing.T) {
+	worker, responses := newTestWorker(t, 30*time.Millisecond)
+
+	var last uint64
+	for _, query := range []string{"q", "qu", "que", "quee", "queen"} {
+		last = worker.Submit(query)
+	}
+
+	r := waitResponse(t, responses)
+	if r.Generation != last || r.Query != "queen" {
+		t.Errorf("seule la dernière saisie doit être cherchée, got génération %d %q", r.Generation, r.Query)
+	}
+	if len(r.Results) == 0 || r.Results[0].MatchedText != "Queen" {
+		t.Errorf("Queen attendu, got %+v", r.Results)
+	}
+	expectNoResponse(t, responses, 100*time.Millisecond)
+}
+
+func TestSearchWorker_StaleResultsAreDropped(t *testing.T) {
+	worker, responses := newTestWorker(t, 0)
+
+	// File simulant le thread UI : les livraisons attendent d'y être exécutées
+	ui := make(chan func(), 10)
+	worker.SetDispatcher(func(fn func()) { ui <- fn })
+
+	worker.Submit("queen")
+	var stale func()
+	select {
+	case stale = <-ui:
+	case <-time.After(2 * time.Second):
+		t.Fatal("la première recherche n'a pas abouti")
+	}
+
+	// Nouvelle frappe avant que le thread UI ne traite la première réponse
+	latest := worker.Submit("beatles")
+	stale()
+	expectNoResponse(t, responses, 10*time.Millisecond)
+
+	select {
+	case fn := <-ui:
+		fn()
+	case <-time.After(2 * time.Second):
+		t.Fatal("la seconde recherche n'a pas abouti")
+	}
+	if r := waitResponse(t, responses); r.Generation != latest || r.Query != "beatles" {
+		t.Errorf("attendu la génération %d, got %d %q", latest, r.Generation, r.Query)
+	}
+}
+
+func TestSearchWorker_CancelAndClose(t *testing.T) {
+	worker, responses := newTestWorker(t, 20*time.Millisecond)
+
+	first := worker.Submit("queen")
+	if cancelled := worker.Cancel(); cancelled <= first {
+		t.Errorf("Cancel doit passer à une nouvelle génération: %d <= %d", cancelled, first)
+	}
+	expectNoResponse(t, responses, 80*time.Millisecond)
+
+	worker.Close()
+	worker.Submit("queen")
+	expectNoResponse(t, responses, 80*time.Millisecond)
+}
+
+func TestSearchPipeline_SearchContextCancelled(t *testing.T) {
+	pipeline := NewSearchPipeline(newPipelineTestEngine(t))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	results, err := pipeline.SearchContext(ctx, "queen", 10)
+	if !errors.Is(err, context.Canceled) || results != nil {
+		t.Errorf("recherche annulée attendue, got %v (%d résultats)", err, len(results))
+	}
+}
+
+func TestSearchWorker_DeliversCorrection(t *testing.T) {
+	worker, responses := newTestWorker(t, 0)
+	worker.SetCorrector(NewSpellCorrector(worker.pipeline.engine))
+
+	worker.Submit("mxrcxrx") // inconnu de tous les moteurs
+	r := waitResponse(t, responses)
+	if len(r.Results) != 0 || r.Correction == nil || r.Correction.Query != "mercury" {
+		t.Errorf("correction mercury attendue, got %+v (%+v)", r.Correction, r.Results)
+	}
+
+	worker.Submit("queen")
+	if r := waitResponse(t, responses); r.Correction != nil {
+		t.Errorf("pas de correction quand il y a des résultats, got %+v", r.Correction)
+	}
+}
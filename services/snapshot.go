@@ -0,0 +1,121 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"groupie-tracker/models"
+)
+
+// SnapshotVersion est incrémentée à chaque changement du format de fichier
+const SnapshotVersion = 1
+
+// Snapshot est une copie complète du jeu de données, utilisée pour
+// démarrer instantanément et pour fonctionner hors ligne
+type Snapshot struct {
+	Version    int                      `json:"version"`
+	SavedAt    time.Time                `json:"saved_at"`
+	Artists    []models.Artist          `json:"artists"`
+	Aggregates []models.ArtistAggregate `json:"aggregates"`
+}
+
+// NewSnapshot crée un snapshot à partir des artistes et de leurs données agrégées
+func NewSnapshot(artists []models.Artist, aggregates map[int]models.ArtistAggregate) *Snapshot {
+	list := make([]models.ArtistAggregate, 0, len(aggregates))
+	for _, aggregate := range aggregates {
+		list = append(list, aggregate)
+	}
+
+	// Ordre stable pour des fichiers comparables d'une sauvegarde à l'autre
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Artist.ID < list[j].Artist.ID
+	})
+
+	return &Snapshot{
+		Version:    SnapshotVersion,
+		SavedAt:    time.Now(),
+		Artists:    artists,
+		Aggregates: list,
+	}
+}
+
+// DefaultSnapshotPath retourne l'emplacement du snapshot (à côté de l'historique)
+func DefaultSnapshotPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".groupie-tracker", "snapshot.json")
+}
+
+// SaveSnapshot écrit le snapshot sur disque (écriture atomique)
+func SaveSnapshot(path string, snapshot *Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic écrit data dans path via un fichier temporaire unique
+// renommé ensuite : deux sauvegardes concurrentes ne partagent jamais le
+// même fichier temporaire et path n'est jamais lu à moitié écrit
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // sans effet une fois renommé
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadSnapshot lit un snapshot ; un fichier d'une autre version est refusé
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("snapshot illisible: %w", err)
+	}
+
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("version de snapshot %d non supportée (attendue %d)", snapshot.Version, SnapshotVersion)
+	}
+
+	return &snapshot, nil
+}
+
+// AggregateMap retourne les données agrégées indexées par ID d'artiste
+func (s *Snapshot) AggregateMap() map[int]models.ArtistAggregate {
+	aggregates := make(map[int]models.ArtistAggregate, len(s.Aggregates))
+	for _, aggregate := range s.Aggregates {
+		aggregates[aggregate.Artist.ID] = aggregate
+	}
+	return aggregates
+}
+
+// DataSource expose le snapshot comme une source de données en mémoire
+func (s *Snapshot) DataSource() *MemoryDataSource {
+	source := NewMemoryDataSource(s.Artists)
+	for _, aggregate := range s.Aggregates {
+		source.AddAggregate(aggregate)
+	}
+	return source
+}
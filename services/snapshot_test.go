@@ -0,0 +1,82 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"groupie-tracker/models"
+)
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	artists := createTestArtists()
+	aggregates := map[int]models.ArtistAggregate{
+		1: {
+			Artist:    artists[0],
+			Locations: models.Location{ID: 1, Locations: []string{"london-uk"}},
+			Dates:     models.ConcertDate{ID: 1, Dates: testDates("*12-07-1986")},
+			Relation:  models.Relation{ID: 1, DatesLocations: map[string][]models.Date{"london-uk": testDates("12-07-1986")}},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := SaveSnapshot(path, NewSnapshot(artists, aggregates)); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshot: %v", err)
+	}
+
+	if len(loaded.Artists) != len(artists) {
+		t.Errorf("Attendu %d artistes, got %d", len(artists), len(loaded.Artists))
+	}
+	if loaded.SavedAt.IsZero() {
+		t.Error("La date de sauvegarde devrait être renseignée")
+	}
+
+	// Le snapshot sert de source de données hors ligne
+	aggregate, err := AggregateArtistFrom(loaded.DataSource(), artists[0])
+	if err != nil {
+		t.Fatalf("AggregateArtistFrom: %v", err)
+	}
+	if aggregate.Relation.DatesLocations["london-uk"][0].String() != "12-07-1986" {
+		t.Errorf("Relation incorrecte: %+v", aggregate.Relation)
+	}
+}
+
+func TestLoadSnapshot_RejectsOtherVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	os.WriteFile(path, []byte(`{"version": 999, "artists": []}`), 0644)
+
+	if _, err := LoadSnapshot(path); err == nil {
+		t.Error("Une version inconnue devrait être refusée")
+	}
+}
+
+func TestSaveSnapshot_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	artists := createTestArtists()
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := SaveSnapshot(path, NewSnapshot(artists[:1+i%len(artists)], nil)); err != nil {
+				t.Errorf("SaveSnapshot: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err := LoadSnapshot(path); err != nil {
+		t.Fatalf("Le snapshot devrait être lisible après des sauvegardes concurrentes: %v", err)
+	}
+	files, _ := os.ReadDir(filepath.Dir(path))
+	if len(files) != 1 {
+		t.Errorf("Aucun fichier temporaire ne devrait rester, got %d fichiers", len(files))
+	}
+}
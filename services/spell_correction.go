@@ -0,0 +1,177 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// =====================
+// CORRECTION ORTHOGRAPHIQUE ("VOULIEZ-VOUS DIRE")
+// =====================
+//
+// Quand ni la recherche exacte ni la recherche floue ne trouvent rien, chaque
+// mot inconnu de la requête est rapproché des mots du vocabulaire indexé
+// (noms d'artistes, membres, villes) : "mrecuri" -> "mercury", "los angls"
+// -> "los angeles". Les combinaisons sont essayées de la plus proche à la plus
+// éloignée et seules celles qui donnent des résultats sont proposées.
+
+const (
+	minCorrectedWordLength = 3  // mots plus courts laissés tels quels
+	maxWordCorrections     = 3  // mots du vocabulaire essayés par mot inconnu
+	maxCorrectedWords      = 4  // mots inconnus corrigés au plus par requête
+	maxCorrectionQueries   = 30 // combinaisons vérifiées au plus
+)
+
+// Correction est une proposition "Vouliez-vous dire" pour une requête sans
+// résultat
+type Correction struct {
+	Query    string // requête corrigée, prête à être saisie
+	Distance int    // nombre total de modifications
+	Results  int    // nombre de résultats de la requête corrigée
+}
+
+// wordCorrection est un mot du vocabulaire proche d'un mot saisi
+type wordCorrection struct {
+	word     string
+	distance int
+	count    int // fréquence du mot dans l'index
+}
+
+// SpellCorrector propose des corrections à partir du vocabulaire du
+// SearchEngine
+type SpellCorrector struct {
+	baseEngine *SearchEngine
+	fuzzy      *FuzzySearchEngine
+}
+
+// NewSpellCorrector crée un correcteur orthographique
+func NewSpellCorrector(baseEngine *SearchEngine) *SpellCorrector {
+	return &SpellCorrector{
+		baseEngine: baseEngine,
+		fuzzy:      NewFuzzySearchEngine(baseEngine),
+	}
+}
+
+// Suggest retourne la meilleure correction de query, s'il y en a une
+func (sc *SpellCorrector) Suggest(query string) (Correction, bool) {
+	corrections := sc.Suggestions(query, 1)
+	if len(corrections) == 0 {
+		return Correction{}, false
+	}
+	return corrections[0], true
+}
+
+// Suggestions retourne au plus limit corrections de query, la plus proche
+// d'abord. Rien n'est proposé si la recherche exacte ou floue trouve déjà des
+// résultats, ni pour une requête structurée.
+func (sc *SpellCorrector) Suggestions(query string, limit int) []Correction {
+	words := strings.Fields(query)
+	if len(words) == 0 || limit <= 0 {
+		return nil
+	}
+	if parsed, err := ParseQuery(query); err == nil && parsed.Structured() {
+		return nil
+	}
+	if len(sc.fuzzy.FuzzySearch(query, 2)) > 0 {
+		return nil
+	}
+
+	options := sc.wordOptions(words)
+	if options == nil {
+		return nil
+	}
+
+	corrections := []Correction{}
+	for _, candidate := range combineCorrections(options) {
+		if candidate.distance == 0 {
+			continue // requête d'origine
+		}
+		results := sc.baseEngine.Search(candidate.word)
+		if len(results) == 0 {
+			continue
+		}
+		corrections = append(corrections, Correction{
+			Query:    candidate.word,
+			Distance: candidate.distance,
+			Results:  len(results),
+		})
+		if len(corrections) == limit {
+			break
+		}
+	}
+	return corrections
+}
+
+// wordOptions retourne, pour chaque mot saisi, les mots possibles : le mot
+// lui-même s'il est connu (ou trop court pour être corrigé), sinon les mots
+// proches du vocabulaire. Retourne nil si aucun mot n'a de correction.
+func (sc *SpellCorrector) wordOptions(words []string) [][]wordCorrection {
+	sc.baseEngine.mu.RLock()
+	defer sc.baseEngine.mu.RUnlock()
+
+	options := make([][]wordCorrection, len(words))
+	corrected := 0
+	for i, word := range words {
+		options[i] = []wordCorrection{{word: word}}
+
+		key := looseText(word)
+		length := utf8.RuneCountInString(key)
+		if length < minCorrectedWordLength || strings.ContainsFunc(key, unicode.IsDigit) ||
+			sc.baseEngine.index.knownWord(key) {
+			continue
+		}
+
+		// Une faute de plus que la recherche floue : la correction n'est
+		// qu'une proposition
+		maxDistance := tokenEditBudget(key, 2) + 1
+		if closest := sc.baseEngine.index.closestWords(key, maxDistance, maxWordCorrections); len(closest) > 0 {
+			options[i] = closest
+			corrected++
+		}
+	}
+
+	if corrected == 0 || corrected > maxCorrectedWords {
+		return nil
+	}
+	return options
+}
+
+// combineCorrections construit les requêtes corrigées possibles (dans
+// wordCorrection.word), triées par distance totale croissante puis par
+// fréquence décroissante
+func combineCorrections(options [][]wordCorrection) []wordCorrection {
+	combinations := []wordCorrection{{}}
+	for _, choices := range options {
+		next := make([]wordCorrection, 0, len(combinations)*len(choices))
+		for _, prefix := range combinations {
+			for _, choice := range choices {
+				word := choice.word
+				if prefix.word != "" {
+					word = prefix.word + " " + word
+				}
+				next = append(next, wordCorrection{
+					word:     word,
+					distance: prefix.distance + choice.distance,
+					count:    prefix.count + choice.count,
+				})
+			}
+		}
+		combinations = next
+	}
+
+	sort.Slice(combinations, func(i, j int) bool {
+		if combinations[i].distance != combinations[j].distance {
+			return combinations[i].distance < combinations[j].distance
+		}
+		if combinations[i].count != combinations[j].count {
+			return combinations[i].count > combinations[j].count
+		}
+		return combinations[i].word < combinations[j].word
+	})
+	if len(combinations) > maxCorrectionQueries {
+		combinations = combinations[:maxCorrectionQueries]
+	}
+	return combinations
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+
+	"groupie-tracker/models"
+)
+
+func TestSpellCorrector_Suggest(t *testing.T) {
+	engine := queryTestEngine()
+	corrector := NewSpellCorrector(engine)
+
+	tests := []struct {
+		query, expected string
+	}{
+		{"mrecuri", "mercury"},
+		{"hmbrg", "hamburg"},
+		{"los angls", "los angeles"}, // un seul mot mal orthographié
+		{"Brln", "berlin"},
+	}
+
+	for _, tt := range tests {
+		correction, ok := corrector.Suggest(tt.query)
+		if !ok || correction.Query != tt.expected {
+			t.Errorf("Suggest(%q) = %+v %v, attendu %q", tt.query, correction, ok, tt.expected)
+			continue
+		}
+		if correction.Results == 0 || len(engine.Search(correction.Query)) != correction.Results {
+			t.Errorf("Suggest(%q): la correction doit donner des résultats, got %+v", tt.query, correction)
+		}
+	}
+}
+
+func TestSpellCorrector_MultiWordKeepsCorrectWords(t *testing.T) {
+	engine := newPipelineTestEngine(t,
+		models.Artist{ID: 4, Name: "Red Hot Chili Peppers"},
+		models.Artist{ID: 5, Name: "Mötley Crüe"},
+	)
+	corrector := NewSpellCorrector(engine)
+
+	tests := []struct {
+		query, expected string
+	}{
+		{"chlii Peppers", "chili Peppers"}, // le mot correct est gardé tel quel
+		{"hot chlii pepprs", "hot chili peppers"},
+		{"mxtlxx", "mötley"}, // forme affichée du vocabulaire
+	}
+
+	for _, tt := range tests {
+		if correction, ok := corrector.Suggest(tt.query); !ok || correction.Query != tt.expected {
+			t.Errorf("Suggest(%q) = %+v %v, attendu %q", tt.query, correction, ok, tt.expected)
+		}
+	}
+}
+
+func TestSpellCorrector_NoSuggestion(t *testing.T) {
+	corrector := NewSpellCorrector(queryTestEngine())
+
+	for _, query := range []string{
+		"",
+		"queen",          // résultat exact
+		"qeen",           // résultat flou
+		"mercuri",        // résultat flou mot à mot
+		"xq",             // trop court
+		"zzzzzzzz",       // rien d'approchant
+		"member:mercuri", // requête structurée
+		"1987",
+	} {
+		if corrections := corrector.Suggestions(query, 3); len(corrections) != 0 {
+			t.Errorf("Suggestions(%q): aucune correction attendue, got %+v", query, corrections)
+		}
+	}
+}
+
+func TestSpellCorrector_FollowsStoreUpdates(t *testing.T) {
+	store := NewArtistStore(newStoreSource())
+	store.Replace(createTestArtists(), nil)
+	engine := NewSearchEngineWithStore(store)
+	t.Cleanup(engine.Close)
+	corrector := NewSpellCorrector(engine)
+
+	if correction, _ := corrector.Suggest("brln"); correction.Query == "berlin" {
+		t.Fatal("aucune ville indexée avant le chargement des lieux")
+	}
+
+	artists := createTestArtists()
+	store.Replace(artists, map[int]models.ArtistAggregate{
+		3: {Artist: artists[2], Locations: models.Location{Locations: []string{"berlin-germany"}}},
+	})
+	if correction, ok := corrector.Suggest("brln"); !ok || correction.Query != "berlin" {
+		t.Errorf("berlin attendu après publication, got %+v %v", correction, ok)
+	}
+}
@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"groupie-tracker/api"
+	"groupie-tracker/models"
+)
+
+// StoreChangeKind indique ce qui a changé dans un ArtistStore
+type StoreChangeKind int
+
+const (
+	StoreArtistsChanged    StoreChangeKind = iota // liste d'artistes chargée ou remplacée
+	StoreAggregatesChanged                        // données agrégées ajoutées
+)
+
+// StoreChange est transmis aux abonnés d'un ArtistStore
+type StoreChange struct {
+	Kind      StoreChangeKind
+	ArtistIDs []int // artistes concernés (vide = tous)
+}
+
+// StoreState est un état figé du store. Il n'est jamais modifié après
+// publication (copy-on-write) : un lecteur peut le parcourir sans verrou et
+// voit un ensemble cohérent même si un chargement est en cours.
+type StoreState struct {
+	Artists    []models.Artist
+	Aggregates map[int]models.ArtistAggregate
+
+	concertsOnce sync.Once
+	concerts     []models.Concert
+}
+
+// Aggregate retourne les données agrégées d'un artiste dans cet état
+func (st *StoreState) Aggregate(id int) (models.ArtistAggregate, bool) {
+	aggregate, ok := st.Aggregates[id]
+	return aggregate, ok
+}
+
+// Concerts retourne tous les concerts de cet état, triés par date.
+// La liste est calculée au premier appel puis partagée (ne pas la modifier).
+func (st *StoreState) Concerts() []models.Concert {
+	st.concertsOnce.Do(func() {
+		st.concerts = FlattenConcerts(st.Aggregates)
+	})
+	return st.concerts
+}
+
+// ArtistStore est le point d'accès unique aux données : chaque artiste et
+// chaque agrégat n'est chargé qu'une fois depuis la source, puis partagé
+// entre les moteurs et les vues. Il est sûr pour un usage concurrent.
+//
+// ArtistStore implémente DataSource : on peut le passer partout où une
+// source est attendue pour profiter de la mémorisation.
+type ArtistStore struct {
+	mu          sync.RWMutex
+	source      DataSource
+	state       *StoreState // remplacé en bloc à chaque écriture
+	loaded      bool
+	loading     map[int]chan struct{} // agrégats en cours de chargement
+	subscribers map[int]func(StoreChange)
+	nextSubID   int
+
+	// artistsMu sérialise le premier chargement de la liste d'artistes
+	artistsMu sync.Mutex
+}
+
+// NewArtistStore crée un store vide qui chargera ses données depuis source
+func NewArtistStore(source DataSource) *ArtistStore {
+	return &ArtistStore{
+		source:      source,
+		state:       &StoreState{Aggregates: map[int]models.ArtistAggregate{}},
+		loading:     make(map[int]chan struct{}),
+		subscribers: make(map[int]func(StoreChange)),
+	}
+}
+
+// Source retourne la source utilisée pour les chargements
+func (s *ArtistStore) Source() DataSource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.source
+}
+
+// SetSource change la source utilisée pour les prochains chargements
+func (s *ArtistStore) SetSource(source DataSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.source = source
+}
+
+// State retourne l'état courant, à lire sans le modifier
+func (s *ArtistStore) State() *StoreState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// publish remplace l'état courant (s.mu doit être verrouillé en écriture).
+// artists == nil conserve la liste actuelle ; les agrégats sont fusionnés.
+// Une nouvelle liste d'artistes écarte les agrégats des artistes disparus.
+func (s *ArtistStore) publish(artists []models.Artist, aggregates map[int]models.ArtistAggregate) {
+	next := &StoreState{Artists: s.state.Artists, Aggregates: s.state.Aggregates}
+	if artists != nil {
+		next.Artists = artists
+		s.loaded = true
+	}
+
+	if len(aggregates) > 0 || artists != nil {
+		var current map[int]bool
+		if artists != nil {
+			current = make(map[int]bool, len(artists))
+			for _, artist := range artists {
+				current[artist.ID] = true
+			}
+		}
+
+		next.Aggregates = make(map[int]models.ArtistAggregate, len(s.state.Aggregates)+len(aggregates))
+		for _, merged := range []map[int]models.ArtistAggregate{s.state.Aggregates, aggregates} {
+			for id, aggregate := range merged {
+				if current == nil || current[id] {
+					next.Aggregates[id] = aggregate
+				}
+			}
+		}
+	}
+
+	s.state = next
+}
+
+// =====================
+// ARTISTES
+// =====================
+
+// Artists retourne la liste des artistes, chargée au premier appel.
+// La slice retournée est partagée : ne pas la modifier.
+func (s *ArtistStore) Artists(ctx context.Context) ([]models.Artist, error) {
+	if artists, ok := s.cachedArtists(); ok {
+		return artists, nil
+	}
+
+	s.artistsMu.Lock()
+	defer s.artistsMu.Unlock()
+
+	// Un autre appel a pu charger la liste pendant qu'on attendait
+	if artists, ok := s.cachedArtists(); ok {
+		return artists, nil
+	}
+
+	artists, err := s.Source().GetArtists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if artists == nil {
+		artists = []models.Artist{}
+	}
+
+	s.mu.Lock()
+	s.publish(artists, nil)
+	s.mu.Unlock()
+
+	s.notify(StoreChange{Kind: StoreArtistsChanged})
+	return artists, nil
+}
+
+// Loaded indique si la liste d'artistes est en mémoire
+func (s *ArtistStore) Loaded() bool {
+	_, ok := s.cachedArtists()
+	return ok
+}
+
+// CachedArtists retourne les artistes déjà chargés, sans requête
+func (s *ArtistStore) CachedArtists() []models.Artist {
+	artists, _ := s.cachedArtists()
+	return artists
+}
+
+func (s *ArtistStore) cachedArtists() ([]models.Artist, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state.Artists, s.loaded
+}
+
+// Artist retourne un artiste par son ID
+func (s *ArtistStore) Artist(ctx context.Context, id int) (models.Artist, error) {
+	artists, err := s.Artists(ctx)
+	if err != nil {
+		return models.Artist{}, err
+	}
+
+	for _, artist := range artists {
+		if artist.ID == id {
+			return artist, nil
+		}
+	}
+	return models.Artist{}, fmt.Errorf("artiste #%d: %w", id, api.ErrNotFound)
+}
+
+// SetArtists remplace la liste d'artistes (sans toucher aux agrégats)
+func (s *ArtistStore) SetArtists(artists []models.Artist) {
+	if artists == nil {
+		artists = []models.Artist{}
+	}
+
+	s.mu.Lock()
+	s.publish(artists, nil)
+	s.mu.Unlock()
+
+	s.notify(StoreChange{Kind: StoreArtistsChanged})
+}
+
+// =====================
+// AGRÉGATS
+// =====================
+
+// Aggregate retourne les données agrégées d'un artiste, chargées une seule
+// fois même si plusieurs goroutines les demandent en même temps
+func (s *ArtistStore) Aggregate(ctx context.Context, id int) (models.ArtistAggregate, error) {
+	for {
+		s.mu.Lock()
+		if aggregate, ok := s.state.Aggregates[id]; ok {
+			s.mu.Unlock()
+			return aggregate, nil
+		}
+
+		// Chargement déjà en cours ailleurs : on attend son résultat
+		if wait, ok := s.loading[id]; ok {
+			s.mu.Unlock()
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return models.ArtistAggregate{}, ctx.Err()
+			}
+		}
+
+		done := make(chan struct{})
+		s.loading[id] = done
+		s.mu.Unlock()
+
+		aggregate, err := s.loadAggregate(ctx, id)
+
+		s.mu.Lock()
+		delete(s.loading, id)
+		if err == nil {
+			s.publish(nil, map[int]models.ArtistAggregate{id: aggregate})
+		}
+		s.mu.Unlock()
+		close(done)
+
+		if err != nil {
+			return models.ArtistAggregate{}, err
+		}
+
+		s.notify(StoreChange{Kind: StoreAggregatesChanged, ArtistIDs: []int{id}})
+		return aggregate, nil
+	}
+}
+
+func (s *ArtistStore) loadAggregate(ctx context.Context, id int) (models.ArtistAggregate, error) {
+	artist, err := s.Artist(ctx, id)
+	if err != nil {
+		return models.ArtistAggregate{}, err
+	}
+	return AggregateArtistContext(ctx, s.Source(), artist)
+}
+
+// CachedAggregate retourne les données agrégées déjà chargées, sans requête
+func (s *ArtistStore) CachedAggregate(id int) (models.ArtistAggregate, bool) {
+	return s.State().Aggregate(id)
+}
+
+// Aggregates retourne une copie de toutes les données agrégées chargées
+func (s *ArtistStore) Aggregates() map[int]models.ArtistAggregate {
+	state := s.State()
+
+	aggregates := make(map[int]models.ArtistAggregate, len(state.Aggregates))
+	for id, aggregate := range state.Aggregates {
+		aggregates[id] = aggregate
+	}
+	return aggregates
+}
+
+// AddAggregates enregistre des données agrégées déjà chargées
+func (s *ArtistStore) AddAggregates(aggregates map[int]models.ArtistAggregate) {
+	if len(aggregates) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	s.publish(nil, aggregates)
+	s.mu.Unlock()
+
+	s.notify(StoreChange{Kind: StoreAggregatesChanged, ArtistIDs: sortedIDs(aggregates)})
+}
+
+// Complete indique si les artistes et tous leurs agrégats sont en mémoire
+func (s *ArtistStore) Complete() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.loaded {
+		return false
+	}
+	for _, artist := range s.state.Artists {
+		if _, ok := s.state.Aggregates[artist.ID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadAll charge (en groupé si possible) les agrégats encore manquants
+func (s *ArtistStore) LoadAll(ctx context.Context) error {
+	artists, err := s.Artists(ctx)
+	if err != nil {
+		return err
+	}
+
+	state := s.State()
+	missing := []models.Artist{}
+	for _, artist := range artists {
+		if _, ok := state.Aggregate(artist.ID); !ok {
+			missing = append(missing, artist)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	aggregates, err := LoadAllAggregatesContext(ctx, s.Source(), missing)
+	s.AddAggregates(aggregates)
+	return err
+}
+
+// Replace remplace la liste d'artistes et complète les agrégats : ceux
+// absents de la nouvelle version sont conservés pour les artistes toujours
+// présents, écartés pour les artistes disparus
+func (s *ArtistStore) Replace(artists []models.Artist, aggregates map[int]models.ArtistAggregate) {
+	if artists == nil {
+		artists = []models.Artist{}
+	}
+
+	s.mu.Lock()
+	s.publish(artists, aggregates)
+	s.mu.Unlock()
+
+	s.notify(StoreChange{Kind: StoreArtistsChanged})
+}
+
+// =====================
+// ABONNEMENTS
+// =====================
+
+// Subscribe enregistre fn, appelée après chaque changement (depuis la
+// goroutine qui a provoqué le changement). Retourne la fonction de désabonnement.
+func (s *ArtistStore) Subscribe(fn func(StoreChange)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextSubID
+	s.nextSubID++
+	s.subscribers[id] = fn
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers, id)
+	}
+}
+
+func (s *ArtistStore) notify(change StoreChange) {
+	s.mu.RLock()
+	subscribers := make([]func(StoreChange), 0, len(s.subscribers))
+	for _, fn := range s.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	s.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(change)
+	}
+}
+
+func sortedIDs(aggregates map[int]models.ArtistAggregate) []int {
+	ids := make([]int, 0, len(aggregates))
+	for id := range aggregates {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// =====================
+// DATASOURCE
+// =====================
+
+func (s *ArtistStore) GetArtists(ctx context.Context) ([]models.Artist, error) {
+	return s.Artists(ctx)
+}
+
+func (s *ArtistStore) GetLocation(ctx context.Context, id int) (models.Location, error) {
+	aggregate, err := s.Aggregate(ctx, id)
+	return aggregate.Locations, err
+}
+
+func (s *ArtistStore) GetDate(ctx context.Context, id int) (models.ConcertDate, error) {
+	aggregate, err := s.Aggregate(ctx, id)
+	return aggregate.Dates, err
+}
+
+func (s *ArtistStore) GetRelation(ctx context.Context, id int) (models.Relation, error) {
+	aggregate, err := s.Aggregate(ctx, id)
+	return aggregate.Relation, err
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"groupie-tracker/api"
+	"groupie-tracker/models"
+)
+
+// storeSource compte les appels à la source (compteurs sûrs en concurrence)
+type storeSource struct {
+	*MemoryDataSource
+	artistCalls   atomic.Int32
+	locationCalls atomic.Int32
+}
+
+func (s *storeSource) GetArtists(ctx context.Context) ([]models.Artist, error) {
+	s.artistCalls.Add(1)
+	return s.MemoryDataSource.GetArtists(ctx)
+}
+
+func (s *storeSource) GetLocation(ctx context.Context, id int) (models.Location, error) {
+	s.locationCalls.Add(1)
+	return s.MemoryDataSource.GetLocation(ctx, id)
+}
+
+func newStoreSource() *storeSource {
+	return &storeSource{MemoryDataSource: newCountingSource().MemoryDataSource}
+}
+
+func TestArtistStore_LoadsOnce(t *testing.T) {
+	source := newStoreSource()
+	store := NewArtistStore(source)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.Aggregate(context.Background(), 1); err != nil {
+				t.Errorf("Aggregate: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := source.artistCalls.Load(); n != 1 {
+		t.Errorf("Les artistes devraient être chargés une fois, got %d", n)
+	}
+	if n := source.locationCalls.Load(); n != 1 {
+		t.Errorf("L'agrégat devrait être chargé une fois, got %d", n)
+	}
+}
+
+func TestArtistStore_LoadAllUsesIndexes(t *testing.T) {
+	source := newStoreSource()
+	store := NewArtistStore(source)
+
+	if err := store.LoadAll(context.Background()); err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if !store.Complete() {
+		t.Error("Le store devrait être complet après LoadAll")
+	}
+	if n := source.locationCalls.Load(); n != 0 {
+		t.Errorf("Aucun appel par artiste attendu, got %d", n)
+	}
+
+	// Les agrégats sont ensuite servis depuis la mémoire
+	if _, err := store.Aggregate(context.Background(), 2); err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+	if n := source.locationCalls.Load(); n != 0 {
+		t.Errorf("L'agrégat devrait venir du store, got %d appels", n)
+	}
+}
+
+func TestArtistStore_UnknownArtist(t *testing.T) {
+	store := NewArtistStore(newStoreSource())
+
+	if _, err := store.Aggregate(context.Background(), 999); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("Attendu api.ErrNotFound, got %v", err)
+	}
+}
+
+func TestArtistStore_Subscribe(t *testing.T) {
+	store := NewArtistStore(newStoreSource())
+
+	changes := []StoreChange{}
+	unsubscribe := store.Subscribe(func(change StoreChange) {
+		changes = append(changes, change)
+	})
+
+	store.Aggregate(context.Background(), 1)
+
+	if len(changes) != 2 {
+		t.Fatalf("Attendu 2 notifications (artistes puis agrégat), got %+v", changes)
+	}
+	if changes[0].Kind != StoreArtistsChanged {
+		t.Errorf("Première notification inattendue: %+v", changes[0])
+	}
+	if changes[1].Kind != StoreAggregatesChanged || len(changes[1].ArtistIDs) != 1 || changes[1].ArtistIDs[0] != 1 {
+		t.Errorf("Seconde notification inattendue: %+v", changes[1])
+	}
+
+	unsubscribe()
+	store.Aggregate(context.Background(), 2)
+	if len(changes) != 2 {
+		t.Error("Aucune notification après désabonnement")
+	}
+}
+
+func TestArtistStore_ReplaceKeepsMissingAggregates(t *testing.T) {
+	source := newStoreSource()
+	store := NewArtistStore(source)
+	if err := store.LoadAll(context.Background()); err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+
+	// Nouvelle version partielle : seul l'artiste 1 est fourni
+	fresh := source.Artists[0]
+	fresh.Name = "Queen (remastered)"
+	store.Replace(source.Artists, map[int]models.ArtistAggregate{1: {Artist: fresh}})
+
+	if aggregate, _ := store.CachedAggregate(1); aggregate.Artist.Name != fresh.Name {
+		t.Errorf("L'agrégat 1 devrait être remplacé, got %q", aggregate.Artist.Name)
+	}
+	if _, ok := store.CachedAggregate(2); !ok {
+		t.Error("Les agrégats absents de la nouvelle version devraient être conservés")
+	}
+	if !store.Complete() {
+		t.Error("Le store devrait rester complet")
+	}
+
+	// Un artiste remplacé par un autre : même nombre d'artistes, mais le
+	// nouveau n'a pas encore d'agrégat et celui du disparu est écarté
+	removed := source.Artists[len(source.Artists)-1]
+	artists := append([]models.Artist{}, source.Artists[:len(source.Artists)-1]...)
+	artists = append(artists, models.Artist{ID: 99, Name: "Nouvel artiste"})
+	store.Replace(artists, nil)
+
+	if store.Complete() {
+		t.Error("Le store ne doit pas être complet sans l'agrégat du nouvel artiste")
+	}
+	if _, ok := store.CachedAggregate(removed.ID); ok {
+		t.Error("L'agrégat d'un artiste disparu devrait être écarté")
+	}
+	if _, ok := store.CachedAggregate(2); !ok {
+		t.Error("Les agrégats des artistes toujours présents devraient être conservés")
+	}
+}
+
+func TestArtistStore_StateIsImmutable(t *testing.T) {
+	store := NewArtistStore(newStoreSource())
+	store.SetArtists(createTestArtists())
+
+	before := store.State()
+	store.AddAggregates(map[int]models.ArtistAggregate{1: {}})
+
+	if len(before.Aggregates) != 0 {
+		t.Error("Un état déjà publié ne doit pas être modifié")
+	}
+	if _, ok := store.State().Aggregate(1); !ok {
+		t.Error("Le nouvel état devrait contenir l'agrégat")
+	}
+}
@@ -3,15 +3,22 @@ package services
 import (
     "strings"
     "time"
+
+    "groupie-tracker/models"
 )
 
 // ParseDate convertit une date au format "*dd-mm-yyyy" en time.Time
+// (voir models.ParseDate pour conserver le marqueur "*")
 func ParseDate(s string) (time.Time, error) {
-    s = strings.TrimPrefix(s, "*") // supprime l'éventuel *
-    return time.Parse("02-01-2006", s)
+    date, err := models.ParseDate(s)
+    return date.Time, err
 }
 
 // ParseLocation sépare une location de type "city-country" en ville et pays
+// bruts (minuscules, "_" remplacés par des espaces). Le pays est toujours le
+// dernier segment, la ville regroupe les précédents.
+//
+// Deprecated: utiliser ParsePlace, qui normalise le pays (code ISO, nom affiché).
 func ParseLocation(s string) (city, country string) {
 	if s == "" {
 		return "", ""
@@ -22,8 +29,8 @@ func ParseLocation(s string) (city, country string) {
 		return "", ""
 	}
 
-	city = strings.ReplaceAll(parts[0], "_", " ")
-	country = strings.ReplaceAll(parts[1], "_", " ")
+	city = strings.ReplaceAll(strings.Join(parts[:len(parts)-1], "-"), "_", " ")
+	country = strings.ReplaceAll(parts[len(parts)-1], "_", " ")
 
 	city = strings.TrimSpace(city)
 	country = strings.TrimSpace(country)
@@ -32,27 +39,16 @@ func ParseLocation(s string) (city, country string) {
 }
 
 
-// FormatDate convertit une date du format "DD-MM-YYYY" ou "*DD-MM-YYYY" au format "JJ/MM/AAAA"
-func FormatDate(dateStr string) string {
-	if dateStr == "" {
+// FormatDate formate une date de l'API au format "JJ/MM/AAAA" (vide si absente)
+func FormatDate(date models.Date) string {
+	if date.IsZero() {
 		return ""
 	}
-	
-	// Retirer l'éventuel astérisque
-	dateStr = strings.TrimPrefix(dateStr, "*")
-	
-	// Parser la date
-	t, err := ParseDate(dateStr)
-	if err != nil {
-		return dateStr // Retourner tel quel si format invalide
-	}
-	
-	// Reformater en JJ/MM/AAAA
-	return t.Format("02/01/2006")
+	return date.Time.Format("02/01/2006")
 }
 
 // FormatDateList formate une liste de dates
-func FormatDateList(dates []string) []string {
+func FormatDateList(dates []models.Date) []string {
 	formatted := make([]string, len(dates))
 	for i, date := range dates {
 		formatted[i] = FormatDate(date)
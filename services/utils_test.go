@@ -3,8 +3,19 @@ package services
 import (
     "testing"
     "time"
+
+    "groupie-tracker/models"
 )
 
+// testDates construit des dates typées ("" donne une date absente)
+func testDates(values ...string) []models.Date {
+    dates := make([]models.Date, len(values))
+    for i, value := range values {
+        dates[i] = models.MustParseDate(value)
+    }
+    return dates
+}
+
 func TestParseDate(t *testing.T) {
     input := "*23-08-2019"
     expected := time.Date(2019, 8, 23, 0, 0, 0, 0, time.UTC)
@@ -24,3 +35,12 @@ func TestParseLocation(t *testing.T) {
         t.Errorf("ParseLocation = (%s, %s); want (los angeles, usa)", city, country)
     }
 }
+
+func TestFormatDate(t *testing.T) {
+    if got := FormatDate(models.MustParseDate("*23-08-2019")); got != "23/08/2019" {
+        t.Errorf("FormatDate = %q; want 23/08/2019", got)
+    }
+    if got := FormatDate(models.Date{}); got != "" {
+        t.Errorf("FormatDate(date absente) = %q; want \"\"", got)
+    }
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"fmt"
+	"groupie-tracker/services"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showAliasEditor ouvre la fenêtre d'édition des alias de recherche.
+// onChange est appelée après chaque ajout ou suppression.
+func showAliasEditor(aliases *services.AliasDictionary, onChange func()) {
+	window := fyne.CurrentApp().NewWindow("🏷️ Alias de recherche")
+	window.Resize(fyne.NewSize(520, 440))
+	window.CenterOnScreen()
+
+	entries := aliases.All()
+	var list *widget.List
+	list = widget.NewList(
+		func() int {
+			return len(entries)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewButton("🗑️", nil), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			alias := entries[id]
+			row.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s → %s", alias.Alias, alias.Target))
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				if err := aliases.Remove(alias.Alias); err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				entries = aliases.All()
+				list.Refresh()
+				onChange()
+			}
+		},
+	)
+
+	aliasEntry := widget.NewEntry()
+	aliasEntry.SetPlaceHolder("Alias (ex. rhcp)")
+	targetEntry := widget.NewEntry()
+	targetEntry.SetPlaceHolder("Cible (ex. Red Hot Chili Peppers)")
+
+	addButton := widget.NewButton("➕ Ajouter", func() {
+		if err := aliases.Set(aliasEntry.Text, targetEntry.Text); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		aliasEntry.SetText("")
+		targetEntry.SetText("")
+		entries = aliases.All()
+		list.Refresh()
+		onChange()
+	})
+
+	form := container.NewBorder(nil, nil, nil, addButton, container.NewGridWithColumns(2, aliasEntry, targetEntry))
+	hint := widget.NewLabel("Un alias recherche aussi sa cible : nom d'artiste, de membre ou de lieu.")
+	hint.Wrapping = fyne.TextWrapWord
+
+	window.SetContent(container.NewBorder(container.NewVBox(hint, form), nil, nil, nil, list))
+	window.Show()
+}
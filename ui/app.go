@@ -14,6 +14,12 @@ type App struct {
 	// Navigation
 	currentView fyne.CanvasObject
 	
+	// Données partagées par toutes les vues (chargées une seule fois)
+	store *services.ArtistStore
+
+	// Historique des versions du jeu de données (vue "Nouveautés")
+	history *services.DatasetHistory
+
 	// Managers
 	favoritesManager *services.FavoritesManager
 	imageCache       *services.ImageCache
@@ -22,7 +28,7 @@ type App struct {
 	listView *ArtistListView
 }
 
-func NewApp() *App {
+func NewApp(source services.DataSource) *App {
 	a := app.New()
 	
 	// Appliquer le thème violet foncé
@@ -36,6 +42,8 @@ func NewApp() *App {
 	appInstance := &App{
 		FyneApp:          a,
 		Window:           w,
+		store:            services.NewArtistStore(source),
+		history:          services.NewDatasetHistory(services.DefaultDatasetHistoryDir(), 10),
 		favoritesManager: services.NewFavoritesManager(),
 		imageCache:       services.NewImageCache(),
 	}
@@ -63,19 +71,27 @@ func NewApp() *App {
 }
 
 func (a *App) ShowArtistList() {
-	// Nettoyer l'ancienne vue si elle existe
+	// La vue liste est conservée entre deux navigations (données déjà chargées)
+	if a.listView != nil && a.listView.Ready() {
+		a.currentView = a.listView.Container
+		a.Window.SetContent(a.currentView)
+		return
+	}
+
+	// Nettoyer l'ancienne vue (en erreur) si elle existe
 	if a.listView != nil {
 		a.listView.Cleanup()
 	}
 	
 	// Créer la nouvelle vue
-	a.listView = NewArtistListView(a.ShowArtistDetails, a.favoritesManager, a.imageCache, a.ShowFavorites)
+	a.listView = NewArtistListView(a.store, a.history, a.ShowArtistDetails, a.favoritesManager, a.imageCache, a.ShowFavorites, a.ShowWhatsNew, a.ShowArtistList)
 	a.currentView = a.listView.Container
 	a.Window.SetContent(a.currentView)
 }
 
 func (a *App) ShowArtistDetails(artistID int) {
-	detailsView := NewArtistDetailsView(artistID, a.ShowArtistList, a.favoritesManager)
+	retry := func() { a.ShowArtistDetails(artistID) }
+	detailsView := NewArtistDetailsView(artistID, a.store, a.ShowArtistList, retry, a.favoritesManager)
 	a.currentView = detailsView.Container
 	a.Window.SetContent(a.currentView)
 }
@@ -86,7 +102,7 @@ func (a *App) ShowFavorites() {
 	}
 	
 	favView := NewFavoritesView(
-		a.listView.allArtists,
+		a.store.CachedArtists(),
 		a.favoritesManager,
 		a.imageCache,
 		a.ShowArtistDetails,
@@ -96,6 +112,12 @@ func (a *App) ShowFavorites() {
 	a.Window.SetContent(a.currentView)
 }
 
+func (a *App) ShowWhatsNew() {
+	whatsNewView := NewWhatsNewView(a.history, a.ShowArtistDetails, a.ShowArtistList)
+	a.currentView = whatsNewView.Container
+	a.Window.SetContent(a.currentView)
+}
+
 func (a *App) Run() {
 	a.Window.ShowAndRun()
 }
@@ -1,12 +1,14 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"groupie-tracker/api"
 	"groupie-tracker/models"
 	"groupie-tracker/services"
 	"image/color"
 	"net/url"
-	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -25,46 +27,30 @@ type ArtistDetailsView struct {
 }
 
 // NewArtistDetailsView crée une vue détails améliorée
-func NewArtistDetailsView(artistID int, onBack func(), favMgr *services.FavoritesManager) *ArtistDetailsView {
+func NewArtistDetailsView(artistID int, store *services.ArtistStore, onBack func(), onRetry func(), favMgr *services.FavoritesManager) *ArtistDetailsView {
 	view := &ArtistDetailsView{
 		onBack:           onBack,
 		favoritesManager: favMgr,
 		spotifyService:   services.NewSpotifyService(),
 	}
 
-	// Chargement des données
-	artists, err := services.GetArtists()
-	if err != nil {
-		view.Container = container.NewCenter(
-			widget.NewLabel("❌ Erreur: " + err.Error()),
-		)
-		return view
-	}
+	// Chargement des données (construction synchrone, pas d'annulation)
+	ctx := context.Background()
 
-	// Trouver l'artiste
-	var artist models.Artist
-	found := false
-	for _, a := range artists {
-		if a.ID == artistID {
-			artist = a
-			found = true
-			break
-		}
+	artist, err := store.Artist(ctx, artistID)
+	if errors.Is(err, api.ErrNotFound) {
+		view.Container = NewErrorView("Artiste non trouvé", err, nil, onBack)
+		return view
 	}
-
-	if !found {
-		view.Container = container.NewCenter(
-			widget.NewLabel(fmt.Sprintf("❌ Artiste #%d non trouvé", artistID)),
-		)
+	if err != nil {
+		view.Container = NewErrorView("Impossible de charger les artistes", err, onRetry, onBack)
 		return view
 	}
 
-	// Agréger les données
-	aggregate, err := services.AggregateArtist(artist)
+	// Données agrégées (déjà en mémoire après le préchargement)
+	aggregate, err := store.Aggregate(ctx, artistID)
 	if err != nil {
-		view.Container = container.NewCenter(
-			widget.NewLabel("❌ Erreur chargement: " + err.Error()),
-		)
+		view.Container = NewErrorView(fmt.Sprintf("Impossible de charger %s", artist.Name), err, onRetry, onBack)
 		return view
 	}
 	view.aggregate = aggregate
@@ -222,9 +208,70 @@ func (v *ArtistDetailsView) createHeader() fyne.CanvasObject {
 		container.NewCenter(artistImage),
 		container.NewPadded(container.NewCenter(title)),
 		container.NewCenter(subtitle),
+		container.NewCenter(v.createQualityBadge()),
 	)
 }
 
+// createQualityBadge crée le badge de qualité des données (recoupement
+// Locations / Dates / Relation) ; un clic ouvre le détail des anomalies
+func (v *ArtistDetailsView) createQualityBadge() fyne.CanvasObject {
+	report := services.NewConsistencyChecker().Check(v.aggregate)
+
+	badge := widget.NewButton("", func() { v.showQualityReport(report) })
+	switch {
+	case report.OK():
+		badge.SetText("✅ Données cohérentes")
+		badge.Importance = widget.SuccessImportance
+	case report.Worst() == services.SeverityError:
+		badge.SetText(fmt.Sprintf("❌ %d anomalie(s) dans les données", len(report.Issues)))
+		badge.Importance = widget.DangerImportance
+	case report.Worst() == services.SeverityWarning:
+		badge.SetText(fmt.Sprintf("⚠️ %d anomalie(s) dans les données", len(report.Issues)))
+		badge.Importance = widget.WarningImportance
+	default:
+		badge.SetText(fmt.Sprintf("ℹ️ %d remarque(s) sur les données", len(report.Issues)))
+		badge.Importance = widget.LowImportance
+	}
+
+	return badge
+}
+
+// showQualityReport affiche le détail du rapport de cohérence
+func (v *ArtistDetailsView) showQualityReport(report services.ConsistencyReport) {
+	reportWindow := fyne.CurrentApp().NewWindow("🔎 Qualité des données")
+	reportWindow.Resize(fyne.NewSize(600, 400))
+	reportWindow.CenterOnScreen()
+
+	lines := container.NewVBox()
+	if report.OK() {
+		lines.Add(widget.NewLabel("Les lieux, les dates et la relation de cet artiste concordent."))
+	}
+	for _, issue := range report.Issues {
+		line := widget.NewLabel(fmt.Sprintf("[%s] %s", issue.Severity, issue.Message))
+		line.Wrapping = fyne.TextWrapWord
+		lines.Add(line)
+	}
+
+	content := container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle(
+				fmt.Sprintf("🔎 %s — %d anomalie(s)", report.ArtistName, len(report.Issues)),
+				fyne.TextAlignCenter,
+				fyne.TextStyle{Bold: true},
+			),
+			widget.NewSeparator(),
+		),
+		widget.NewButton("OK", func() {
+			reportWindow.Close()
+		}),
+		nil, nil,
+		container.NewVScroll(lines),
+	)
+
+	reportWindow.SetContent(container.NewPadded(content))
+	reportWindow.Show()
+}
+
 // createPlaceholder crée un placeholder pour l'image
 func (v *ArtistDetailsView) createPlaceholder() fyne.CanvasObject {
 	bg := canvas.NewRectangle(color.RGBA{R: 100, G: 150, B: 200, A: 255})
@@ -352,15 +399,15 @@ func (v *ArtistDetailsView) createLocationsSection() fyne.CanvasObject {
 	grid := container.NewGridWithColumns(4)
 
 	for _, location := range v.aggregate.Locations.Locations {
-		city, country := services.ParseLocation(location)
+		place := services.ParsePlace(location)
 
 		// Card avec hiérarchie visuelle claire
-		cityLabel := canvas.NewText(city, color.RGBA{R: 40, G: 40, B: 40, A: 255})
+		cityLabel := canvas.NewText(place.Locality(), color.RGBA{R: 40, G: 40, B: 40, A: 255})
 		cityLabel.TextSize = 16
 		cityLabel.TextStyle = fyne.TextStyle{Bold: true}
 		cityLabel.Alignment = fyne.TextAlignCenter
 
-		countryLabel := widget.NewLabelWithStyle(strings.ToUpper(country), fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+		countryLabel := widget.NewLabelWithStyle(place.Country.Label(), fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
 
 		locationCard := widget.NewCard(
 			"",
@@ -424,33 +471,44 @@ func (v *ArtistDetailsView) createDatesSection() fyne.CanvasObject {
 
 // createScheduleSection crée la section programme - AMÉLIORÉ avec hiérarchie
 func (v *ArtistDetailsView) createScheduleSection() fyne.CanvasObject {
+	concerts := services.ConcertsFromAggregate(v.aggregate)
+
 	titleBox := container.NewHBox(
 		widget.NewLabelWithStyle("🎫 Programme Détaillé", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-		widget.NewLabel(fmt.Sprintf("(%d concerts)", v.countTotalConcerts())),
+		widget.NewLabel(fmt.Sprintf("(%d concerts)", len(concerts))),
 	)
 
 	scheduleList := container.NewVBox()
 
-	for location, dates := range v.aggregate.Relation.DatesLocations {
-		city, country := services.ParseLocation(location)
+	// Regrouper par lieu, dans l'ordre chronologique du premier concert
+	order := []string{}
+	byLocation := make(map[string][]models.Concert)
+	for _, concert := range concerts {
+		if _, exists := byLocation[concert.Location]; !exists {
+			order = append(order, concert.Location)
+		}
+		byLocation[concert.Location] = append(byLocation[concert.Location], concert)
+	}
+
+	for _, location := range order {
+		group := byLocation[location]
 
 		// En-tête du lieu avec hiérarchie visuelle
-		locationTitle := canvas.NewText(fmt.Sprintf("%s, %s", city, strings.ToUpper(country)), color.RGBA{R: 40, G: 40, B: 40, A: 255})
+		locationTitle := canvas.NewText(group[0].Place.String(), color.RGBA{R: 40, G: 40, B: 40, A: 255})
 		locationTitle.TextSize = 18
 		locationTitle.TextStyle = fyne.TextStyle{Bold: true}
 
 		locationHeader := container.NewHBox(
 			widget.NewLabel("📍"),
 			locationTitle,
-			widget.NewLabel(fmt.Sprintf("(%d concerts)", len(dates))),
+			widget.NewLabel(fmt.Sprintf("(%d concerts)", len(group))),
 		)
 
 		// Dates pour ce lieu avec plus d'espace et dates formatées
 		datesContainer := container.NewVBox()
-		formattedDates := services.FormatDateList(dates)
 
-		for i, date := range formattedDates {
-			dateText := canvas.NewText(date, color.RGBA{R: 60, G: 100, B: 180, A: 255})
+		for i, concert := range group {
+			dateText := canvas.NewText(concert.Date.Format("02/01/2006"), color.RGBA{R: 60, G: 100, B: 180, A: 255})
 			dateText.TextSize = 14
 
 			dateRow := container.NewHBox(
@@ -460,7 +518,7 @@ func (v *ArtistDetailsView) createScheduleSection() fyne.CanvasObject {
 			)
 			datesContainer.Add(container.NewPadded(dateRow))
 
-			if i < len(formattedDates)-1 {
+			if i < len(group)-1 {
 				datesContainer.Add(widget.NewSeparator())
 			}
 		}
@@ -486,11 +544,3 @@ func (v *ArtistDetailsView) createScheduleSection() fyne.CanvasObject {
 		scheduleList,
 	)
 }
-
-func (v *ArtistDetailsView) countTotalConcerts() int {
-	total := 0
-	for _, dates := range v.aggregate.Relation.DatesLocations {
-		total += len(dates)
-	}
-	return total
-}
@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"fmt"
+	"groupie-tracker/api"
 	"groupie-tracker/models"
 	"groupie-tracker/services"
 	"image/color"
@@ -28,7 +29,11 @@ type ArtistListView struct {
 	filteredArtists []models.Artist
 	onSelectArtist  func(int)
 	onShowFavorites func()
+	onShowWhatsNew  func()
+	onRetry         func()
 
+	store            *services.ArtistStore
+	unsubscribe      func()
 	searchEngine     *services.SearchEngine
 	filterEngine     *services.FilterEngine
 	geocoder         *services.GeocodingService
@@ -36,76 +41,249 @@ type ArtistListView struct {
 	favoritesManager *services.FavoritesManager
 	imageCache       *services.ImageCache
 
-	listView      *widget.List
-	galleryView   fyne.CanvasObject
-	currentView   fyne.CanvasObject
-	searchBar     *SearchBar
-	statusLabel   *widget.Label
-	filtersPanel  *FiltersPanel
-	viewContainer *fyne.Container
+	listView        *widget.List
+	galleryView     fyne.CanvasObject
+	currentView     fyne.CanvasObject
+	searchBar       *SearchBar
+	statusLabel     *widget.Label
+	dataStatusLabel *widget.Label
+	filtersPanel    *FiltersPanel
+	activeFilters   *services.FilterCriteria // derniers critères appliqués (nil = aucun)
+	viewContainer   *fyne.Container
 
 	viewMode ViewMode
 	ctx      context.Context
 	cancel   context.CancelFunc
+
+	// Démarrage à chaud depuis le dernier snapshot
+	snapshot *services.Snapshot
+
+	// Versions successives du jeu de données ("Nouveautés")
+	history     *services.DatasetHistory
+	whatsNewBtn *widget.Button
 }
 
-func NewArtistListView(onSelectArtist func(int), favMgr *services.FavoritesManager, imgCache *services.ImageCache, onShowFavorites func()) *ArtistListView {
+func NewArtistListView(store *services.ArtistStore, history *services.DatasetHistory, onSelectArtist func(int), favMgr *services.FavoritesManager, imgCache *services.ImageCache, onShowFavorites func(), onShowWhatsNew func(), onRetry func()) *ArtistListView {
 	view := &ArtistListView{
+		store:            store,
+		history:          history,
 		onSelectArtist:   onSelectArtist,
 		favoritesManager: favMgr,
 		imageCache:       imgCache,
 		onShowFavorites:  onShowFavorites,
+		onShowWhatsNew:   onShowWhatsNew,
+		onRetry:          onRetry,
 		viewMode:         ViewModeList,
 	}
 
 	view.ctx, view.cancel = context.WithCancel(context.Background())
 
-	artists, err := services.GetArtists()
+	// Démarrage à chaud : on remplit le store avec le dernier snapshot
+	// et on actualise depuis la source en arrière-plan
+	if !store.Loaded() {
+		if snapshot, err := services.LoadSnapshot(services.DefaultSnapshotPath()); err == nil && len(snapshot.Artists) > 0 {
+			view.snapshot = snapshot
+			store.Replace(snapshot.Artists, snapshot.AggregateMap())
+		}
+	}
+
+	artists, err := store.Artists(view.ctx)
 	if err != nil {
-		view.Container = container.NewCenter(
-			widget.NewLabel("❌ Erreur: " + err.Error()),
-		)
+		view.Container = NewErrorView("Impossible de charger les artistes", err, onRetry, nil)
 		return view
 	}
 
-	view.allArtists = artists
-	view.filteredArtists = artists
+	view.initEngines(artists)
+	view.buildUI()
+	view.filtersPanel.LoadAvailableLocations(view.filterEngine)
+	view.unsubscribe = store.Subscribe(view.onStoreChange)
+
+	switch {
+	case view.snapshot != nil:
+		view.setDataStatus(fmt.Sprintf("📦 Données du %s — actualisation en cours...", view.snapshot.SavedAt.Format("02/01/2006 15:04")))
+		go view.refreshFromSource()
+	case store.Complete():
+		view.setDataStatus("🟢 En ligne")
+		go view.preloadImages()
+	default:
+		view.setDataStatus("🟢 En ligne")
+		go view.preload()
+	}
 
-	view.searchEngine = services.NewSearchEngine(artists)
-	view.filterEngine = services.NewFilterEngine(artists)
-	view.geocoder = services.NewGeocodingService()
-	view.geoPreloader = services.NewGeocodingPreloader(view.geocoder)
+	return view
+}
 
-	view.filtersPanel = NewFiltersPanel(func(criteria *services.FilterCriteria) {
-		view.applyFilters(criteria)
-	})
+// Ready indique si la vue a pu charger ses données (sinon elle affiche une erreur)
+func (v *ArtistListView) Ready() bool {
+	return v.searchEngine != nil
+}
 
-	view.buildUI()
-	go view.preload()
+// initEngines crée les moteurs de recherche/filtrage branchés sur le store
+func (v *ArtistListView) initEngines(artists []models.Artist) {
+	v.allArtists = artists
+	v.filteredArtists = artists
 
-	return view
+	v.searchEngine = services.NewSearchEngineWithStore(v.store)
+	v.filterEngine = services.NewFilterEngineWithStore(v.store)
+	v.geocoder = services.NewGeocodingService()
+	v.geoPreloader = services.NewGeocodingPreloader(v.geocoder)
+	v.geoPreloader.SetDataSource(v.store)
+
+	v.filtersPanel = NewFiltersPanel(func(criteria *services.FilterCriteria) {
+		v.applyFilters(criteria)
+	})
 }
 
-func (v *ArtistListView) preload() {
-	for i, artist := range v.allArtists {
-		select {
-		case <-v.ctx.Done():
+// onStoreChange répercute les changements du store sur l'interface
+// (appelée depuis la goroutine qui a modifié le store)
+func (v *ArtistListView) onStoreChange(change services.StoreChange) {
+	fyne.Do(func() {
+		if v.ctx.Err() != nil {
 			return
-		default:
-			v.searchEngine.LoadAggregateData(artist.ID)
-			v.filterEngine.LoadAggregateData(artist.ID)
-			
-			if i%(len(v.allArtists)/10+1) == 0 {
-				fmt.Printf("📊 Données: %d/%d\n", i, len(v.allArtists))
+		}
+
+		switch change.Kind {
+		case services.StoreArtistsChanged:
+			v.allArtists = v.store.CachedArtists()
+			v.filtersPanel.LoadAvailableLocations(v.filterEngine)
+			if v.activeFilters != nil {
+				v.applyFilters(v.activeFilters) // les filtres restent actifs après actualisation
+				return
+			}
+			v.filteredArtists = v.allArtists
+			v.refreshCurrentView()
+			v.statusLabel.SetText(fmt.Sprintf("📋 %d artistes", len(v.filteredArtists)))
+		case services.StoreAggregatesChanged:
+			v.filtersPanel.LoadAvailableLocations(v.filterEngine)
+			if v.activeFilters != nil {
+				v.applyFilters(v.activeFilters) // lieux et concerts arrivés après le filtrage
 			}
 		}
+	})
+}
+
+func (v *ArtistListView) preload() {
+	err := v.store.LoadAll(v.ctx)
+	if v.ctx.Err() != nil {
+		return
 	}
-	
-	v.filtersPanel.LoadAvailableLocations(v.filterEngine)
+	if err != nil {
+		fmt.Printf("⚠️ Données agrégées incomplètes: %v\n", err)
+	}
+	v.logDataStats()
 	fmt.Println("✅ Données agrégées OK")
 
+	v.saveSnapshot()
+	v.preloadImages()
+}
+
+// refreshFromSource actualise les données du store chargées depuis le snapshot.
+// Les réponses servies par le cache HTTP périmé (réseau coupé) ne remplacent
+// ni le store ni le snapshot.
+func (v *ArtistListView) refreshFromSource() {
+	source := v.store.Source()
+	ctx, report := api.WithFetchReport(v.ctx)
+
+	artists, err := source.GetArtists(ctx)
+	if v.ctx.Err() != nil {
+		return
+	}
+	if err != nil || report.Stale() {
+		if err != nil {
+			fmt.Printf("📴 Hors ligne: %v\n", err)
+		} else {
+			fmt.Println("📴 Hors ligne: artistes servis par le cache HTTP")
+		}
+		fyne.Do(func() {
+			v.setDataStatus(fmt.Sprintf("🔴 Hors ligne — données du %s", v.snapshot.SavedAt.Format("02/01/2006 15:04")))
+		})
+		v.preloadImages()
+		return
+	}
+
+	// Chargement groupé via les endpoints d'index (repli par artiste inclus)
+	aggregates, err := services.LoadAllAggregatesContext(ctx, source, artists)
+	if v.ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		fmt.Printf("⚠️ Données agrégées incomplètes: %v\n", err)
+	}
+
+	v.store.Replace(artists, aggregates)
+	v.logDataStats()
+
+	// Une partie des agrégats vient du cache périmé : le snapshot est gardé
+	if report.Stale() {
+		fyne.Do(func() {
+			v.setDataStatus(fmt.Sprintf("🟠 Connexion instable — certaines données du %s", report.StaleSince().Format("02/01/2006 15:04")))
+		})
+		v.preloadImages()
+		return
+	}
+
+	fyne.Do(func() {
+		v.setDataStatus("🟢 En ligne — données à jour")
+	})
+
+	v.saveSnapshot()
+	v.preloadImages()
+}
+
+// logDataStats affiche l'état du store et du cache HTTP
+func (v *ArtistListView) logDataStats() {
+	fmt.Printf("📊 Données: %d/%d\n", len(v.store.Aggregates()), len(v.store.CachedArtists()))
+
+	stats := api.GetCacheStats()
+	fmt.Printf("📦 Cache HTTP: %d hits, %d revalidées, %d téléchargées, %d périmées\n",
+		stats.Hits, stats.Revalidated, stats.Misses, stats.StaleServed)
+}
+
+// saveSnapshot enregistre le contenu du store pour le prochain démarrage
+func (v *ArtistListView) saveSnapshot() {
+	if !v.store.Complete() {
+		return // données incomplètes : on garde le snapshot précédent
+	}
+
+	snapshot := services.NewSnapshot(v.store.CachedArtists(), v.store.Aggregates())
+	if err := services.SaveSnapshot(services.DefaultSnapshotPath(), snapshot); err != nil {
+		fmt.Printf("⚠️ Erreur sauvegarde snapshot: %v\n", err)
+		return
+	}
+	fmt.Println("💾 Snapshot des données sauvegardé")
+
+	v.recordVersion(snapshot)
+}
+
+// recordVersion compare la version téléchargée à la précédente et signale
+// les nouveautés (artistes, lieux, dates) sur le bouton dédié
+func (v *ArtistListView) recordVersion(snapshot *services.Snapshot) {
+	if v.history == nil {
+		return
+	}
+
+	diff, err := v.history.Record(snapshot)
+	if err != nil {
+		fmt.Printf("⚠️ Erreur historique des versions: %v\n", err)
+	}
+	if diff == nil {
+		return
+	}
+
+	fmt.Printf("🆕 Nouvelle version des données: %s\n", diff.Summary())
+	fyne.Do(func() {
+		if v.whatsNewBtn != nil {
+			v.whatsNewBtn.SetText("🆕 Nouveautés (" + diff.Summary() + ")")
+			v.whatsNewBtn.Importance = widget.HighImportance
+			v.whatsNewBtn.Refresh()
+		}
+	})
+}
+
+// preloadImages précharge les images des artistes en arrière-plan
+func (v *ArtistListView) preloadImages() {
 	fmt.Println("🖼️ Préchargement des images...")
-	err := v.imageCache.PreloadImages(v.allArtists, func(current, total int) {
+	err := v.imageCache.PreloadImagesContext(v.ctx, v.store.CachedArtists(), func(current, total int) {
 		if current%(total/10+1) == 0 {
 			fmt.Printf("🖼️ Images: %d/%d (%.0f%%)\n", current, total, float64(current)*100/float64(total))
 		}
@@ -120,6 +298,13 @@ func (v *ArtistListView) preload() {
 	fmt.Println("💡 Géolocalisation: à la demande (clic sur Carte)")
 }
 
+// setDataStatus met à jour l'indicateur de fraîcheur des données
+func (v *ArtistListView) setDataStatus(text string) {
+	if v.dataStatusLabel != nil {
+		v.dataStatusLabel.SetText(text)
+	}
+}
+
 func (v *ArtistListView) preloadGeoOnDemand() {
 	loaded, total := v.geoPreloader.GetProgress()
 	if loaded == total && total > 0 {
@@ -129,7 +314,7 @@ func (v *ArtistListView) preloadGeoOnDemand() {
 
 	fmt.Println("🌍 Démarrage géolocalisation...")
 	
-	err := v.geoPreloader.PreloadAll(v.allArtists, func(current, total int) {
+	err := v.geoPreloader.PreloadAllContext(v.ctx, v.store.CachedArtists(), func(current, total int) {
 		if current%(total/10+1) == 0 {
 			fmt.Printf("🌍 Géo: %d/%d (%.0f%%)\n", current, total, float64(current)*100/float64(total))
 		}
@@ -146,6 +331,15 @@ func (v *ArtistListView) Cleanup() {
 	if v.cancel != nil {
 		v.cancel()
 	}
+	if v.unsubscribe != nil {
+		v.unsubscribe()
+	}
+	if v.searchBar != nil {
+		v.searchBar.Close()
+	}
+	if v.searchEngine != nil {
+		v.searchEngine.Close()
+	}
 }
 
 func (v *ArtistListView) buildUI() {
@@ -158,6 +352,9 @@ func (v *ArtistListView) buildUI() {
 	v.searchBar = NewSearchBar(v.searchEngine, v.onSelectArtist)
 	v.statusLabel = widget.NewLabel(fmt.Sprintf("📋 %d artistes", len(v.filteredArtists)))
 	v.statusLabel.Alignment = fyne.TextAlignCenter
+	v.dataStatusLabel = widget.NewLabel("")
+	v.dataStatusLabel.Alignment = fyne.TextAlignCenter
+	v.dataStatusLabel.TextStyle = fyne.TextStyle{Italic: true}
 
 	listBtn := widget.NewButton("📋 Liste", func() { v.switchView(ViewModeList) })
 	galleryBtn := widget.NewButton("🖼️ Galerie", func() { v.switchView(ViewModeGallery) })
@@ -168,6 +365,12 @@ func (v *ArtistListView) buildUI() {
 		}
 	})
 	favBtn.Importance = widget.HighImportance
+
+	v.whatsNewBtn = widget.NewButton("🆕 Nouveautés", func() {
+		if v.onShowWhatsNew != nil {
+			v.onShowWhatsNew()
+		}
+	})
 	
 	filterBtn := widget.NewButton("🔧 Filtres", func() { v.showFiltersWindow() })
 	resetBtn := widget.NewButton("🔄 Reset", func() { v.resetFilters() })
@@ -178,7 +381,7 @@ func (v *ArtistListView) buildUI() {
 		listBtn, galleryBtn, mapBtn,
 	)
 
-	actionToolbar := container.NewHBox(favBtn, filterBtn, resetBtn, helpBtn)
+	actionToolbar := container.NewHBox(favBtn, v.whatsNewBtn, filterBtn, resetBtn, helpBtn)
 
 	v.createAllViews()
 	v.viewContainer = container.NewMax(v.currentView)
@@ -186,6 +389,7 @@ func (v *ArtistListView) buildUI() {
 	content := container.NewBorder(
 		container.NewVBox(
 			title,
+			v.dataStatusLabel,
 			widget.NewSeparator(),
 			v.searchBar.Container,
 			widget.NewSeparator(),
@@ -460,20 +664,13 @@ func createSmallPlaceholder(name string) fyne.CanvasObject {
 }
 
 func (v *ArtistListView) showArtistMap(artistID int) {
-	var artist models.Artist
-	for _, a := range v.allArtists {
-		if a.ID == artistID {
-			artist = a
-			break
-		}
-	}
-
-	aggregate, err := services.AggregateArtist(artist)
+	aggregate, err := v.store.Aggregate(v.ctx, artistID)
 	if err != nil {
+		fmt.Printf("⚠️ Carte indisponible: %v\n", err)
 		return
 	}
 
-	mapView := NewMapView(aggregate, v.geocoder)
+	mapView := NewMapView(v.ctx, aggregate, v.geocoder)
 
 	backButton := widget.NewButton("← Retour", func() {
 		v.switchView(ViewModeMap)
@@ -486,7 +683,10 @@ func (v *ArtistListView) showArtistMap(artistID int) {
 }
 
 func (v *ArtistListView) applyFilters(criteria *services.FilterCriteria) {
-	v.filteredArtists = v.filterEngine.ApplyFilters(criteria)
+	applied := *criteria // le panneau continue de modifier ses critères
+	applied.Locations = append([]string(nil), criteria.Locations...)
+	v.activeFilters = &applied
+	v.filteredArtists = v.filterEngine.ApplyFilters(v.activeFilters)
 	v.refreshCurrentView()
 	v.statusLabel.SetText(fmt.Sprintf("📋 %d/%d artistes", len(v.filteredArtists), len(v.allArtists)))
 }
@@ -503,6 +703,7 @@ func (v *ArtistListView) refreshCurrentView() {
 }
 
 func (v *ArtistListView) resetFilters() {
+	v.activeFilters = nil
 	v.filteredArtists = v.allArtists
 	v.refreshCurrentView()
 	v.statusLabel.SetText(fmt.Sprintf("📋 %d artistes", len(v.filteredArtists)))
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"context"
+	"errors"
+
+	"groupie-tracker/api"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// NewErrorView crée un écran d'erreur avec un conseil adapté à la classe
+// d'erreur, un bouton "Réessayer" et un bouton "Retour" (optionnels si nil)
+func NewErrorView(title string, err error, onRetry func(), onBack func()) fyne.CanvasObject {
+	icon, hint := errorHint(err)
+
+	titleLabel := widget.NewLabelWithStyle(icon+" "+title, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	hintLabel := widget.NewLabel(hint)
+	hintLabel.Alignment = fyne.TextAlignCenter
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	// Détail technique (URL, statut...) en petit pour le diagnostic
+	details := widget.NewLabelWithStyle(err.Error(), fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+	details.Wrapping = fyne.TextWrapWord
+
+	buttons := container.NewHBox()
+	if onRetry != nil {
+		retryBtn := widget.NewButton("🔄 Réessayer", onRetry)
+		retryBtn.Importance = widget.HighImportance
+		buttons.Add(retryBtn)
+	}
+	if onBack != nil {
+		buttons.Add(widget.NewButton("← Retour", onBack))
+	}
+
+	card := widget.NewCard("", "", container.NewVBox(
+		titleLabel,
+		widget.NewSeparator(),
+		hintLabel,
+		details,
+		widget.NewLabel(""),
+		container.NewCenter(buttons),
+	))
+
+	return container.NewCenter(container.NewGridWrap(fyne.NewSize(560, 320), card))
+}
+
+// errorHint retourne une icône et un conseil pour chaque classe d'erreur
+func errorHint(err error) (string, string) {
+	switch {
+	case errors.Is(err, api.ErrOffline):
+		return "📴", "Impossible de joindre le serveur. Vérifiez votre connexion internet puis réessayez."
+	case errors.Is(err, api.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "⏱️", "Le serveur met trop de temps à répondre. Il est peut-être en cours de démarrage : réessayez dans quelques secondes."
+	case errors.Is(err, api.ErrRateLimited):
+		return "🚦", "Trop de requêtes envoyées. Patientez une minute avant de réessayer."
+	case errors.Is(err, api.ErrUnavailable):
+		return "🛠️", "Le service est temporairement indisponible. Réessayez un peu plus tard."
+	case errors.Is(err, api.ErrNotFound):
+		return "🔍", "Les données demandées sont introuvables. Elles ont peut-être été supprimées."
+	case errors.Is(err, api.ErrBadRequest):
+		return "🔗", "L'adresse du serveur est invalide. Vérifiez la configuration de l'application."
+	case errors.Is(err, api.ErrDecode):
+		return "🧩", "Les données reçues sont illisibles. Si le problème persiste, videz le cache de l'application."
+	}
+	return "❌", "Une erreur inattendue est survenue."
+}
@@ -36,6 +36,13 @@ type FiltersPanel struct {
 	membersMinLabel  *widget.Label
 	membersMaxLabel  *widget.Label
 
+	// Widgets pour Année de Concert
+	concertCheck     *widget.Check
+	concertMinSlider *widget.Slider
+	concertMaxSlider *widget.Slider
+	concertMinLabel  *widget.Label
+	concertMaxLabel  *widget.Label
+
 	// Widgets pour Locations
 	locationCheck  *widget.Check
 	locationSelect *widget.Select
@@ -87,6 +94,7 @@ func (fp *FiltersPanel) buildContent() fyne.CanvasObject {
 	albumSection := fp.buildFirstAlbumSection()
 	membersSection := fp.buildMembersSection()
 	locationSection := fp.buildLocationSection()
+	concertSection := fp.buildConcertYearSection()
 	buttonsSection := fp.buildButtons()
 
 	// Assemblage
@@ -101,6 +109,8 @@ func (fp *FiltersPanel) buildContent() fyne.CanvasObject {
 		widget.NewSeparator(),
 		locationSection,
 		widget.NewSeparator(),
+		concertSection,
+		widget.NewSeparator(),
 		buttonsSection,
 	)
 }
@@ -300,16 +310,14 @@ func (fp *FiltersPanel) buildLocationSection() fyne.CanvasObject {
 		}
 	})
 
-	// Liste de pays (à remplir dynamiquement avec GetAvailableLocations())
-	countries := []string{
-		"USA",
-		"FRANCE",
-		"UK",
-		"GERMANY",
-		"JAPAN",
-		"CANADA",
-		"SPAIN",
-		"ITALY",
+	// Liste de pays par défaut (remplacée par GetAvailableLocations() une
+	// fois les données chargées). Les libellés sont canoniques ("🇺🇸 États-Unis")
+	// et reconnus tels quels par le FilterEngine.
+	countries := []string{}
+	for _, token := range []string{"usa", "france", "uk", "germany", "japan", "canada", "spain", "italy"} {
+		if country, ok := services.LookupCountry(token); ok {
+			countries = append(countries, country.Label())
+		}
 	}
 
 	fp.locationSelect = widget.NewSelect(
@@ -338,6 +346,65 @@ func (fp *FiltersPanel) buildLocationSection() fyne.CanvasObject {
 	)
 }
 
+// buildConcertYearSection crée la section filtre par année de concert
+func (fp *FiltersPanel) buildConcertYearSection() fyne.CanvasObject {
+	fp.concertCheck = widget.NewCheck("Activer ce filtre", func(checked bool) {
+		fp.criteria.EnableConcertYearFilter = checked
+		if checked {
+			fp.concertMinSlider.Enable()
+			fp.concertMaxSlider.Enable()
+		} else {
+			fp.concertMinSlider.Disable()
+			fp.concertMaxSlider.Disable()
+		}
+	})
+
+	fp.concertMinLabel = widget.NewLabel("Min: 1950")
+	fp.concertMaxLabel = widget.NewLabel("Max: 2025")
+
+	fp.concertMinSlider = widget.NewSlider(1950, 2025)
+	fp.concertMinSlider.SetValue(1950)
+	fp.concertMinSlider.Step = 1
+	fp.concertMinSlider.OnChanged = func(val float64) {
+		fp.criteria.ConcertYearMin = int(val)
+		fp.concertMinLabel.SetText(fmt.Sprintf("Min: %d", int(val)))
+		
+		if val > fp.concertMaxSlider.Value {
+			fp.concertMaxSlider.SetValue(val)
+		}
+	}
+	fp.concertMinSlider.Disable()
+
+	fp.concertMaxSlider = widget.NewSlider(1950, 2025)
+	fp.concertMaxSlider.SetValue(2025)
+	fp.concertMaxSlider.Step = 1
+	fp.concertMaxSlider.OnChanged = func(val float64) {
+		fp.criteria.ConcertYearMax = int(val)
+		fp.concertMaxLabel.SetText(fmt.Sprintf("Max: %d", int(val)))
+		
+		if val < fp.concertMinSlider.Value {
+			fp.concertMinSlider.SetValue(val)
+		}
+	}
+	fp.concertMaxSlider.Disable()
+
+	slidersContent := container.NewVBox(
+		fp.concertCheck,
+		widget.NewLabel(""),
+		container.NewHBox(fp.concertMinLabel, widget.NewLabel("")),
+		fp.concertMinSlider,
+		widget.NewLabel(""),
+		container.NewHBox(fp.concertMaxLabel, widget.NewLabel("")),
+		fp.concertMaxSlider,
+	)
+
+	return widget.NewCard(
+		"🎫 Année de Concert",
+		"Artistes ayant joué au moins une fois sur la période",
+		slidersContent,
+	)
+}
+
 // buildButtons crée les boutons d'action
 func (fp *FiltersPanel) buildButtons() fyne.CanvasObject {
 	fp.applyButton = widget.NewButton("✅ Appliquer les Filtres", func() {
@@ -371,6 +438,7 @@ func (fp *FiltersPanel) resetFilters() {
 	fp.albumCheck.SetChecked(false)
 	fp.membersCheck.SetChecked(false)
 	fp.locationCheck.SetChecked(false)
+	fp.concertCheck.SetChecked(false)
 
 	// Réinitialiser les sliders
 	fp.creationMinSlider.SetValue(1950)
@@ -379,6 +447,8 @@ func (fp *FiltersPanel) resetFilters() {
 	fp.albumMaxSlider.SetValue(2025)
 	fp.membersMinSlider.SetValue(1)
 	fp.membersMaxSlider.SetValue(10)
+	fp.concertMinSlider.SetValue(1950)
+	fp.concertMaxSlider.SetValue(2025)
 
 	// Réinitialiser la sélection
 	fp.locationSelect.SetSelected("")
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
@@ -21,20 +22,28 @@ import (
 // MapView représente une vue carte avec vraies tuiles OpenStreetMap
 type MapView struct {
 	Container   fyne.CanvasObject
+	ctx         context.Context // annulé quand la vue parente est fermée
 	geocoder    *services.GeocodingService
 	artistData  models.ArtistAggregate
 	coordinates map[string]*services.Coordinates
+	concerts    map[string][]models.Concert // concerts par clé de lieu
 
 	mapContainer    *fyne.Container
 	selectedLocation string
 }
 
 // NewMapView crée une vue carte avec chargement à la demande
-func NewMapView(artistData models.ArtistAggregate, geocoder *services.GeocodingService) *MapView {
+func NewMapView(ctx context.Context, artistData models.ArtistAggregate, geocoder *services.GeocodingService) *MapView {
 	mv := &MapView{
+		ctx:         ctx,
 		geocoder:    geocoder,
 		artistData:  artistData,
 		coordinates: make(map[string]*services.Coordinates),
+		concerts:    make(map[string][]models.Concert),
+	}
+
+	for _, concert := range services.ConcertsFromAggregate(artistData) {
+		mv.concerts[concert.Location] = append(mv.concerts[concert.Location], concert)
 	}
 
 	mv.buildUI()
@@ -114,7 +123,7 @@ func (mv *MapView) showDefaultMap() {
 }
 
 // showLocationMap affiche la carte centrée sur un lieu
-func (mv *MapView) showLocationMap(city, country string, coords *services.Coordinates) {
+func (mv *MapView) showLocationMap(place models.Place, coords *services.Coordinates) {
 	zoom := 8
 	
 	mapImg := mv.renderMap(coords.Latitude, coords.Longitude, zoom, coords)
@@ -124,7 +133,7 @@ func (mv *MapView) showLocationMap(city, country string, coords *services.Coordi
 	canvasImg.SetMinSize(fyne.NewSize(700, 700))
 
 	// Info simplifiée - seulement le lieu
-	infoText := fmt.Sprintf("%s, %s", city, country)
+	infoText := fmt.Sprintf("%s %s", place.Country.Flag(), place.String())
 
 	infoLabel := widget.NewLabel(infoText)
 	infoLabel.Alignment = fyne.TextAlignCenter
@@ -139,7 +148,7 @@ func (mv *MapView) showLocationMap(city, country string, coords *services.Coordi
 	}
 	mv.mapContainer.Refresh()
 	
-	fmt.Printf("🎯 Carte affichée pour %s, %s (%.4f, %.4f)\n", city, country, coords.Latitude, coords.Longitude)
+	fmt.Printf("🎯 Carte affichée pour %s (%.4f, %.4f)\n", place, coords.Latitude, coords.Longitude)
 }
 
 // renderMap génère l'image de la carte avec tuiles OSM
@@ -162,7 +171,7 @@ func (mv *MapView) renderMap(centerLat, centerLon float64, zoom int, selectedCoo
 
 			url := fmt.Sprintf("https://tile.openstreetmap.org/%d/%d/%d.png", zoom, tx, ty)
 
-			tile := downloadImage(url)
+			tile := downloadImage(mv.ctx, url)
 			if tile != nil {
 				destX := (dx + 1) * 256
 				destY := (dy + 1) * 256
@@ -206,8 +215,14 @@ func (mv *MapView) loadCoordinatesOnDemand() {
 	fmt.Printf("🌍 Chargement des coordonnées pour %s (%d lieux)...\n", mv.artistData.Artist.Name, total)
 
 	for i, location := range mv.artistData.Locations.Locations {
+		if mv.ctx.Err() != nil {
+			fmt.Printf("⏹️ Géocodage interrompu pour %s\n", mv.artistData.Artist.Name)
+			return
+		}
+
 		// Vérifier si déjà en cache
-		if coords, exists := mv.geocoder.GetFromCache(location); exists {
+		place := services.ParsePlace(location)
+		if coords, exists := mv.geocoder.GetFromCache(place.Query()); exists {
 			mv.coordinates[location] = coords
 			loaded++
 			if i%5 == 0 || i == total-1 {
@@ -215,7 +230,7 @@ func (mv *MapView) loadCoordinatesOnDemand() {
 			}
 		} else {
 			// Géocoder à la demande
-			coords, err := mv.geocoder.Geocode(location)
+			coords, err := mv.geocoder.GeocodePlaceContext(mv.ctx, place)
 			if err == nil && coords != nil {
 				mv.coordinates[location] = coords
 				loaded++
@@ -261,7 +276,7 @@ func (mv *MapView) createLocationsList() *widget.List {
 			}
 
 			location := mv.artistData.Locations.Locations[id]
-			city, country := services.ParseLocation(location)
+			place := services.ParsePlace(location)
 
 			vbox := obj.(*fyne.Container)
 			hbox := vbox.Objects[0].(*fyne.Container)
@@ -270,7 +285,11 @@ func (mv *MapView) createLocationsList() *widget.List {
 			statusLabel := vbox.Objects[1].(*widget.Label)
 			viewBtn := vbox.Objects[2].(*widget.Button)
 
-			nameLabel.SetText(fmt.Sprintf("%s, %s", city, country))
+			nameLabel.SetText(place.String())
+			if concerts := mv.concerts[location]; len(concerts) > 0 {
+				nameLabel.SetText(fmt.Sprintf("%s — %d concert(s), dernier le %s",
+					place, len(concerts), concerts[len(concerts)-1].Date.Format("02/01/2006")))
+			}
 
 			if coords, exists := mv.coordinates[location]; exists {
 				statusLabel.SetText(
@@ -279,7 +298,7 @@ func (mv *MapView) createLocationsList() *widget.List {
 
 				viewBtn.Enable()
 				viewBtn.OnTapped = func() {
-					mv.showLocationMap(city, country, coords)
+					mv.showLocationMap(place, coords)
 				}
 			} else {
 				statusLabel.SetText("⏳ Chargement en cours...")
@@ -387,8 +406,8 @@ func drawMarker(img *image.RGBA, x, y int, isSelected bool) {
 }
 
 // downloadImage télécharge une image depuis une URL
-func downloadImage(url string) image.Image {
-	req, err := http.NewRequest("GET", url, nil)
+func downloadImage(ctx context.Context, url string) image.Image {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil
 	}
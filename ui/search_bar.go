@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"groupie-tracker/services"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -15,14 +17,21 @@ type SearchBar struct {
 	
 	// Moteurs de recherche
 	searchEngine   *services.SearchEngine
-	fuzzyEngine    *services.FuzzySearchEngine
-	initialsEngine *services.InitialsSearchEngine
+	pipeline       *services.SearchPipeline // exact, initiales, flou et phonétique classés ensemble
+	worker         *services.SearchWorker   // recherche hors du thread UI pendant la frappe
 	searchHistory  *services.SearchHistory
+	aliases        *services.AliasDictionary // "rhcp" -> Red Hot Chili Peppers
+	clicks         *services.ClickModel      // classement personnalisé par l'historique
 	
 	// Widgets
 	entry          *widget.Entry
+	queryStatus    *widget.Label   // erreur de syntaxe de la requête
+	personalize    *widget.Check   // active le classement personnalisé
+	completions    *fyne.Container // complétion des noms de champs
 	suggestionList *widget.List
 	suggestions    []services.SearchResult
+	explanations   []string // pourquoi chaque suggestion correspond
+	corrections    []*services.Correction // ligne "Vouliez-vous dire" (nil pour un résultat)
 	onSelect       func(int) // Callback quand on sélectionne un artiste
 	
 	// État
@@ -33,17 +42,48 @@ type SearchBar struct {
 func NewSearchBar(searchEngine *services.SearchEngine, onSelect func(int)) *SearchBar {
 	sb := &SearchBar{
 		searchEngine:       searchEngine,
-		fuzzyEngine:        services.NewFuzzySearchEngine(searchEngine),
-		initialsEngine:     services.NewInitialsSearchEngine(searchEngine),
+		pipeline:           services.NewSearchPipeline(searchEngine),
 		searchHistory:      services.NewSearchHistory(50),
+		aliases:            services.NewAliasDictionary(),
 		suggestions:        []services.SearchResult{},
 		onSelect:           onSelect,
 		suggestionsVisible: false,
 	}
+	searchEngine.SetAliases(sb.aliases)
+	
+	// Classement appris des sélections passées
+	sb.clicks = services.NewClickModelFromHistory(sb.searchHistory, services.DefaultClickHalfLife)
+	sb.pipeline.SetClickModel(sb.clicks)
 
 	// Entry de recherche
 	sb.entry = widget.NewEntry()
-	sb.entry.SetPlaceHolder("🔍 Rechercher (essayez 'fm' pour Freddie Mercury ou 'qeen' pour Queen)...")
+	sb.entry.SetPlaceHolder("🔍 Rechercher (essayez 'fm', 'qeen', 'acdc' ou 'member:john country:germany year:>=1995')...")
+
+	// Aide au langage de requête
+	sb.queryStatus = widget.NewLabel("")
+	sb.queryStatus.Wrapping = fyne.TextWrapWord
+	sb.queryStatus.Importance = widget.DangerImportance
+	sb.queryStatus.Hide()
+	sb.completions = container.NewHBox()
+	sb.completions.Hide()
+	
+	// Personnalisation du classement (désactivable)
+	sb.personalize = widget.NewCheck("Classer selon mes choix passés", func(checked bool) {
+		sb.clicks.SetEnabled(checked)
+		if sb.entry.Text != "" {
+			sb.worker.Submit(sb.entry.Text)
+		}
+	})
+	sb.personalize.Checked = sb.clicks.Enabled() // sans déclencher OnChanged
+	
+	// Édition des alias de recherche
+	aliasButton := widget.NewButton("🏷️ Alias", func() {
+		showAliasEditor(sb.aliases, func() {
+			if sb.entry.Text != "" {
+				sb.worker.Submit(sb.entry.Text)
+			}
+		})
+	})
 	
 	// Liste de suggestions
 	sb.suggestionList = widget.NewList(
@@ -62,11 +102,21 @@ func NewSearchBar(searchEngine *services.SearchEngine, onSelect func(int)) *Sear
 
 	// Gestion de la sélection
 	sb.suggestionList.OnSelected = func(id widget.ListItemID) {
+		if correction := sb.correctionAt(id); correction != nil {
+			// Relancer la recherche avec la requête corrigée
+			sb.suggestionList.UnselectAll()
+			sb.entry.SetText(correction.Query)
+			sb.entry.CursorColumn = len([]rune(correction.Query))
+			sb.entry.Refresh()
+			return
+		}
+		
 		if id < len(sb.suggestions) {
 			selected := sb.suggestions[id]
 			
 			// Sauvegarder dans l'historique
 			sb.searchHistory.Add(sb.entry.Text, selected.ArtistID)
+			sb.clicks.SetHistory(sb.searchHistory.GetAll())
 			
 			// Nettoyer l'interface
 			sb.entry.SetText("")
@@ -79,6 +129,11 @@ func NewSearchBar(searchEngine *services.SearchEngine, onSelect func(int)) *Sear
 		}
 	}
 
+	// Recherche en arrière-plan, résultats livrés sur le thread UI
+	sb.worker = services.NewSearchWorker(sb.pipeline, services.DefaultSearchDelay, 10, sb.showResults)
+	sb.worker.SetDispatcher(fyne.Do)
+	sb.worker.SetCorrector(services.NewSpellCorrector(searchEngine))
+
 	// Événement de changement de texte
 	sb.entry.OnChanged = func(query string) {
 		sb.updateSuggestionsAdvanced(query)
@@ -93,7 +148,7 @@ func NewSearchBar(searchEngine *services.SearchEngine, onSelect func(int)) *Sear
 
 	// Layout
 	searchContainer := container.NewBorder(
-		sb.entry,
+		container.NewVBox(sb.entry, sb.completions, sb.queryStatus, container.NewBorder(nil, nil, nil, aliasButton, sb.personalize)),
 		nil,
 		nil,
 		nil,
@@ -113,14 +168,15 @@ func (sb *SearchBar) createSuggestionTemplate() fyne.CanvasObject {
 	artistLabel := widget.NewLabel("")
 	artistLabel.TextStyle = fyne.TextStyle{Italic: true}
 	
-	scoreLabel := widget.NewLabel("")
-	scoreLabel.TextStyle = fyne.TextStyle{Monospace: true}
-	scoreLabel.Hide() // Caché par défaut
+	reasonLabel := widget.NewLabel("")
+	reasonLabel.TextStyle = fyne.TextStyle{Monospace: true}
+	reasonLabel.Importance = widget.LowImportance
+	reasonLabel.Hide() // Caché sans explication
 	
 	return container.NewVBox(
 		container.NewHBox(typeIcon, matchedLabel),
 		artistLabel,
-		scoreLabel,
+		reasonLabel,
 	)
 }
 
@@ -133,7 +189,17 @@ func (sb *SearchBar) updateSuggestionItem(id widget.ListItemID, obj fyne.CanvasO
 	typeIcon := topRow.Objects[0].(*widget.Label)
 	matchedLabel := topRow.Objects[1].(*widget.RichText)
 	artistLabel := vbox.Objects[1].(*widget.Label)
-	scoreLabel := vbox.Objects[2].(*widget.Label)
+	reasonLabel := vbox.Objects[2].(*widget.Label)
+	
+	// Correction proposée pour une saisie sans résultat
+	if correction := sb.correctionAt(id); correction != nil {
+		typeIcon.SetText("💡")
+		matchedLabel.ParseMarkdown("Vouliez-vous dire **" + correction.Query + "** ?")
+		artistLabel.SetText(fmt.Sprintf("→ %d résultat(s)", correction.Results))
+		artistLabel.Show()
+		reasonLabel.Hide()
+		return
+	}
 	
 	// Icône selon le type
 	var icon string
@@ -146,6 +212,8 @@ func (sb *SearchBar) updateSuggestionItem(id widget.ListItemID, obj fyne.CanvasO
 		icon = "📍"
 	case services.SearchTypeDate:
 		icon = "📅"
+	case services.SearchTypeConcert:
+		icon = "🎫"
 	}
 	typeIcon.SetText(icon)
 	
@@ -155,70 +223,59 @@ func (sb *SearchBar) updateSuggestionItem(id widget.ListItemID, obj fyne.CanvasO
 	matchedLabel.ParseMarkdown(markdownText)
 	
 	// Nom de l'artiste
-	if suggestion.Type != services.SearchTypeArtist {
+	if suggestion.Type == services.SearchTypeConcert {
+		artistLabel.SetText("→ " + suggestion.ArtistName + " · 📍 " + suggestion.Location)
+		artistLabel.Show()
+	} else if suggestion.Type != services.SearchTypeArtist {
 		artistLabel.SetText("→ " + suggestion.ArtistName)
 		artistLabel.Show()
 	} else {
 		artistLabel.Hide()
 	}
 	
-	// Score (pour debug, peut être affiché)
-	scoreLabel.SetText(fmt.Sprintf("[%d pts]", suggestion.Score))
+	// Explication du classement (pipeline uniquement)
+	if id < len(sb.explanations) && sb.explanations[id] != "" {
+		reasonLabel.SetText(fmt.Sprintf("%s [%d pts]", sb.explanations[id], suggestion.Score))
+		reasonLabel.Show()
+	} else {
+		reasonLabel.Hide()
+	}
 }
 
-// updateSuggestionsAdvanced - Version avancée avec tous les moteurs
+// updateSuggestionsAdvanced - Version avancée avec tous les moteurs : la
+// syntaxe est vérifiée tout de suite, la recherche part au SearchWorker
 func (sb *SearchBar) updateSuggestionsAdvanced(query string) {
+	_, err := services.ParseQuery(query)
+	sb.updateQueryHelp(query, err)
+
 	if query == "" {
 		// Afficher l'historique récent quand la recherche est vide
+		sb.worker.Cancel()
 		sb.showHistorySuggestions()
 		return
 	}
 
-	// Combiner tous les types de recherche
-	allResults := []services.SearchResult{}
-	seen := make(map[string]bool) // Pour éviter les doublons
-	
-	// 1. Recherche normale (score le plus élevé)
-	normalResults := sb.searchEngine.GetSuggestions(query, 8)
-	for _, r := range normalResults {
-		key := fmt.Sprintf("%d-%s-%s", r.ArtistID, r.MatchedText, r.Type)
-		if !seen[key] {
-			allResults = append(allResults, r)
-			seen[key] = true
-		}
-	}
-	
-	// 2. Recherche par initiales (si query courte)
-	if len(query) >= 2 && len(query) <= 5 {
-		initialsResults := sb.initialsEngine.SearchByInitials(query)
-		for _, r := range initialsResults {
-			key := fmt.Sprintf("%d-%s-%s", r.ArtistID, r.MatchedText, r.Type)
-			if !seen[key] {
-				allResults = append(allResults, r)
-				seen[key] = true
-			}
-		}
-	}
-	
-	// 3. Recherche floue (si peu de résultats normaux)
-	if len(normalResults) < 3 {
-		fuzzyResults := sb.fuzzyEngine.FuzzySearch(query, 2)
-		for _, r := range fuzzyResults {
-			key := fmt.Sprintf("%d-%s-%s", r.ArtistID, r.MatchedText, r.Type)
-			if !seen[key] {
-				allResults = append(allResults, r)
-				seen[key] = true
-			}
-		}
+	sb.worker.Submit(query)
+}
+
+// showResults affiche une réponse du SearchWorker (thread UI). Le worker ne
+// livre que la réponse de la dernière saisie.
+func (sb *SearchBar) showResults(response services.SearchResponse) {
+	sb.suggestions = make([]services.SearchResult, len(response.Results))
+	sb.explanations = make([]string, len(response.Results))
+	sb.corrections = nil
+	for i, r := range response.Results {
+		sb.suggestions[i] = r.SearchResult
+		sb.explanations[i] = r.Explain()
 	}
 	
-	// Limiter à 10 suggestions
-	if len(allResults) > 10 {
-		allResults = allResults[:10]
+	// Aucun résultat : proposer la correction orthographique
+	if len(sb.suggestions) == 0 && response.Correction != nil {
+		sb.suggestions = []services.SearchResult{{MatchedText: response.Correction.Query}}
+		sb.explanations = nil
+		sb.corrections = []*services.Correction{response.Correction}
 	}
 	
-	sb.suggestions = allResults
-	
 	if len(sb.suggestions) > 0 {
 		sb.showSuggestions()
 	} else {
@@ -226,9 +283,40 @@ func (sb *SearchBar) updateSuggestionsAdvanced(query string) {
 	}
 	
 	sb.suggestionList.Refresh()
-	
-	fmt.Printf("🔍 Recherche avancée: '%s' -> %d résultats (normal: %d, initiales: vérifiées, fuzzy: vérifiée)\n", 
-		query, len(sb.suggestions), len(normalResults))
+}
+
+// updateQueryHelp affiche l'erreur de syntaxe éventuelle et les noms de
+// champs complétant le dernier mot saisi
+func (sb *SearchBar) updateQueryHelp(query string, err error) {
+	var syntaxErr *services.QuerySyntaxError
+	if errors.As(err, &syntaxErr) {
+		message := "⚠️ " + syntaxErr.Error()
+		if len(syntaxErr.Suggestions) > 0 {
+			message += " — essayez " + strings.Join(syntaxErr.Suggestions, ", ")
+		}
+		sb.queryStatus.SetText(message)
+		sb.queryStatus.Show()
+	} else {
+		sb.queryStatus.Hide()
+	}
+
+	sb.completions.Objects = nil
+	for _, completion := range services.CompleteQueryField(query) {
+		btn := widget.NewButton(completion, func() {
+			text := services.ApplyQueryCompletion(sb.entry.Text, completion)
+			sb.entry.SetText(text)
+			sb.entry.CursorColumn = len([]rune(text))
+			sb.entry.Refresh()
+		})
+		btn.Importance = widget.LowImportance
+		sb.completions.Add(btn)
+	}
+	if len(sb.completions.Objects) > 0 {
+		sb.completions.Show()
+	} else {
+		sb.completions.Hide()
+	}
+	sb.completions.Refresh()
 }
 
 // showHistorySuggestions affiche les suggestions de l'historique
@@ -242,6 +330,8 @@ func (sb *SearchBar) showHistorySuggestions() {
 	
 	// Convertir l'historique en suggestions
 	sb.suggestions = []services.SearchResult{}
+	sb.explanations = nil
+	sb.corrections = nil
 	
 	for _, entry := range recent {
 		sb.suggestions = append(sb.suggestions, services.SearchResult{
@@ -272,15 +362,30 @@ func (sb *SearchBar) hideSuggestions() {
 		sb.suggestionsVisible = false
 	}
 	sb.suggestions = []services.SearchResult{}
+	sb.explanations = nil
+	sb.corrections = nil
 	sb.suggestionList.Refresh()
 }
 
+// correctionAt retourne la correction affichée à la ligne id, ou nil
+func (sb *SearchBar) correctionAt(id widget.ListItemID) *services.Correction {
+	if id < 0 || id >= len(sb.corrections) {
+		return nil
+	}
+	return sb.corrections[id]
+}
+
 // Clear vide la barre de recherche
 func (sb *SearchBar) Clear() {
 	sb.entry.SetText("")
 	sb.hideSuggestions()
 }
 
+// Close arrête la recherche en arrière-plan
+func (sb *SearchBar) Close() {
+	sb.worker.Close()
+}
+
 // Focus met le focus sur la barre
 func (sb *SearchBar) Focus() {
 	sb.entry.FocusGained()
@@ -294,4 +399,9 @@ func (sb *SearchBar) SetPlaceholder(text string) {
 // GetSearchHistory retourne l'historique (pour affichage externe)
 func (sb *SearchBar) GetSearchHistory() *services.SearchHistory {
 	return sb.searchHistory
+}
+
+// GetAliases retourne le dictionnaire d'alias de recherche de l'utilisateur
+func (sb *SearchBar) GetAliases() *services.AliasDictionary {
+	return sb.aliases
 }
\ No newline at end of file
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"groupie-tracker/services"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// WhatsNewView affiche l'historique des changements du jeu de données
+// (artistes, lieux et dates ajoutés ou retirés entre deux actualisations)
+type WhatsNewView struct {
+	Container      fyne.CanvasObject
+	history        *services.DatasetHistory
+	onSelectArtist func(int)
+	onBack         func()
+}
+
+// NewWhatsNewView crée la vue "Nouveautés"
+func NewWhatsNewView(history *services.DatasetHistory, onSelectArtist func(int), onBack func()) *WhatsNewView {
+	view := &WhatsNewView{
+		history:        history,
+		onSelectArtist: onSelectArtist,
+		onBack:         onBack,
+	}
+
+	view.Container = view.buildUI()
+	return view
+}
+
+func (v *WhatsNewView) buildUI() fyne.CanvasObject {
+	title := widget.NewLabelWithStyle(
+		"🆕 Nouveautés",
+		fyne.TextAlignCenter,
+		fyne.TextStyle{Bold: true},
+	)
+
+	backBtn := widget.NewButton("← Retour", func() {
+		if v.onBack != nil {
+			v.onBack()
+		}
+	})
+
+	header := container.NewVBox(backBtn, widget.NewSeparator(), title, widget.NewSeparator())
+
+	diffs, err := v.history.Diffs()
+	if err != nil {
+		return container.NewBorder(header, nil, nil, nil,
+			NewErrorView("Historique des changements illisible", err, nil, v.onBack))
+	}
+
+	if len(diffs) == 0 {
+		emptyMsg := container.NewVBox(
+			widget.NewLabel(""),
+			widget.NewLabelWithStyle(
+				"📭 Aucun changement détecté pour l'instant",
+				fyne.TextAlignCenter,
+				fyne.TextStyle{Bold: true},
+			),
+			widget.NewLabel("Les ajouts d'artistes, de villes et de dates apparaîtront ici après chaque actualisation des données."),
+		)
+		return container.NewBorder(header, nil, nil, nil, container.NewCenter(emptyMsg))
+	}
+
+	cards := container.NewVBox()
+	for _, diff := range diffs {
+		cards.Add(v.createDiffCard(diff))
+	}
+
+	return container.NewBorder(header, nil, nil, nil, container.NewVScroll(cards))
+}
+
+// createDiffCard crée la carte d'une actualisation
+func (v *WhatsNewView) createDiffCard(diff services.DatasetDiff) fyne.CanvasObject {
+	content := container.NewVBox()
+
+	if len(diff.Artists) > 0 {
+		content.Add(widget.NewLabelWithStyle("🎤 Artistes", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, change := range diff.Artists {
+			text := fmt.Sprintf("%s %s", changeIcon(change.Kind), change.ArtistName)
+			if len(change.Fields) > 0 {
+				text += " (" + strings.Join(change.Fields, ", ") + ")"
+			}
+			content.Add(v.artistLine(text, change.ArtistID, change.Kind))
+		}
+	}
+
+	if len(diff.Locations) > 0 {
+		content.Add(widget.NewLabelWithStyle("📍 Lieux", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, change := range diff.Locations {
+			place := services.ParsePlace(change.Location)
+			text := fmt.Sprintf("%s %s : %s", changeIcon(change.Kind), change.ArtistName, place)
+			content.Add(v.artistLine(text, change.ArtistID, change.Kind))
+		}
+	}
+
+	if len(diff.Dates) > 0 {
+		content.Add(widget.NewLabelWithStyle("📅 Dates", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, change := range diff.Dates {
+			place := services.ParsePlace(change.Location)
+			text := fmt.Sprintf("%s %s : %s à %s", changeIcon(change.Kind), change.ArtistName, services.FormatDate(change.Date), place)
+			content.Add(v.artistLine(text, change.ArtistID, change.Kind))
+		}
+	}
+
+	return widget.NewCard(
+		diff.To.Local().Format("📅 02/01/2006 15:04"),
+		diff.Summary(),
+		content,
+	)
+}
+
+// artistLine crée une ligne cliquable vers l'artiste (simple texte s'il a été retiré)
+func (v *WhatsNewView) artistLine(text string, artistID int, kind services.ChangeKind) fyne.CanvasObject {
+	if kind == services.ChangeRemoved || v.onSelectArtist == nil {
+		return widget.NewLabel(text)
+	}
+
+	btn := widget.NewButton(text, func() { v.onSelectArtist(artistID) })
+	btn.Alignment = widget.ButtonAlignLeading
+	btn.Importance = widget.LowImportance
+	return btn
+}
+
+func changeIcon(kind services.ChangeKind) string {
+	switch kind {
+	case services.ChangeAdded:
+		return "➕"
+	case services.ChangeRemoved:
+		return "➖"
+	}
+	return "✏️"
+}